@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/collector"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// assertPermissionsCmd implements the "assert-permissions" command: a CI
+// assertion that a principal can do nothing beyond a declared allowlist
+func assertPermissionsCmd() *cobra.Command {
+	var (
+		principalARN       string
+		allowedActionsFile string
+		dataFile           string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "assert-permissions",
+		Short: "Fail if a principal can do anything beyond a declared allowlist",
+		Long: `Assert that a principal's effective permissions stay within a declared
+allowlist, and exit non-zero if anything unexpected is found.
+
+The allowlist file is a JSON array of allowed action/resource entries:
+
+  [
+    {"action": "s3:GetObject", "resource": "arn:aws:s3:::app-data/*"},
+    {"action": "sqs:SendMessage", "resource": "arn:aws:sqs:*:*:app-*"}
+  ]
+
+Action and resource support IAM-style wildcards; an omitted resource matches
+any resource. Effective permissions are computed with the full evaluation
+(SCPs, permission boundaries, explicit denies, conditions), so this enforces
+what the principal can actually do, not just what its policies say.`,
+		Example: `  # Enforce a policy contract in CI
+  aws-access-map assert-permissions \
+    --principal arn:aws:iam::123456789012:role/AppRole \
+    --allowed-actions-file expected.json
+
+  # Run offline against previously collected data
+  aws-access-map assert-permissions \
+    --principal arn:aws:iam::123456789012:role/AppRole \
+    --allowed-actions-file expected.json \
+    --data collected.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+			}
+			if err := validateQueryARN("principal", principalARN); err != nil {
+				return err
+			}
+
+			allowed, err := loadAllowedActionsFile(allowedActionsFile)
+			if err != nil {
+				return err
+			}
+
+			var result *types.CollectionResult
+			if dataFile != "" {
+				result, err = simulation.LoadFromFile(dataFile)
+				if err != nil {
+					return fmt.Errorf("failed to load data: %w", err)
+				}
+			} else {
+				if err := ensureOnline(); err != nil {
+					return err
+				}
+				ctx := context.Background()
+				col, err := collector.New(ctx, region, profile, debug, false)
+				if err != nil {
+					return fmt.Errorf("failed to create collector: %w", err)
+				}
+				fmt.Fprintln(os.Stderr, "Collecting AWS data...")
+				result, err = col.Collect(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to collect data: %w", err)
+				}
+			}
+
+			g, err := graph.Build(result)
+			if err != nil {
+				return fmt.Errorf("failed to build graph: %w", err)
+			}
+
+			engine := query.New(g).WithContext(buildEvaluationContext(result))
+			unexpected, err := engine.UnexpectedPermissions(principalARN, allowed)
+			if err != nil {
+				return fmt.Errorf("failed to compute effective permissions: %w", err)
+			}
+
+			if format == "json" {
+				out := struct {
+					Principal  string             `json:"principal"`
+					Passed     bool               `json:"passed"`
+					Unexpected []query.Capability `json:"unexpected,omitempty"`
+				}{
+					Principal:  principalARN,
+					Passed:     len(unexpected) == 0,
+					Unexpected: unexpected,
+				}
+				data, err := json.MarshalIndent(out, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal output: %w", err)
+				}
+				fmt.Println(string(data))
+			} else {
+				if len(unexpected) == 0 {
+					fmt.Printf("✅ %s has no permissions beyond the declared allowlist\n", principalARN)
+				} else {
+					fmt.Printf("❌ %s has %d capability(ies) beyond the declared allowlist:\n", principalARN, len(unexpected))
+					for _, capability := range unexpected {
+						fmt.Printf("  - %s on %s\n", capability.Action, capability.ResourceARN)
+					}
+				}
+			}
+
+			if len(unexpected) > 0 {
+				os.Exit(1) // Non-zero exit for CI/CD
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&principalARN, "principal", "", "Principal ARN to assert on")
+	cmd.Flags().StringVar(&allowedActionsFile, "allowed-actions-file", "", "JSON file declaring the allowed action/resource entries")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); collects live when omitted")
+
+	_ = cmd.MarkFlagRequired("principal")
+	_ = cmd.MarkFlagRequired("allowed-actions-file")
+
+	return cmd
+}
+
+// loadAllowedActionsFile parses a JSON allowlist of action/resource entries
+func loadAllowedActionsFile(path string) ([]query.AllowedAction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowed actions file: %w", err)
+	}
+
+	var allowed []query.AllowedAction
+	if err := json.Unmarshal(data, &allowed); err != nil {
+		return nil, fmt.Errorf("failed to parse allowed actions file: %w", err)
+	}
+
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("allowed actions file %s contains no entries", path)
+	}
+	for i, entry := range allowed {
+		if entry.Action == "" {
+			return nil, fmt.Errorf("allowed actions file %s: entry %d has no action", path, i)
+		}
+	}
+
+	return allowed, nil
+}
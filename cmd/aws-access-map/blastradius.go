@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/collector"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// blastRadiusCmd implements the "blast-radius" command: everything a
+// principal could reach if its credentials leaked
+func blastRadiusCmd() *cobra.Command {
+	var (
+		principalARN string
+		dataFile     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "blast-radius",
+		Short: "Show every resource a compromised principal could reach",
+		Long: `Compute the full reachable resource set for a principal, including
+transitive assume-role chains - the incident-response answer to "if this
+credential leaked, what's exposed".
+
+Every role the principal can assume (directly or through a chain) contributes
+its effective permissions; SCPs, boundaries, and explicit denies still apply.
+Results are grouped by service.`,
+		Example: `  aws-access-map blast-radius --principal arn:aws:iam::123456789012:user/alice
+  aws-access-map blast-radius --principal arn:aws:iam::123456789012:role/ci --data collected.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+			}
+			if err := validateQueryARN("principal", principalARN); err != nil {
+				return err
+			}
+
+			var result *types.CollectionResult
+			var err error
+			if dataFile != "" {
+				result, err = simulation.LoadFromFile(dataFile)
+				if err != nil {
+					return fmt.Errorf("failed to load data: %w", err)
+				}
+			} else {
+				if err := ensureOnline(); err != nil {
+					return err
+				}
+				ctx := context.Background()
+				col, err := collector.New(ctx, region, profile, debug, false)
+				if err != nil {
+					return fmt.Errorf("failed to create collector: %w", err)
+				}
+				fmt.Fprintln(os.Stderr, "Collecting AWS data...")
+				result, err = col.Collect(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to collect data: %w", err)
+				}
+			}
+
+			g, err := graph.Build(result)
+			if err != nil {
+				return fmt.Errorf("failed to build graph: %w", err)
+			}
+
+			evalCtx := buildEvaluationContext(result)
+			radius, err := query.New(g).WithContext(evalCtx).BlastRadius(principalARN)
+			if err != nil {
+				return err
+			}
+
+			if format == "json" {
+				data, err := json.MarshalIndent(radius, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal output: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("Blast radius for %s\n", radius.PrincipalARN)
+			if len(radius.Identities) > 1 {
+				fmt.Printf("Includes %d transitively assumable role(s):\n", len(radius.Identities)-1)
+				for _, identity := range radius.Identities[1:] {
+					fmt.Printf("  %s\n", identity)
+				}
+			}
+			fmt.Println()
+
+			if len(radius.Resources) == 0 {
+				fmt.Println("No reachable resources.")
+				return nil
+			}
+
+			// Group by service, preserving the sorted order from BlastRadius
+			currentService := ""
+			for _, resource := range radius.Resources {
+				if resource.Service != currentService {
+					if currentService != "" {
+						fmt.Println()
+					}
+					currentService = resource.Service
+					fmt.Printf("%s:\n", currentService)
+				}
+				fmt.Printf("  %s\n", resource.ResourceARN)
+				fmt.Printf("    actions: %s\n", strings.Join(resource.Actions, ", "))
+			}
+			fmt.Printf("\nTotal: %d reachable resource(s)\n", len(radius.Resources))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&principalARN, "principal", "", "Principal ARN to analyze")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); collects live when omitted")
+
+	_ = cmd.MarkFlagRequired("principal")
+
+	return cmd
+}
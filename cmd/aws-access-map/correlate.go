@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/collector"
+	"github.com/pfrederiksen/aws-access-map/internal/findings"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// correlateCmd implements the "correlate" command: enrich external scanner
+// findings (Prowler) with reachability context from the access graph
+func correlateCmd() *cobra.Command {
+	var (
+		findingsFile string
+		dataFile     string
+		failedOnly   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "correlate",
+		Short: "Correlate Prowler findings with the access graph",
+		Long: `Import a Prowler JSON report and match its findings against the access
+graph. Findings about IAM principals gain a blast radius ("Prowler flagged
+this role - here's everything it can reach"); findings about resources gain
+the list of principals holding grants on them. Findings about services this
+tool does not collect pass through unmatched.
+
+The importer reads Prowler's native JSON array output (prowler -M json).`,
+		Example: `  aws-access-map correlate --findings prowler-output.json --data collected.json
+  aws-access-map correlate --findings prowler-output.json --failed-only --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+			}
+
+			items, err := findings.LoadProwler(findingsFile)
+			if err != nil {
+				return err
+			}
+			if failedOnly {
+				var failed []findings.Finding
+				for _, f := range items {
+					if strings.EqualFold(f.Status, "FAIL") {
+						failed = append(failed, f)
+					}
+				}
+				items = failed
+			}
+
+			var result *types.CollectionResult
+			if dataFile != "" {
+				result, err = simulation.LoadFromFile(dataFile)
+				if err != nil {
+					return fmt.Errorf("failed to load data: %w", err)
+				}
+			} else {
+				if err := ensureOnline(); err != nil {
+					return err
+				}
+				ctx := context.Background()
+				col, err := collector.New(ctx, region, profile, debug, false)
+				if err != nil {
+					return fmt.Errorf("failed to create collector: %w", err)
+				}
+				fmt.Fprintln(os.Stderr, "Collecting AWS data...")
+				result, err = col.Collect(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to collect data: %w", err)
+				}
+			}
+
+			g, err := graph.Build(result)
+			if err != nil {
+				return fmt.Errorf("failed to build graph: %w", err)
+			}
+
+			evalCtx := buildEvaluationContext(result)
+			correlated := query.New(g).WithContext(evalCtx).CorrelateFindings(items)
+
+			if format == "json" {
+				data, err := json.MarshalIndent(correlated, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal output: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			matched := 0
+			for _, c := range correlated {
+				if c.PrincipalARN == "" && c.ResourceARN == "" {
+					continue
+				}
+				matched++
+
+				fmt.Printf("[%s] %s: %s\n", strings.ToUpper(c.Finding.Severity), c.Finding.CheckID, c.Finding.CheckTitle)
+				if c.Finding.Description != "" {
+					fmt.Printf("  %s\n", c.Finding.Description)
+				}
+				if c.PrincipalARN != "" {
+					fmt.Printf("  Principal: %s\n", c.PrincipalARN)
+					if c.BlastRadius != nil {
+						if len(c.BlastRadius.Identities) > 1 {
+							fmt.Printf("  Can assume %d role(s) transitively\n", len(c.BlastRadius.Identities)-1)
+						}
+						fmt.Printf("  Can reach %d resource(s)\n", len(c.BlastRadius.Resources))
+					}
+				}
+				if c.ResourceARN != "" {
+					fmt.Printf("  Resource: %s\n", c.ResourceARN)
+					fmt.Printf("  %d principal(s) hold grants on it\n", len(c.GrantedPrincipals))
+					for _, arn := range c.GrantedPrincipals {
+						fmt.Printf("    %s\n", arn)
+					}
+				}
+				fmt.Println()
+			}
+
+			fmt.Printf("Correlated %d of %d finding(s) with the access graph\n", matched, len(correlated))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&findingsFile, "findings", "", "Prowler JSON report to import")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); collects live when omitted")
+	cmd.Flags().BoolVar(&failedOnly, "failed-only", false, "Only correlate findings with status FAIL")
+
+	_ = cmd.MarkFlagRequired("findings")
+
+	return cmd
+}
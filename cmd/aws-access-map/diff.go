@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/cache"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+)
+
+// diffCmd implements the "diff" command: a structural comparison of two
+// collected datasets, independent of any specific resource or action
+func diffCmd() *cobra.Command {
+	var beforeFile string
+	var afterFile string
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show structural changes between two collected datasets",
+		Long: `Compare two collection files and report added, removed and modified
+principals and resources. A principal counts as modified when any of its
+policies, trust policy or permissions boundary changed; a resource when its
+resource policy changed.
+
+For the access-level view of a change (who gained or lost what), see
+"simulate diff" and "diff-permissions".`,
+		Example: `  aws-access-map diff --before monday.json --after friday.json
+  aws-access-map diff --before monday.json --after friday.json --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+			}
+
+			before, err := simulation.LoadFromFile(beforeFile)
+			if err != nil {
+				return fmt.Errorf("failed to load before data: %w", err)
+			}
+			after, err := simulation.LoadFromFile(afterFile)
+			if err != nil {
+				return fmt.Errorf("failed to load after data: %w", err)
+			}
+
+			changes := cache.DetectChanges(cache.ExtractMetadata(before), cache.ExtractMetadata(after))
+
+			// Map iteration makes the raw lists unordered; sort for stable output
+			for _, arns := range [][]string{
+				changes.Added, changes.Removed, changes.Modified,
+				changes.PrincipalsAdded, changes.PrincipalsRemoved, changes.PrincipalsModified,
+			} {
+				sort.Strings(arns)
+			}
+
+			if format == "json" {
+				data, err := json.MarshalIndent(changes, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal output: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			if !changes.HasChanges() {
+				fmt.Println("No structural changes detected")
+				return nil
+			}
+
+			printDiffSection("Principals added", changes.PrincipalsAdded)
+			printDiffSection("Principals removed", changes.PrincipalsRemoved)
+			printDiffSection("Principals modified", changes.PrincipalsModified)
+			printDiffSection("Resources added", changes.Added)
+			printDiffSection("Resources removed", changes.Removed)
+			printDiffSection("Resources modified", changes.Modified)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&beforeFile, "before", "", "Baseline collection file (JSON)")
+	cmd.Flags().StringVar(&afterFile, "after", "", "Newer collection file (JSON)")
+
+	_ = cmd.MarkFlagRequired("before")
+	_ = cmd.MarkFlagRequired("after")
+
+	return cmd
+}
+
+// printDiffSection renders one changeset bucket, skipping empty ones
+func printDiffSection(title string, arns []string) {
+	if len(arns) == 0 {
+		return
+	}
+	fmt.Printf("%s (%d):\n", title, len(arns))
+	for _, arn := range arns {
+		fmt.Printf("  %s\n", arn)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+)
+
+// diffPermissionsCmd implements the "diff-permissions" command: a compact
+// diff of one principal's effective permission set between two datasets
+func diffPermissionsCmd() *cobra.Command {
+	var (
+		beforeFile   string
+		afterFile    string
+		principalARN string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff-permissions",
+		Short: "Diff a principal's effective permissions between two datasets",
+		Long: `Compute how a principal's effective permission set (action/resource
+grants) changed between two collected datasets, e.g. cache snapshots taken
+before and after a deployment.
+
+Unlike 'simulate diff', which compares who-can results per resource, this
+shows the added and removed capabilities of one principal across the whole
+graph - answering "how did this role's capabilities change" for change
+review.`,
+		Example: `  aws-access-map diff-permissions \
+    --before monday.json --after friday.json \
+    --principal arn:aws:iam::123456789012:role/AppRole`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+			}
+			if err := validateQueryARN("principal", principalARN); err != nil {
+				return err
+			}
+
+			evalCtx := buildEvaluationContext()
+
+			capabilitiesFor := func(path string) ([]query.Capability, error) {
+				result, err := simulation.LoadFromFile(path)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load %s: %w", path, err)
+				}
+				g, err := graph.Build(result)
+				if err != nil {
+					return nil, fmt.Errorf("failed to build graph from %s: %w", path, err)
+				}
+				return query.New(g).WithContext(evalCtx).EffectivePermissions(principalARN)
+			}
+
+			before, err := capabilitiesFor(beforeFile)
+			if err != nil {
+				return err
+			}
+			after, err := capabilitiesFor(afterFile)
+			if err != nil {
+				return err
+			}
+
+			added, removed := query.DiffCapabilities(before, after)
+
+			if format == "json" {
+				out := struct {
+					Principal string             `json:"principal"`
+					Added     []query.Capability `json:"added"`
+					Removed   []query.Capability `json:"removed"`
+				}{principalARN, added, removed}
+				data, err := json.MarshalIndent(out, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal output: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("Permission changes for %s:\n\n", principalARN)
+			if len(added) == 0 && len(removed) == 0 {
+				fmt.Println("No changes in effective permissions")
+				return nil
+			}
+			if len(added) > 0 {
+				fmt.Printf("Added (%d):\n", len(added))
+				for _, capability := range added {
+					fmt.Printf("  + %s on %s\n", capability.Action, capability.ResourceARN)
+				}
+			}
+			if len(removed) > 0 {
+				if len(added) > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("Removed (%d):\n", len(removed))
+				for _, capability := range removed {
+					fmt.Printf("  - %s on %s\n", capability.Action, capability.ResourceARN)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&beforeFile, "before", "", "Baseline dataset file (JSON)")
+	cmd.Flags().StringVar(&afterFile, "after", "", "Updated dataset file (JSON)")
+	cmd.Flags().StringVar(&principalARN, "principal", "", "Principal ARN to diff")
+
+	_ = cmd.MarkFlagRequired("before")
+	_ = cmd.MarkFlagRequired("after")
+	_ = cmd.MarkFlagRequired("principal")
+
+	return cmd
+}
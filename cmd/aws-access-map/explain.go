@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+)
+
+// explainCmd implements the "explain" command: a step-by-step trace of why
+// access is or is not allowed, instead of the bare yes/no from who-can/path
+func explainCmd() *cobra.Command {
+	var (
+		principal string
+		action    string
+		resource  string
+		dataFile  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Show step by step why access is or isn't allowed",
+		Long: `Trace the policy evaluation for one principal, action and resource in the
+order AWS applies it: SCPs, RCPs, permission boundary, session policy,
+explicit denies (direct and via groups), then explicit allows. Each step
+names the statement SID that drove the decision where one is known, so a
+surprising verdict can be traced to the exact policy statement.`,
+		Example: `  aws-access-map explain \
+    --principal arn:aws:iam::123456789012:user/alice \
+    --action s3:GetObject \
+    --resource arn:aws:s3:::sensitive-bucket/file.txt
+
+  # Against a previously collected data file, as JSON
+  aws-access-map explain --principal arn:aws:iam::123456789012:role/app \
+    --action kms:Decrypt --resource arn:aws:kms:us-east-1:123456789012:key/abc \
+    --data access-data.json --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateQueryARN("principal", principal); err != nil {
+				return err
+			}
+			if err := validateQueryARN("resource", resource); err != nil {
+				return err
+			}
+			return runExplain(principal, action, resource, dataFile)
+		},
+	}
+
+	cmd.Flags().StringVar(&principal, "principal", "", "Principal ARN to evaluate")
+	cmd.Flags().StringVar(&action, "action", "", "AWS action to check")
+	cmd.Flags().StringVar(&resource, "resource", "", "Resource ARN or identifier")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); skips collection entirely")
+	_ = cmd.MarkFlagRequired("principal")
+	_ = cmd.MarkFlagRequired("action")
+	_ = cmd.MarkFlagRequired("resource")
+
+	return cmd
+}
+
+func runExplain(principal, action, resource, dataFile string) error {
+	start := time.Now()
+
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+	}
+
+	ctx := context.Background()
+
+	// Send progress messages to stderr when stdout carries machine-readable output
+	logOutput := os.Stdout
+	if format == "json" {
+		logOutput = os.Stderr
+	}
+
+	result, err := queryCollectionResult(ctx, dataFile, logOutput)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(logOutput, "Building access graph...")
+	g, err := graph.Build(result)
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	evalCtx := buildEvaluationContext(result)
+
+	// Expand a friendly --resource name like "my-bucket" to the full ARN
+	resolved, err := query.New(g).ResolveResource(resource)
+	if err != nil {
+		return err
+	}
+	if resolved != resource {
+		fmt.Fprintf(logOutput, "Resolved '%s' to '%s'\n", resource, resolved)
+		resource = resolved
+	}
+
+	explanation := g.Explain(principal, action, resource, evalCtx)
+
+	verdict := "DENIED"
+	if explanation.Allowed {
+		verdict = "ALLOWED"
+	}
+	defer printQuerySummary(start, fmt.Sprintf("Access %s after %d step(s)", verdict, len(explanation.Steps)))
+
+	if format == "json" {
+		data, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Can %s perform %s on %s?\n\n", principal, action, resource)
+	for i, step := range explanation.Steps {
+		fmt.Printf("%d. [%s] %s: %s\n", i+1, step.Outcome, step.Stage, step.Detail)
+		if step.Sid != "" {
+			fmt.Printf("   Statement: %s\n", step.Sid)
+		}
+		if step.ViaGroup != "" {
+			fmt.Printf("   Via group: %s\n", step.ViaGroup)
+		}
+	}
+	fmt.Printf("\nResult: %s\n", verdict)
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/collector"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/output"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// exportCmd implements the "export" command: render the access graph in
+// formats consumed by external visualization tools
+func exportCmd() *cobra.Command {
+	var (
+		exportFormat string
+		dataFile     string
+		outputFile   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the access graph for external visualization tools",
+		Long: `Export the access graph as structured data for visualization.
+
+Formats:
+  cytoscape   Cytoscape.js element JSON (nodes + edges with action/severity
+              metadata) for browser-based interactive graph explorers
+  graph-json  Normalized node/edge document with typed edges (PERMISSION,
+              TRUST, MEMBER_OF, DELIVERS_TO) for property-graph databases
+              like Neo4j or Neptune
+  prometheus  Posture metrics (principal/resource counts, admin principals,
+              public resources, findings by severity) in Prometheus text
+              exposition format, for scheduled collection feeding dashboards
+
+Principals and resources become nodes; permission, deny, and trust edges
+carry the action, granting policy, and a severity classification.`,
+		Example: `  # Export collected data for a web dashboard
+  aws-access-map export --format cytoscape --data collected.json -o graph.json
+
+  # Collect live and print to stdout
+  aws-access-map export --format cytoscape
+
+  # Write metrics for the Prometheus node_exporter textfile collector
+  aws-access-map export --format prometheus --data collected.json -o access.prom
+
+  # Export for a Neo4j/Neptune import
+  aws-access-map export --format graph-json --data collected.json -o graph.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if exportFormat != "cytoscape" && exportFormat != "prometheus" && exportFormat != "graph-json" {
+				return fmt.Errorf("invalid export format: %s (must be 'cytoscape', 'graph-json', or 'prometheus')", exportFormat)
+			}
+
+			var result *types.CollectionResult
+			var err error
+			if dataFile != "" {
+				result, err = simulation.LoadFromFile(dataFile)
+				if err != nil {
+					return fmt.Errorf("failed to load data: %w", err)
+				}
+			} else {
+				if err := ensureOnline(); err != nil {
+					return err
+				}
+				ctx := context.Background()
+				col, err := collector.New(ctx, region, profile, debug, false)
+				if err != nil {
+					return fmt.Errorf("failed to create collector: %w", err)
+				}
+				fmt.Fprintln(os.Stderr, "Collecting AWS data...")
+				result, err = col.Collect(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to collect data: %w", err)
+				}
+			}
+
+			g, err := graph.Build(result)
+			if err != nil {
+				return fmt.Errorf("failed to build graph: %w", err)
+			}
+
+			out := os.Stdout
+			if outputFile != "" {
+				f, err := os.Create(outputFile)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			switch exportFormat {
+			case "prometheus":
+				// Metrics include findings by severity, so run the analysis
+				evalCtx := buildEvaluationContext(result)
+				findings, err := query.New(g).WithContext(evalCtx).FindHighRiskAccess()
+				if err != nil {
+					return fmt.Errorf("analysis failed: %w", err)
+				}
+				if err := output.PrintPrometheus(g, result.AccountID, findings, out); err != nil {
+					return err
+				}
+			case "graph-json":
+				if err := output.PrintGraphJSON(g, out); err != nil {
+					return err
+				}
+			default:
+				if err := output.PrintCytoscape(g, out); err != nil {
+					return err
+				}
+			}
+			if outputFile != "" {
+				fmt.Fprintf(os.Stderr, "Graph exported to: %s\n", outputFile)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&exportFormat, "format", "cytoscape", "Export format (cytoscape|graph-json|prometheus)")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); collects live when omitted")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file (defaults to stdout)")
+
+	return cmd
+}
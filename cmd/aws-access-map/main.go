@@ -2,17 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/arn"
 	"github.com/pfrederiksen/aws-access-map/internal/cache"
 	"github.com/pfrederiksen/aws-access-map/internal/collector"
 	"github.com/pfrederiksen/aws-access-map/internal/graph"
 	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
 	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
 	"github.com/pfrederiksen/aws-access-map/pkg/output"
 	"github.com/pfrederiksen/aws-access-map/pkg/types"
 )
@@ -37,6 +45,24 @@ var (
 	mfa         bool
 	orgID       string
 	principalArn string
+	sourceArn   string
+	requestTags []string
+	principalTags []string
+	requestTime string
+	secureTransport bool
+
+	// Output flags
+	echoContext bool
+	quiet       bool
+
+	// Input validation flags
+	strictARN bool
+
+	// Safe mode: never touch AWS
+	offline bool
+
+	// Custom output template file
+	templateFile string
 )
 
 func main() {
@@ -51,7 +77,7 @@ to answer access questions about your AWS infrastructure.`,
 	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "AWS profile to use")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Enable debug logging")
 	rootCmd.PersistentFlags().StringVar(&region, "region", "", "AWS region (defaults to profile region)")
-	rootCmd.PersistentFlags().StringVar(&format, "format", "text", "Output format (text|json)")
+	rootCmd.PersistentFlags().StringVar(&format, "format", "text", "Output format (text|json; who-can also accepts ndjson, report and simulate diff/validate also accept markdown, path accepts dot)")
 
 	// Cache flags
 	rootCmd.PersistentFlags().BoolVar(&useCache, "cache", false, "Force use cached data (fail if cache missing or stale)")
@@ -63,6 +89,16 @@ to answer access questions about your AWS infrastructure.`,
 	rootCmd.PersistentFlags().BoolVar(&mfa, "mfa", false, "Assume MFA is authenticated")
 	rootCmd.PersistentFlags().StringVar(&orgID, "org-id", "", "Principal organization ID (e.g., o-123456)")
 	rootCmd.PersistentFlags().StringVar(&principalArn, "principal-arn", "", "Principal ARN for condition evaluation")
+	rootCmd.PersistentFlags().StringVar(&sourceArn, "source-arn", "", "Calling resource ARN for cross-service condition evaluation (aws:SourceArn, e.g. an SNS topic delivering to SQS)")
+	rootCmd.PersistentFlags().StringArrayVar(&requestTags, "request-tag", nil, "Request tag for condition evaluation as key=value (repeatable, e.g. --request-tag CostCenter=123)")
+	rootCmd.PersistentFlags().StringArrayVar(&principalTags, "principal-tag", nil, "Principal tag for condition evaluation as key=value (repeatable, e.g. --principal-tag team=payments)")
+	rootCmd.PersistentFlags().StringVar(&requestTime, "request-time", "", "Request time for date condition evaluation (RFC3339 or epoch seconds; defaults to now)")
+	rootCmd.PersistentFlags().BoolVar(&secureTransport, "secure-transport", true, "Whether the request uses HTTPS; set =false to test deny-on-insecure-transport statements")
+	rootCmd.PersistentFlags().BoolVar(&echoContext, "echo-context", false, "Include the resolved evaluation context in query output (for reproducibility)")
+	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Suppress the one-line result summary printed to stderr")
+	rootCmd.PersistentFlags().BoolVar(&strictARN, "strict-arn", false, "Reject malformed ARNs in query inputs instead of warning")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Never call AWS; commands that need live collection fail unless given a data file")
+	rootCmd.PersistentFlags().StringVar(&templateFile, "template", "", "Render output through a Go text/template file (overrides --format)")
 
 	// Add commands
 	rootCmd.AddCommand(versionCmd())
@@ -72,6 +108,18 @@ to answer access questions about your AWS infrastructure.`,
 	rootCmd.AddCommand(reportCmd())
 	rootCmd.AddCommand(cacheCmd())
 	rootCmd.AddCommand(simulateCmd())
+	rootCmd.AddCommand(assertPermissionsCmd())
+	rootCmd.AddCommand(exportCmd())
+	rootCmd.AddCommand(diffPermissionsCmd())
+	rootCmd.AddCommand(withPolicyCmd())
+	rootCmd.AddCommand(blastRadiusCmd())
+	rootCmd.AddCommand(suggestSCPCmd())
+	rootCmd.AddCommand(correlateCmd())
+	rootCmd.AddCommand(mergeCmd())
+	rootCmd.AddCommand(explainCmd())
+	rootCmd.AddCommand(whoamiCmd())
+	rootCmd.AddCommand(statsCmd())
+	rootCmd.AddCommand(diffCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -79,8 +127,47 @@ to answer access questions about your AWS infrastructure.`,
 	}
 }
 
-// buildEvaluationContext creates an evaluation context from CLI flags
-func buildEvaluationContext() *conditions.EvaluationContext {
+// ensureOnline fails fast when --offline is set and a command is about to
+// collect live data from AWS. Commands that accept an offline data source
+// check their --data flag first, so only the live-collection paths call this.
+func ensureOnline() error {
+	if offline {
+		return fmt.Errorf("--offline is set: live AWS collection is disabled (provide --data where supported, or drop --offline)")
+	}
+	return nil
+}
+
+// validateQueryARN checks a query input that looks like an ARN. Under
+// --strict-arn malformed ARNs are rejected; otherwise they only warn, since
+// who-can also accepts shorthand identifiers like s3://my-bucket
+func validateQueryARN(label, value string) error {
+	if !strings.HasPrefix(value, "arn:") {
+		return nil
+	}
+	if err := arn.Validate(value); err != nil {
+		if strictARN {
+			return fmt.Errorf("invalid %s ARN %q: %w", label, value, err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %s ARN %q looks malformed: %v\n", label, value, err)
+	}
+	return nil
+}
+
+// printQuerySummary emits a one-line human summary to stderr after a query so
+// interactive users get result counts and timing even when stdout carries
+// JSON. Suppressed by --quiet.
+func printQuerySummary(start time.Time, summary string) {
+	if quiet {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s in %.1fs\n", summary, time.Since(start).Seconds())
+}
+
+// buildEvaluationContext creates an evaluation context from CLI flags. When a
+// collection result is supplied, fields derivable from collected data are
+// filled in too: currently aws:PrincipalOrgPaths from the OU hierarchy (the
+// organization prefix comes from --org-id)
+func buildEvaluationContext(result ...*types.CollectionResult) *conditions.EvaluationContext {
 	ctx := conditions.NewDefaultContext()
 
 	// Override defaults with CLI flags if provided
@@ -90,16 +177,60 @@ func buildEvaluationContext() *conditions.EvaluationContext {
 	if mfa {
 		ctx.MFAAuthenticated = true
 	}
+	ctx.SecureTransport = secureTransport
 	if orgID != "" {
 		ctx.PrincipalOrgID = orgID
 	}
 	if principalArn != "" {
 		ctx.PrincipalARN = principalArn
 	}
+	if sourceArn != "" {
+		ctx.SourceArn = sourceArn
+	}
+	for _, tag := range requestTags {
+		key, value, found := strings.Cut(tag, "=")
+		if !found || key == "" {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring malformed --request-tag %q (expected key=value)\n", tag)
+			continue
+		}
+		ctx.RequestTags[key] = value
+	}
+	for _, tag := range principalTags {
+		key, value, found := strings.Cut(tag, "=")
+		if !found || key == "" {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring malformed --principal-tag %q (expected key=value)\n", tag)
+			continue
+		}
+		ctx.PrincipalTags[key] = value
+	}
+	if requestTime != "" {
+		// Evaluate "would this be allowed at 2am UTC?" scenarios against
+		// DateLessThan-style conditions instead of the wall clock
+		if t, err := parseRequestTime(requestTime); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring invalid --request-time %q (expected RFC3339 or epoch seconds)\n", requestTime)
+		} else {
+			ctx.CurrentTime = t
+		}
+	}
+	if len(result) > 0 && result[0] != nil {
+		ctx.PrincipalOrgPath = conditions.OrgPathFromHierarchy(ctx.PrincipalOrgID, result[0].OUHierarchy)
+	}
 
 	return ctx
 }
 
+// parseRequestTime parses a --request-time value as RFC3339
+// (2026-01-15T02:00:00Z) or epoch seconds (1768442400)
+func parseRequestTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time format: %s", value)
+}
+
 func versionCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "version",
@@ -114,11 +245,26 @@ func versionCmd() *cobra.Command {
 
 func collectCmd() *cobra.Command {
 	var (
-		outputFile   string
-		includeSCPs  bool
-		allAccounts  bool
-		roleName     string
-		incremental  bool
+		outputFile     string
+		outputTemplate string
+		outputDir      string
+		includeSCPs    bool
+		allAccounts    bool
+		roleName       string
+		incremental    bool
+		iamOnly        bool
+		allVersions    bool
+		resume         bool
+		profiles       string
+		maxAPICalls    int
+		redactPolicies bool
+		concurrency    int
+		regions        string
+		legacyIAM      bool
+		maxRetries     int
+		retryBackoff   time.Duration
+		assumeRoleARN  string
+		externalID     string
 	)
 
 	cmd := &cobra.Command{
@@ -126,49 +272,204 @@ func collectCmd() *cobra.Command {
 		Short: "Collect IAM and resource policy data from AWS",
 		Long:  `Fetches IAM policies, resource policies, SCPs, and role trust policies from your AWS account or entire organization.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCollect(outputFile, includeSCPs, allAccounts, roleName, incremental)
+			return runCollect(outputFile, outputTemplate, outputDir, includeSCPs, allAccounts, roleName, incremental, iamOnly, allVersions, resume, profiles, maxAPICalls, redactPolicies, concurrency, regions, legacyIAM, assumeRoleARN, externalID, collector.RetryConfig{MaxRetries: maxRetries, Backoff: retryBackoff})
 		},
 	}
 
 	cmd.Flags().StringVarP(&outputFile, "output", "o", "aws-access-data.json", "Output file for collected data")
+	cmd.Flags().StringVar(&outputTemplate, "output-template", "", "Output filename template with {account}, {date} and {hash} placeholders (overrides --output, e.g. access-{account}-{date}.json)")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Write one <accountID>.json per account plus an index.json instead of a single file (only with --all-accounts)")
 	cmd.Flags().BoolVar(&includeSCPs, "include-scps", false, "Collect Service Control Policies from AWS Organizations (requires organizations:ListPolicies permission)")
 	cmd.Flags().BoolVar(&allAccounts, "all-accounts", false, "Collect from all accounts in the organization (requires Organizations access)")
 	cmd.Flags().StringVar(&roleName, "role-name", "OrganizationAccountAccessRole", "Role name to assume in member accounts (only with --all-accounts)")
 	cmd.Flags().BoolVar(&incremental, "incremental", false, "Use incremental caching (faster for large accounts with few changes)")
+	cmd.Flags().BoolVar(&iamOnly, "iam-only", false, "Collect only IAM users/roles/groups, skipping resource policies (fast identity-only analysis)")
+	cmd.Flags().BoolVar(&allVersions, "all-policy-versions", false, "Also collect historical versions of customer-managed policies (more API calls; enables change/forensic analysis)")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Resume an interrupted organization collection from its checkpoint (only with --all-accounts)")
+	cmd.Flags().StringVar(&profiles, "profiles", "", "Comma-separated AWS profiles to collect and merge into one dataset (e.g. dev,staging,prod)")
+	cmd.Flags().IntVar(&maxAPICalls, "max-api-calls", 0, "Stop collection after this many AWS API calls, keeping partial results (0 = unlimited)")
+	cmd.Flags().BoolVar(&redactPolicies, "redact-policies", false, "Strip policy statements from the output, keeping metadata and a per-document SHA-256 (for sharing sensitive datasets)")
+	cmd.Flags().IntVar(&concurrency, "resource-concurrency", 0, "How many per-service resource collectors run in parallel (0 = default of 4)")
+	cmd.Flags().StringVar(&regions, "regions", "", "Comma-separated regions to collect regional resources from (e.g. us-east-1,eu-west-1); global services are collected once")
+	cmd.Flags().BoolVar(&legacyIAM, "legacy-collection", false, "Collect IAM with per-principal List/Get calls instead of a single GetAccountAuthorizationDetails sweep (for callers without that permission)")
+	cmd.Flags().IntVar(&maxRetries, "max-retries", 0, "Maximum attempts per AWS API call when throttled (0 = default of 8)")
+	cmd.Flags().DurationVar(&retryBackoff, "retry-backoff", 0, "Upper bound on the exponential backoff between retry attempts (0 = default of 30s)")
+	cmd.Flags().StringVar(&assumeRoleARN, "assume-role-arn", "", "Role ARN to assume before collecting (cross-account audits; not with --all-accounts or --profiles)")
+	cmd.Flags().StringVar(&externalID, "external-id", "", "External ID to pass on sts:AssumeRole, for roles that require one (--assume-role-arn or --all-accounts)")
 
 	return cmd
 }
 
 func whoCanCmd() *cobra.Command {
 	var action string
+	var actionsFile string
+	var candidatesFile string
+	var withEvidence bool
+	var dataFile string
+	var failIfFound bool
+	var failIfNone bool
+	var typeFilter string
+	var accountFilter string
 
 	cmd := &cobra.Command{
 		Use:   "who-can <resource>",
 		Short: "Find all principals that can perform an action on a resource",
-		Long:  `Query which principals (users, roles, groups) can perform a specific action on a resource.`,
+		Long: `Query which principals (users, roles, groups) can perform a specific action on a resource.
+
+For CI gating, --fail-if-found exits with code 1 when any principal matches
+(assert nobody can do X) and --fail-if-none exits with code 1 when no principal
+matches (assert somebody still can). Results are printed normally in both text
+and JSON modes before the exit status is applied, mirroring the exit-code
+behavior of 'simulate validate'.`,
 		Example: `  aws-access-map who-can s3://my-bucket --action s3:GetObject
-  aws-access-map who-can arn:aws:kms:us-east-1:123456789012:key/abc --action kms:Decrypt`,
+  aws-access-map who-can my-function --action lambda:InvokeFunction
+  aws-access-map who-can arn:aws:kms:us-east-1:123456789012:key/abc --action kms:Decrypt
+  aws-access-map who-can "*" --actions-file privileged-actions.txt
+
+  # CI gate: fail the build if anyone can delete the audit bucket
+  aws-access-map who-can "arn:aws:s3:::audit-logs/*" --action s3:DeleteObject \
+    --data snapshot.json --fail-if-found`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			resource := args[0]
-			if action == "" {
-				return fmt.Errorf("--action is required")
+			if action == "" && actionsFile == "" {
+				return fmt.Errorf("either --action or --actions-file is required")
+			}
+			if action != "" && actionsFile != "" {
+				return fmt.Errorf("--action and --actions-file are mutually exclusive")
+			}
+			if err := validateQueryARN("resource", resource); err != nil {
+				return err
+			}
+			if failIfFound && failIfNone {
+				return fmt.Errorf("--fail-if-found and --fail-if-none are mutually exclusive")
+			}
+			var candidates []string
+			if candidatesFile != "" {
+				var err error
+				candidates, err = loadCandidatesFile(candidatesFile)
+				if err != nil {
+					return err
+				}
 			}
-			return runWhoCan(resource, action)
+			filter := query.PrincipalFilter{}
+			if typeFilter != "" {
+				filter.Types = strings.Split(typeFilter, ",")
+			}
+			if accountFilter != "" {
+				filter.Accounts = strings.Split(accountFilter, ",")
+			}
+			if err := filter.Validate(); err != nil {
+				return err
+			}
+			var found int
+			if actionsFile != "" {
+				actions, err := loadActionsFile(actionsFile)
+				if err != nil {
+					return err
+				}
+				found, err = runWhoCanBulk(resource, actions, candidates, dataFile, filter)
+				if err != nil {
+					return err
+				}
+			} else {
+				var err error
+				found, err = runWhoCan(resource, action, candidates, withEvidence, dataFile, filter)
+				if err != nil {
+					return err
+				}
+			}
+			applyWhoCanExitGate(found, failIfFound, failIfNone)
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&action, "action", "", "AWS action to check (e.g., s3:GetObject)")
-	_ = cmd.MarkFlagRequired("action")
+	cmd.Flags().StringVar(&actionsFile, "actions-file", "", "File with one action per line to audit in bulk (# comments allowed)")
+	cmd.Flags().StringVar(&candidatesFile, "candidates-file", "", "File with one principal ARN per line; restricts evaluation to those principals (fast path for large accounts)")
+	cmd.Flags().BoolVar(&withEvidence, "with-evidence", false, "Include the granting policy statements for each principal in the output")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); skips collection entirely")
+	cmd.Flags().BoolVar(&failIfFound, "fail-if-found", false, "Exit with code 1 if any principal matches (CI gate: assert nobody has access)")
+	cmd.Flags().BoolVar(&failIfNone, "fail-if-none", false, "Exit with code 1 if no principal matches (CI gate: assert access still exists)")
+	cmd.Flags().StringVar(&typeFilter, "type", "", "Comma-separated principal types to include (user, role, group, service, public, sso-user, sso-group)")
+	cmd.Flags().StringVar(&accountFilter, "account", "", "Comma-separated account IDs to include")
 
 	return cmd
 }
 
+// applyWhoCanExitGate turns the result count into an exit status for CI
+// gating. Results have already been printed, so a bare non-zero exit here is
+// the contract - the reason goes to stderr for humans reading the build log
+func applyWhoCanExitGate(found int, failIfFound, failIfNone bool) {
+	if failIfFound && found > 0 {
+		fmt.Fprintf(os.Stderr, "fail-if-found: %d principal(s) have access\n", found)
+		os.Exit(1)
+	}
+	if failIfNone && found == 0 {
+		fmt.Fprintln(os.Stderr, "fail-if-none: no principals have access")
+		os.Exit(1)
+	}
+}
+
+// loadActionsFile reads a list of actions, one per line. Blank lines and
+// lines starting with # are skipped.
+func loadActionsFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read actions file: %w", err)
+	}
+
+	var actions []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		actions = append(actions, line)
+	}
+
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("actions file %s contains no actions", path)
+	}
+
+	return actions, nil
+}
+
+// loadCandidatesFile reads a list of candidate principal ARNs, one per line.
+// Blank lines and lines starting with # are skipped.
+func loadCandidatesFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read candidates file: %w", err)
+	}
+
+	var candidates []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := validateQueryARN("candidate principal", line); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, line)
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("candidates file %s contains no principal ARNs", path)
+	}
+
+	return candidates, nil
+}
+
 func pathCmd() *cobra.Command {
 	var (
-		from   string
-		to     string
-		action string
+		from     string
+		to       string
+		action   string
+		dataFile string
+		maxDepth int
+		maxPaths int
 	)
 
 	cmd := &cobra.Command{
@@ -177,22 +478,51 @@ func pathCmd() *cobra.Command {
 		Long:  `Discover all paths from a principal to a resource, including role assumption chains.
 
 This command uses BFS to find both direct access and transitive access through role assumptions.
-It will show all paths up to 5 hops (configurable max depth) and return up to 10 paths.`,
+By default it explores up to 5 hops and returns up to 10 paths; tune both with --max-depth and
+--max-paths. Deep role-chaining setups may need a larger depth, but search cost grows with each
+extra hop over dense role meshes, so large values can be slow.`,
 		Example: `  aws-access-map path \
     --from arn:aws:iam::123456789012:role/AppRole \
     --to arn:aws:s3:::sensitive-bucket \
-    --action s3:GetObject`,
+    --action s3:GetObject
+
+  # Start from a service principal referenced in trust policies
+  aws-access-map path \
+    --from lambda.amazonaws.com \
+    --to arn:aws:s3:::sensitive-bucket \
+    --action s3:GetObject
+
+  # Render the paths as a GraphViz digraph
+  aws-access-map path \
+    --from arn:aws:iam::123456789012:role/AppRole \
+    --to arn:aws:s3:::sensitive-bucket \
+    --action s3:GetObject --format dot | dot -Tsvg -o paths.svg`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if from == "" || to == "" || action == "" {
 				return fmt.Errorf("--from, --to, and --action are all required")
 			}
-			return runPath(from, to, action)
+			if err := validateQueryARN("principal", from); err != nil {
+				return err
+			}
+			if err := validateQueryARN("resource", to); err != nil {
+				return err
+			}
+			if maxDepth < 1 {
+				return fmt.Errorf("--max-depth must be at least 1")
+			}
+			if maxPaths < 1 {
+				return fmt.Errorf("--max-paths must be at least 1")
+			}
+			return runPath(from, to, action, dataFile, maxDepth, maxPaths)
 		},
 	}
 
 	cmd.Flags().StringVar(&from, "from", "", "Source principal ARN")
 	cmd.Flags().StringVar(&to, "to", "", "Target resource ARN or identifier")
 	cmd.Flags().StringVar(&action, "action", "", "AWS action to check")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); skips collection entirely")
+	cmd.Flags().IntVar(&maxDepth, "max-depth", 5, "Maximum role-assumption hops to explore (large values can be slow)")
+	cmd.Flags().IntVar(&maxPaths, "max-paths", 10, "Maximum number of paths to return")
 	_ = cmd.MarkFlagRequired("from")
 	_ = cmd.MarkFlagRequired("to")
 	_ = cmd.MarkFlagRequired("action")
@@ -202,25 +532,54 @@ It will show all paths up to 5 hops (configurable max depth) and return up to 10
 
 func reportCmd() *cobra.Command {
 	var (
-		account  string
-		highRisk bool
+		account     string
+		highRisk    bool
+		actionsFile string
+		unusedDays  int
+		escalation  bool
+		unused      bool
+		public      bool
+		confusedDeputy bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "report",
-		Short: "[COMING SOON] Generate security reports for AWS access",
-		Long:  `[COMING SOON] Analyze collected data and generate reports highlighting high-risk access patterns.
-
-Note: This command is not yet implemented. It will always return "No high-risk findings."
-Use 'who-can "*" --action "*"' to find admin users manually for now.`,
+		Short: "Generate security reports for AWS access",
+		Long: `Analyze collected data and generate reports highlighting high-risk access
+patterns: admin access, public resources, privilege escalation paths,
+wildcard resource policies, and more.`,
 		Example: `  aws-access-map report --account 123456789012 --high-risk`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runReport(account, highRisk)
+			if actionsFile != "" {
+				actions, err := loadActionsFile(actionsFile)
+				if err != nil {
+					return err
+				}
+				// Bulk audit: who can perform each listed action anywhere
+				_, err = runWhoCanBulk("*", actions, nil, "", query.PrincipalFilter{})
+				return err
+			}
+			if escalation {
+				return runEscalationReport()
+			}
+			if public {
+				return runPublicReport()
+			}
+			if confusedDeputy {
+				return runConfusedDeputyReport()
+			}
+			return runReport(account, highRisk, unusedDays, unused)
 		},
 	}
 
 	cmd.Flags().StringVar(&account, "account", "", "AWS account ID to report on")
 	cmd.Flags().BoolVar(&highRisk, "high-risk", false, "Only show high-risk findings")
+	cmd.Flags().StringVar(&actionsFile, "actions-file", "", "File with one action per line; audits who can perform each listed action")
+	cmd.Flags().IntVar(&unusedDays, "unused-days", 90, "Flag privileged roles not assumed within this many days (0 disables)")
+	cmd.Flags().BoolVar(&escalation, "escalation", false, "Only report privilege-escalation paths (iam:PutUserPolicy and friends)")
+	cmd.Flags().BoolVar(&unused, "unused", false, "Only report customer-managed policies attached to zero principals")
+	cmd.Flags().BoolVar(&public, "public", false, "Only report publicly accessible resources and the actions they expose")
+	cmd.Flags().BoolVar(&confusedDeputy, "confused-deputy", false, "Only report service-principal grants lacking an aws:SourceAccount/aws:SourceArn condition")
 
 	return cmd
 }
@@ -241,14 +600,34 @@ func cacheCmd() *cobra.Command {
 
 func cacheClearCmd() *cobra.Command {
 	var accountID string
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "clear",
 		Short: "Clear cached data",
 		Long:  `Delete cached collection data. If --account is specified, only clear cache for that account. Otherwise, clear all cache.`,
 		Example: `  aws-access-map cache clear
-  aws-access-map cache clear --account 123456789012`,
+  aws-access-map cache clear --account 123456789012
+  aws-access-map cache clear --dry-run`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if dryRun {
+				files, err := cache.ClearPreview(accountID)
+				if err != nil {
+					return fmt.Errorf("failed to preview cache clear: %w", err)
+				}
+				if len(files) == 0 {
+					fmt.Println("No cache files to remove")
+					return nil
+				}
+				var total int64
+				for _, f := range files {
+					fmt.Printf("Would remove: %s (%s)\n", f.Path, formatByteSize(f.Size))
+					total += f.Size
+				}
+				fmt.Printf("%d file(s), %s total (re-run without --dry-run to delete)\n", len(files), formatByteSize(total))
+				return nil
+			}
+
 			if accountID != "" {
 				if err := cache.Clear(accountID); err != nil {
 					return fmt.Errorf("failed to clear cache for account %s: %w", accountID, err)
@@ -265,21 +644,44 @@ func cacheClearCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&accountID, "account", "", "AWS account ID to clear cache for (leave empty to clear all)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the files that would be removed without deleting anything")
 
 	return cmd
 }
 
+// formatByteSize renders a file size in a human-readable unit
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
 func cacheInfoCmd() *cobra.Command {
 	var accountID string
+	var since string
 
 	cmd := &cobra.Command{
 		Use:   "info",
 		Short: "Show cache information",
-		Long:  `Display information about cached data for an account, including location and age.`,
-		Example: `  aws-access-map cache info --account 123456789012`,
+		Long: `Display information about cached data. With --account, show the cache for
+that account; without it, list every cached account with its age and status.
+Use --since to only list caches older than a duration (e.g. 7d, 36h).`,
+		Example: `  aws-access-map cache info
+  aws-access-map cache info --account 123456789012
+  aws-access-map cache info --since 7d`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if accountID == "" {
-				return fmt.Errorf("--account is required")
+				return runCacheList(since)
+			}
+			if since != "" {
+				return fmt.Errorf("--since only applies when listing all accounts (omit --account)")
 			}
 
 			filePath, modTime, err := cache.GetCacheInfo(accountID)
@@ -307,18 +709,81 @@ func cacheInfoCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&accountID, "account", "", "AWS account ID to show info for")
-	_ = cmd.MarkFlagRequired("account")
+	cmd.Flags().StringVar(&accountID, "account", "", "AWS account ID to show info for (leave empty to list all)")
+	cmd.Flags().StringVar(&since, "since", "", "Only list caches older than this duration, e.g. 7d or 36h")
 
 	return cmd
 }
 
-func runCollect(outputFile string, includeSCPs bool, allAccounts bool, roleName string, incremental bool) error {
+// runCacheList prints every cached account with its age and stale/valid
+// status. A non-empty since keeps only caches older than that duration.
+func runCacheList(since string) error {
+	var minAge time.Duration
+	if since != "" {
+		parsed, err := parseDurationWithDays(since)
+		if err != nil {
+			return fmt.Errorf("invalid --since value %q: %w", since, err)
+		}
+		minAge = parsed
+	}
+
+	caches, err := cache.ListCaches()
+	if err != nil {
+		return fmt.Errorf("failed to list caches: %w", err)
+	}
+
+	if len(caches) == 0 {
+		fmt.Println("No cached accounts found")
+		return nil
+	}
+
+	filtered := caches[:0]
+	for _, info := range caches {
+		if time.Since(info.ModTime) >= minAge {
+			filtered = append(filtered, info)
+		}
+	}
+	if len(filtered) == 0 {
+		fmt.Printf("No caches older than %s\n", since)
+		return nil
+	}
+
+	fmt.Printf("%-14s %-7s %-12s %-10s %s\n", "ACCOUNT", "STATUS", "AGE", "SIZE", "FILE")
+	for _, info := range filtered {
+		age := time.Since(info.ModTime)
+		status := "VALID"
+		if age > cache.DefaultTTL {
+			status = "STALE"
+		}
+		fmt.Printf("%-14s %-7s %-12s %-10s %s\n", info.AccountID, status, age.Round(time.Second), formatByteSize(info.Size), info.Path)
+	}
+	return nil
+}
+
+// parseDurationWithDays parses a duration, additionally accepting a "d"
+// suffix for days (e.g. "7d"), which time.ParseDuration does not
+func parseDurationWithDays(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected a number of days before 'd'")
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+func runCollect(outputFile, outputTemplate, outputDir string, includeSCPs bool, allAccounts bool, roleName string, incremental bool, iamOnly bool, allVersions bool, resume bool, profiles string, maxAPICalls int, redactPolicies bool, concurrency int, regions string, legacyIAM bool, assumeRoleARN, externalID string, retryCfg collector.RetryConfig) error {
 	// Validate format
 	if format != "text" && format != "json" {
 		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
 	}
 
+	// Collection always talks to AWS, so it can never run offline
+	if err := ensureOnline(); err != nil {
+		return err
+	}
+
 	// Validate cache flags
 	if useCache && noCache {
 		return fmt.Errorf("--cache and --no-cache cannot both be specified")
@@ -328,6 +793,22 @@ func runCollect(outputFile string, includeSCPs bool, allAccounts bool, roleName
 	if allAccounts && (useCache || noCache) {
 		return fmt.Errorf("caching is not supported with --all-accounts (not yet implemented)")
 	}
+	if profiles != "" {
+		if allAccounts {
+			return fmt.Errorf("--profiles and --all-accounts are mutually exclusive")
+		}
+		if useCache || noCache {
+			return fmt.Errorf("caching is not supported with --profiles")
+		}
+	}
+	if assumeRoleARN != "" {
+		if allAccounts {
+			return fmt.Errorf("--assume-role-arn and --all-accounts are mutually exclusive (use --role-name for organization collection)")
+		}
+		if profiles != "" {
+			return fmt.Errorf("--assume-role-arn and --profiles are mutually exclusive")
+		}
+	}
 
 	ctx := context.Background()
 
@@ -337,9 +818,26 @@ func runCollect(outputFile string, includeSCPs bool, allAccounts bool, roleName
 		logOutput = os.Stderr
 	}
 
+	// Extra regions for regional resource collection (global services run once)
+	var regionList []string
+	if regions != "" {
+		regionList = strings.Split(regions, ",")
+	}
+
 	// Handle multi-account collection separately
 	if allAccounts {
-		return runMultiAccountCollect(ctx, outputFile, roleName, logOutput)
+		return runMultiAccountCollect(ctx, outputFile, outputTemplate, outputDir, roleName, resume, allVersions, maxAPICalls, redactPolicies, regionList, legacyIAM, externalID, retryCfg, logOutput)
+	}
+	if outputDir != "" {
+		return fmt.Errorf("--output-dir requires --all-accounts")
+	}
+
+	// Handle multi-profile aggregation (multi-account without Organizations)
+	if profiles != "" {
+		return runMultiProfileCollect(ctx, outputFile, outputTemplate, strings.Split(profiles, ","), includeSCPs, allVersions, maxAPICalls, redactPolicies, regionList, legacyIAM, retryCfg, logOutput)
+	}
+	if resume {
+		return fmt.Errorf("--resume requires --all-accounts")
 	}
 
 	var result *types.CollectionResult
@@ -348,10 +846,25 @@ func runCollect(outputFile string, includeSCPs bool, allAccounts bool, roleName
 
 	// First, we need to get account ID to check cache
 	// We'll create a temporary collector just to get the account ID
-	col, err := collector.New(ctx, region, profile, debug, includeSCPs)
+	col, err := collector.New(ctx, region, profile, debug, includeSCPs, retryCfg)
 	if err != nil {
 		return fmt.Errorf("failed to create collector: %w", err)
 	}
+	col = col.WithIAMOnly(iamOnly).WithAllPolicyVersions(allVersions).WithMaxAPICalls(maxAPICalls).WithResourceConcurrency(concurrency).WithRegions(regionList).WithLegacyCollection(legacyIAM).WithExternalID(externalID).WithAssumeRole(assumeRoleARN)
+
+	// Render a per-stage counter to stderr so long collections show progress.
+	// Suppressed by --quiet for scripting
+	progressShown := false
+	if !quiet {
+		col = col.WithProgress(func(stage string, done, total int) {
+			progressShown = true
+			counter := fmt.Sprintf("%d", done)
+			if total > 0 {
+				counter = fmt.Sprintf("%d/%d", done, total)
+			}
+			fmt.Fprintf(os.Stderr, "\r%-60s", fmt.Sprintf("  %s: %s", stage, counter))
+		})
+	}
 
 	// Get account ID for cache lookup
 	accountID, err := getAccountIDFromCollector(ctx, col)
@@ -443,6 +956,11 @@ func runCollect(outputFile string, includeSCPs bool, allAccounts bool, roleName
 			}
 		}
 
+		// Finish the carriage-return progress line before any further output
+		if progressShown {
+			fmt.Fprintln(os.Stderr)
+		}
+
 		// Save to cache with metadata (unless --no-cache)
 		if !noCache && result.AccountID != "" {
 			// Use SaveWithMetadata to support incremental mode
@@ -461,12 +979,23 @@ func runCollect(outputFile string, includeSCPs bool, allAccounts bool, roleName
 		fmt.Fprintf(logOutput, "DEBUG: First policy has %d statements\n", len(result.Principals[0].Policies[0].Statements))
 	}
 
+	// Strip policy statements after caching so the cache keeps full data
+	if redactPolicies {
+		fmt.Fprintln(logOutput, "Redacting policy documents from output...")
+		collector.RedactPolicies(result)
+	}
+
 	// Save to file (always save as JSON)
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
 
+	// Expand the output template into a unique, traceable filename
+	if outputTemplate != "" {
+		outputFile = expandOutputTemplate(outputTemplate, result.AccountID, data)
+	}
+
 	if err := os.WriteFile(outputFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
@@ -478,30 +1007,39 @@ func runCollect(outputFile string, includeSCPs bool, allAccounts bool, roleName
 	return output.PrintCollect(format, result, outputFile)
 }
 
-// getAccountIDFromCollector extracts the account ID without doing full collection
-// This is a helper to enable cache lookup before full collection
-func getAccountIDFromCollector(ctx context.Context, col *collector.Collector) (string, error) {
-	// Unfortunately, the Collector doesn't expose getAccountID publicly
-	// For now, we'll have to do a full collection to get the account ID
-	// This could be optimized in the future by making getAccountID public
-	// For cache-first scenarios, this isn't a problem since we avoid the collection
-	// For --cache scenarios, we'll collect anyway if cache misses
+// expandOutputTemplate resolves {account}, {date} and {hash} placeholders in
+// an output filename so each collection run produces a traceable artifact.
+// The hash is the first 12 hex characters of the SHA-256 of the result JSON.
+func expandOutputTemplate(template, accountID string, data []byte) string {
+	name := strings.ReplaceAll(template, "{account}", accountID)
+	name = strings.ReplaceAll(name, "{date}", time.Now().Format("2006-01-02"))
+	if strings.Contains(name, "{hash}") {
+		sum := sha256.Sum256(data)
+		name = strings.ReplaceAll(name, "{hash}", hex.EncodeToString(sum[:6]))
+	}
+	return name
+}
 
-	// Return empty string to signal that we should skip cache lookup
-	// and proceed with collection. The collection will populate AccountID.
-	return "", nil
+// getAccountIDFromCollector extracts the account ID without doing a full
+// collection, so the cache can be consulted first. One STS call.
+func getAccountIDFromCollector(ctx context.Context, col *collector.Collector) (string, error) {
+	return col.GetAccountID(ctx)
 }
 
 // runMultiAccountCollect handles collection from all accounts in an organization
-func runMultiAccountCollect(ctx context.Context, outputFile string, roleName string, logOutput *os.File) error {
+func runMultiAccountCollect(ctx context.Context, outputFile, outputTemplate, outputDir string, roleName string, resume bool, allVersions bool, maxAPICalls int, redactPolicies bool, regions []string, legacyIAM bool, externalID string, retryCfg collector.RetryConfig, logOutput *os.File) error {
 	fmt.Fprintln(logOutput, "Collecting from all accounts in organization...")
 	fmt.Fprintf(logOutput, "Using role: %s\n", roleName)
+	if resume {
+		fmt.Fprintln(logOutput, "Resuming from last checkpoint (if present)...")
+	}
 
 	// Create collector with SCP collection enabled for organization-wide collection
-	col, err := collector.New(ctx, region, profile, debug, true)
+	col, err := collector.New(ctx, region, profile, debug, true, retryCfg)
 	if err != nil {
 		return fmt.Errorf("failed to create collector: %w", err)
 	}
+	col = col.WithResume(resume).WithAllPolicyVersions(allVersions).WithMaxAPICalls(maxAPICalls).WithRegions(regions).WithLegacyCollection(legacyIAM).WithExternalID(externalID)
 
 	// Collect from all accounts
 	result, err := col.CollectOrganization(ctx, roleName)
@@ -529,12 +1067,39 @@ func runMultiAccountCollect(ctx context.Context, outputFile string, roleName str
 	fmt.Fprintf(logOutput, "  Total resources: %d\n", totalResources)
 	fmt.Fprintf(logOutput, "  SCPs collected: %d\n", len(result.SCPAttachments))
 
+	// Surface SCPs that allow actions another applicable SCP denies
+	conflicts := query.FindSCPConflicts(result.SCPAttachments, result.OUHierarchy)
+	if len(conflicts) > 0 {
+		fmt.Fprintf(logOutput, "\nWarning: %d SCP conflict(s) detected (allowed actions shadowed by denies):\n", len(conflicts))
+		for _, conflict := range conflicts {
+			fmt.Fprintf(logOutput, "  - %q allowed by SCP %s but denied by SCP %s (pattern %q), affecting accounts %v\n",
+				conflict.Action, conflict.AllowedPolicyID, conflict.DeniedPolicyID, conflict.DenyPattern, conflict.AffectedAccounts)
+		}
+	}
+
+	// Strip policy statements before writing the artifact
+	if redactPolicies {
+		fmt.Fprintln(logOutput, "Redacting policy documents from output...")
+		collector.RedactMultiAccountPolicies(result)
+	}
+
+	// Per-account output: one file per account plus an index, so large orgs
+	// can diff and re-query accounts individually
+	if outputDir != "" {
+		return writeMultiAccountDir(result, outputDir, logOutput)
+	}
+
 	// Save to file as JSON
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
 
+	// Expand the output template (org-wide runs use the organization ID)
+	if outputTemplate != "" {
+		outputFile = expandOutputTemplate(outputTemplate, result.OrganizationID, data)
+	}
+
 	if err := os.WriteFile(outputFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
@@ -543,10 +1108,290 @@ func runMultiAccountCollect(ctx context.Context, outputFile string, roleName str
 	return nil
 }
 
-func runWhoCan(resource, action string) error {
+// orgIndex is the index.json written by 'collect --all-accounts --output-dir'.
+// It carries the org-wide data that belongs to no single account file (SCP
+// and RCP attachments, OU hierarchy) plus the collection outcome per account
+type orgIndex struct {
+	OrganizationID string                        `json:"organization_id"`
+	CollectedAt    time.Time                     `json:"collected_at"`
+	SuccessCount   int                           `json:"success_count"`
+	FailureCount   int                           `json:"failure_count"`
+	FailedAccounts []string                      `json:"failed_accounts,omitempty"`
+	AccountFiles   map[string]string             `json:"account_files"` // AccountID -> filename
+	SCPAttachments []types.SCPAttachment         `json:"scp_attachments,omitempty"`
+	RCPAttachments []types.SCPAttachment         `json:"rcp_attachments,omitempty"`
+	OUHierarchy    map[string]*types.OUHierarchy `json:"ou_hierarchy,omitempty"`
+}
+
+// writeMultiAccountDir writes one <accountID>.json per collected account plus
+// an index.json into outputDir, instead of one organization-wide file
+func writeMultiAccountDir(result *types.MultiAccountCollectionResult, outputDir string, logOutput *os.File) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	index := &orgIndex{
+		OrganizationID: result.OrganizationID,
+		CollectedAt:    result.CollectedAt,
+		SuccessCount:   result.SuccessCount,
+		FailureCount:   result.FailureCount,
+		FailedAccounts: result.FailedAccounts,
+		AccountFiles:   make(map[string]string, len(result.Accounts)),
+		SCPAttachments: result.SCPAttachments,
+		RCPAttachments: result.RCPAttachments,
+		OUHierarchy:    result.OUHierarchy,
+	}
+
+	for accountID, accountResult := range result.Accounts {
+		fileName := accountID + ".json"
+		data, err := json.MarshalIndent(accountResult, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal account %s: %w", accountID, err)
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, fileName), data, 0644); err != nil {
+			return fmt.Errorf("failed to write account file: %w", err)
+		}
+		index.AccountFiles[accountID] = fileName
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "index.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write index file: %w", err)
+	}
+
+	fmt.Fprintf(logOutput, "\nData saved to: %s (%d account file(s) + index.json)\n", outputDir, len(index.AccountFiles))
+	return nil
+}
+
+// runMultiProfileCollect aggregates collection across several AWS profiles
+// into one merged dataset. This serves multi-account setups without
+// Organizations: each profile is collected independently, failures are
+// recorded without aborting the run, and profiles that resolve to an
+// already-collected account are skipped so shared accounts are not duplicated
+func runMultiProfileCollect(ctx context.Context, outputFile, outputTemplate string, profiles []string, includeSCPs bool, allVersions bool, maxAPICalls int, redactPolicies bool, regions []string, legacyIAM bool, retryCfg collector.RetryConfig, logOutput *os.File) error {
+	fmt.Fprintf(logOutput, "Collecting from %d profile(s)...\n", len(profiles))
+
+	result := &types.MultiAccountCollectionResult{
+		Accounts:    make(map[string]*types.CollectionResult),
+		CollectedAt: time.Now(),
+	}
+
+	var failedProfiles []string
+	for _, profileName := range profiles {
+		profileName = strings.TrimSpace(profileName)
+		if profileName == "" {
+			continue
+		}
+
+		fmt.Fprintf(logOutput, "\nCollecting profile %s...\n", profileName)
+		col, err := collector.New(ctx, region, profileName, debug, includeSCPs, retryCfg)
+		if err != nil {
+			fmt.Fprintf(logOutput, "  Failed to create collector for profile %s: %v\n", profileName, err)
+			failedProfiles = append(failedProfiles, profileName)
+			continue
+		}
+
+		accountResult, err := col.WithAllPolicyVersions(allVersions).WithMaxAPICalls(maxAPICalls).WithRegions(regions).WithLegacyCollection(legacyIAM).Collect(ctx)
+		if err != nil {
+			fmt.Fprintf(logOutput, "  Failed to collect from profile %s: %v\n", profileName, err)
+			failedProfiles = append(failedProfiles, profileName)
+			continue
+		}
+
+		// Dedup: two profiles may be credentials into the same account
+		if _, exists := result.Accounts[accountResult.AccountID]; exists {
+			fmt.Fprintf(logOutput, "  Profile %s resolves to already-collected account %s, skipping duplicate\n", profileName, accountResult.AccountID)
+			continue
+		}
+
+		result.Accounts[accountResult.AccountID] = accountResult
+		result.SuccessCount++
+		fmt.Fprintf(logOutput, "  Collected account %s (%d principals, %d resources)\n",
+			accountResult.AccountID, len(accountResult.Principals), len(accountResult.Resources))
+	}
+
+	result.FailureCount = len(failedProfiles)
+	result.FailedAccounts = failedProfiles // Profile names; no account ID when collection failed
+
+	if result.SuccessCount == 0 {
+		return fmt.Errorf("collection failed for all %d profile(s)", len(profiles))
+	}
+
+	// Print summary
+	fmt.Fprintf(logOutput, "\nCollection complete:\n")
+	fmt.Fprintf(logOutput, "  Profiles succeeded: %d\n", result.SuccessCount)
+	fmt.Fprintf(logOutput, "  Profiles failed: %d\n", result.FailureCount)
+	if len(failedProfiles) > 0 {
+		fmt.Fprintf(logOutput, "  Failed profiles: %v\n", failedProfiles)
+	}
+
+	totalPrincipals := 0
+	totalResources := 0
+	for _, accountResult := range result.Accounts {
+		totalPrincipals += len(accountResult.Principals)
+		totalResources += len(accountResult.Resources)
+	}
+	fmt.Fprintf(logOutput, "  Total principals: %d\n", totalPrincipals)
+	fmt.Fprintf(logOutput, "  Total resources: %d\n", totalResources)
+
+	// Strip policy statements before writing the artifact
+	if redactPolicies {
+		fmt.Fprintln(logOutput, "Redacting policy documents from output...")
+		collector.RedactMultiAccountPolicies(result)
+	}
+
+	// Save to file as JSON
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	// No single account or organization ID for a merged run
+	if outputTemplate != "" {
+		outputFile = expandOutputTemplate(outputTemplate, "multi-profile", data)
+	}
+
+	if err := os.WriteFile(outputFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	fmt.Fprintf(logOutput, "\nData saved to: %s\n", outputFile)
+	return nil
+}
+
+// queryCollectionResult obtains the data backing a query command: a --data
+// file when given, the cache when --cache is set, otherwise a live collection
+func queryCollectionResult(ctx context.Context, dataFile string, logOutput io.Writer) (*types.CollectionResult, error) {
+	if dataFile != "" {
+		fmt.Fprintf(logOutput, "Loading data from %s...\n", dataFile)
+		result, err := simulation.LoadFromFile(dataFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load data: %w", err)
+		}
+		return result, nil
+	}
+
+	if err := ensureOnline(); err != nil {
+		return nil, err
+	}
+	col, err := collector.New(ctx, region, profile, debug, false) // Don't collect SCPs for query commands
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collector: %w", err)
+	}
+
+	if useCache {
+		// Force use cache - fail if not found or stale
+		accountID, err := getAccountIDFromCollector(ctx, col)
+		if err != nil {
+			return nil, fmt.Errorf("cannot use cache: failed to determine account ID: %w", err)
+		}
+
+		fmt.Fprintln(logOutput, "Loading from cache...")
+		result, err := cache.Load(accountID, cacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load from cache: %w", err)
+		}
+		if result == nil {
+			return nil, fmt.Errorf("no valid cache found for account %s (run 'collect' first)", accountID)
+		}
+		return result, nil
+	}
+
+	fmt.Fprintln(logOutput, "Collecting AWS data...")
+	result, err := col.Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect data: %w", err)
+	}
+	return result, nil
+}
+
+// runWhoCan returns the number of matching principals so the caller can
+// apply the --fail-if-found/--fail-if-none exit gates
+func runWhoCan(resource, action string, candidates []string, withEvidence bool, dataFile string, filter query.PrincipalFilter) (int, error) {
+	start := time.Now()
+
+	// Validate format
+	if format != "text" && format != "json" && format != "ndjson" {
+		return 0, fmt.Errorf("invalid format: %s (must be 'text', 'json' or 'ndjson')", format)
+	}
+
+	ctx := context.Background()
+
+	// Send progress messages to stderr when stdout carries machine-readable output
+	logOutput := os.Stdout
+	if format != "text" {
+		logOutput = os.Stderr
+	}
+
+	result, err := queryCollectionResult(ctx, dataFile, logOutput)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintln(logOutput, "Building access graph...")
+	g, err := graph.Build(result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	// Query the graph with evaluation context
+	evalCtx := buildEvaluationContext(result)
+	engine := query.New(g).WithContext(evalCtx).WithCandidates(candidates).WithEvidence(withEvidence)
+
+	// Expand friendly names like "my-bucket" to the full resource ARN
+	resolved, err := engine.ResolveResource(resource)
+	if err != nil {
+		return 0, err
+	}
+	if resolved != resource {
+		fmt.Fprintf(logOutput, "Resolved '%s' to '%s'\n", resource, resolved)
+		resource = resolved
+	}
+
+	fmt.Fprintf(logOutput, "Querying who can perform '%s' on '%s'...\n\n", action, resource)
+
+	results, err := engine.WhoCanDetailedFiltered(resource, action, filter)
+	if err != nil {
+		return 0, fmt.Errorf("query failed: %w", err)
+	}
+
+	conditional := 0
+	for _, r := range results {
+		if r.Decision == graph.DecisionConditional {
+			conditional++
+		}
+	}
+	defer printQuerySummary(start, fmt.Sprintf("Found %d principal(s) (%d conditional)", len(results), conditional))
+
+	// Print results using output formatter
+	if templateFile != "" {
+		principals := make([]*types.Principal, len(results))
+		for i, r := range results {
+			principals[i] = r.Principal
+		}
+		if echoContext {
+			return len(results), output.PrintWhoCanTemplate(templateFile, resource, action, principals, evalCtx)
+		}
+		return len(results), output.PrintWhoCanTemplate(templateFile, resource, action, principals)
+	}
+	if echoContext {
+		return len(results), output.PrintWhoCanDetailed(format, resource, action, results, evalCtx)
+	}
+	return len(results), output.PrintWhoCanDetailed(format, resource, action, results)
+}
+
+// runWhoCanBulk evaluates every action from an actions file against the
+// resource and prints the results grouped by action. It returns the total
+// number of principal matches across all actions for the exit gates
+func runWhoCanBulk(resource string, actions []string, candidates []string, dataFile string, filter query.PrincipalFilter) (int, error) {
+	start := time.Now()
+
 	// Validate format
 	if format != "text" && format != "json" {
-		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+		return 0, fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
 	}
 
 	ctx := context.Background()
@@ -557,8 +1402,137 @@ func runWhoCan(resource, action string) error {
 		logOutput = os.Stderr
 	}
 
-	// For MVP, collect data on the fly
-	// TODO: Load from cached file
+	result, err := queryCollectionResult(ctx, dataFile, logOutput)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintln(logOutput, "Building access graph...")
+	g, err := graph.Build(result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	// Query the graph with evaluation context
+	evalCtx := buildEvaluationContext(result)
+	engine := query.New(g).WithContext(evalCtx).WithCandidates(candidates)
+
+	// Expand friendly names like "my-bucket" to the full resource ARN
+	resolved, err := engine.ResolveResource(resource)
+	if err != nil {
+		return 0, err
+	}
+	if resolved != resource {
+		fmt.Fprintf(logOutput, "Resolved '%s' to '%s'\n", resource, resolved)
+		resource = resolved
+	}
+
+	fmt.Fprintf(logOutput, "Auditing %d action(s) against '%s'...\n\n", len(actions), resource)
+
+	results, err := engine.WhoCanEach(resource, actions)
+	if err != nil {
+		return 0, fmt.Errorf("query failed: %w", err)
+	}
+	for i := range results {
+		results[i].Principals = filter.Apply(results[i].Principals)
+	}
+
+	matches := 0
+	for _, r := range results {
+		matches += len(r.Principals)
+	}
+	defer printQuerySummary(start, fmt.Sprintf("Audited %d action(s), %d principal match(es)", len(results), matches))
+
+	// Print results grouped by action
+	if echoContext {
+		return matches, output.PrintWhoCanBulk(format, resource, results, evalCtx)
+	}
+	return matches, output.PrintWhoCanBulk(format, resource, results)
+}
+
+func runPath(from, to, action string, dataFile string, maxDepth, maxPaths int) error {
+	start := time.Now()
+
+	// Validate format
+	if format != "text" && format != "json" && format != "dot" {
+		return fmt.Errorf("invalid format: %s (must be 'text', 'json', or 'dot')", format)
+	}
+
+	ctx := context.Background()
+
+	// Send progress messages to stderr when stdout carries machine-readable output
+	logOutput := os.Stdout
+	if format == "json" || format == "dot" {
+		logOutput = os.Stderr
+	}
+
+	result, err := queryCollectionResult(ctx, dataFile, logOutput)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(logOutput, "Building access graph...")
+	g, err := graph.Build(result)
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	// Query the graph with evaluation context
+	evalCtx := buildEvaluationContext(result)
+	engine := query.New(g).WithContext(evalCtx).WithPathLimits(maxDepth, maxPaths)
+
+	// Expand a friendly --to name like "my-bucket" to the full resource ARN
+	resolved, err := engine.ResolveResource(to)
+	if err != nil {
+		return err
+	}
+	if resolved != to {
+		fmt.Fprintf(logOutput, "Resolved '%s' to '%s'\n", to, resolved)
+		to = resolved
+	}
+
+	fmt.Fprintf(logOutput, "Finding paths from '%s' to '%s' for action '%s'...\n\n", from, to, action)
+
+	paths, err := engine.FindPaths(from, to, action)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	defer printQuerySummary(start, fmt.Sprintf("Found %d path(s)", len(paths)))
+
+	// Print results using output formatter, including onward data flows
+	dataFlows := engine.DataFlowsFrom(to)
+	if templateFile != "" {
+		if echoContext {
+			return output.PrintPathsTemplate(templateFile, from, to, action, paths, dataFlows, evalCtx)
+		}
+		return output.PrintPathsTemplate(templateFile, from, to, action, paths, dataFlows)
+	}
+	if echoContext {
+		return output.PrintPathsWithDataFlows(format, from, to, action, paths, dataFlows, evalCtx)
+	}
+	return output.PrintPathsWithDataFlows(format, from, to, action, paths, dataFlows)
+}
+
+func runReport(account string, highRisk bool, unusedDays int, unusedOnly bool) error {
+	start := time.Now()
+
+	// Validate format
+	if format != "text" && format != "json" && format != "markdown" {
+		return fmt.Errorf("invalid format: %s (must be 'text', 'json', or 'markdown')", format)
+	}
+
+	ctx := context.Background()
+
+	// Send progress messages to stderr when stdout carries machine-readable output
+	logOutput := os.Stdout
+	if format == "json" || format == "markdown" {
+		logOutput = os.Stderr
+	}
+
+	if err := ensureOnline(); err != nil {
+		return err
+	}
 	col, err := collector.New(ctx, region, profile, debug, false) // Don't collect SCPs for query commands
 	if err != nil {
 		return fmt.Errorf("failed to create collector: %w", err)
@@ -576,36 +1550,67 @@ func runWhoCan(resource, action string) error {
 		return fmt.Errorf("failed to build graph: %w", err)
 	}
 
-	fmt.Fprintf(logOutput, "Querying who can perform '%s' on '%s'...\n\n", action, resource)
+	fmt.Fprintln(logOutput, "Analyzing for high-risk patterns...")
 
 	// Query the graph with evaluation context
-	evalCtx := buildEvaluationContext()
+	evalCtx := buildEvaluationContext(result)
 	engine := query.New(g).WithContext(evalCtx)
-	principals, err := engine.WhoCan(resource, action)
-	if err != nil {
-		return fmt.Errorf("query failed: %w", err)
+
+	var findings []query.HighRiskFinding
+	if unusedOnly {
+		// --unused narrows the report to dead customer-managed policies
+		findings = engine.FindUnusedManagedPolicies()
+	} else {
+		findings, err = engine.FindHighRiskAccess()
+		if err != nil {
+			return fmt.Errorf("analysis failed: %w", err)
+		}
+
+		// Fuse usage telemetry with privilege analysis: powerful roles nobody
+		// has assumed recently are prime cleanup candidates
+		if unusedDays > 0 {
+			findings = append(findings, engine.FindUnusedPrivilegedRoles(unusedDays)...)
+		}
+
+		// Filter to only high-risk if flag is set
+		if highRisk {
+			filtered := make([]query.HighRiskFinding, 0)
+			for _, f := range findings {
+				if f.Severity == "CRITICAL" || f.Severity == "HIGH" {
+					filtered = append(filtered, f)
+				}
+			}
+			findings = filtered
+		}
 	}
 
+	defer printQuerySummary(start, fmt.Sprintf("Found %d finding(s)", len(findings)))
+
 	// Print results using output formatter
-	return output.PrintWhoCan(format, resource, action, principals)
+	if templateFile != "" {
+		return output.PrintReportTemplate(templateFile, result.AccountID, findings)
+	}
+	return output.PrintReport(format, result.AccountID, findings)
 }
 
-func runPath(from, to, action string) error {
-	// Validate format
+func runEscalationReport() error {
+	start := time.Now()
+
 	if format != "text" && format != "json" {
 		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
 	}
 
 	ctx := context.Background()
 
-	// Send progress messages to stderr when using JSON format
 	logOutput := os.Stdout
 	if format == "json" {
 		logOutput = os.Stderr
 	}
 
-	// Collect data
-	col, err := collector.New(ctx, region, profile, debug, false) // Don't collect SCPs for query commands
+	if err := ensureOnline(); err != nil {
+		return err
+	}
+	col, err := collector.New(ctx, region, profile, debug, false)
 	if err != nil {
 		return fmt.Errorf("failed to create collector: %w", err)
 	}
@@ -622,35 +1627,55 @@ func runPath(from, to, action string) error {
 		return fmt.Errorf("failed to build graph: %w", err)
 	}
 
-	fmt.Fprintf(logOutput, "Finding paths from '%s' to '%s' for action '%s'...\n\n", from, to, action)
+	fmt.Fprintln(logOutput, "Scanning for privilege-escalation paths...")
 
-	// Query the graph with evaluation context
-	evalCtx := buildEvaluationContext()
-	engine := query.New(g).WithContext(evalCtx)
-	paths, err := engine.FindPaths(from, to, action)
-	if err != nil {
-		return fmt.Errorf("query failed: %w", err)
+	evalCtx := buildEvaluationContext(result)
+	findings := query.New(g).WithContext(evalCtx).FindPrivilegeEscalation()
+
+	defer printQuerySummary(start, fmt.Sprintf("Found %d escalation path(s)", len(findings)))
+
+	if format == "json" {
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
 	}
 
-	// Print results using output formatter
-	return output.PrintPaths(format, from, to, action, paths)
+	if len(findings) == 0 {
+		fmt.Println("No privilege-escalation paths found")
+		return nil
+	}
+
+	for _, f := range findings {
+		fmt.Printf("[%s] %s\n", f.Severity, f.PrincipalARN)
+		fmt.Printf("  Action: %s (target: %s)\n", f.Action, f.Target)
+		fmt.Printf("  %s\n\n", f.Description)
+	}
+	return nil
 }
 
-func runReport(account string, highRisk bool) error {
-	// Validate format
+// runPublicReport collects fresh data and lists publicly accessible resources
+// with the actions their resource policies expose
+func runPublicReport() error {
+	start := time.Now()
+
 	if format != "text" && format != "json" {
 		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
 	}
 
 	ctx := context.Background()
 
-	// Send progress messages to stderr when using JSON format
 	logOutput := os.Stdout
 	if format == "json" {
 		logOutput = os.Stderr
 	}
 
-	col, err := collector.New(ctx, region, profile, debug, false) // Don't collect SCPs for query commands
+	if err := ensureOnline(); err != nil {
+		return err
+	}
+	col, err := collector.New(ctx, region, profile, debug, false)
 	if err != nil {
 		return fmt.Errorf("failed to create collector: %w", err)
 	}
@@ -667,27 +1692,98 @@ func runReport(account string, highRisk bool) error {
 		return fmt.Errorf("failed to build graph: %w", err)
 	}
 
-	fmt.Fprintln(logOutput, "Analyzing for high-risk patterns...")
+	fmt.Fprintln(logOutput, "Scanning for publicly accessible resources...")
 
-	// Query the graph with evaluation context
-	evalCtx := buildEvaluationContext()
-	engine := query.New(g).WithContext(evalCtx)
-	findings, err := engine.FindHighRiskAccess()
+	findings := query.New(g).FindPublicAccess()
+
+	defer printQuerySummary(start, fmt.Sprintf("Found %d publicly accessible resource(s)", len(findings)))
+
+	if format == "json" {
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(findings) == 0 {
+		fmt.Println("No publicly accessible resources found")
+		return nil
+	}
+
+	for _, f := range findings {
+		fmt.Printf("%s (%s)\n", f.Resource.ARN, f.Resource.Type)
+		fmt.Printf("  Public actions: %s\n\n", strings.Join(f.Actions, ", "))
+	}
+	return nil
+}
+
+// runConfusedDeputyReport collects fresh data and lists resource-policy
+// statements that grant a service principal access without pinning the
+// originating account or resource
+func runConfusedDeputyReport() error {
+	start := time.Now()
+
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+	}
+
+	ctx := context.Background()
+
+	logOutput := os.Stdout
+	if format == "json" {
+		logOutput = os.Stderr
+	}
+
+	if err := ensureOnline(); err != nil {
+		return err
+	}
+	col, err := collector.New(ctx, region, profile, debug, false)
 	if err != nil {
-		return fmt.Errorf("analysis failed: %w", err)
+		return fmt.Errorf("failed to create collector: %w", err)
 	}
 
-	// Filter to only high-risk if flag is set
-	if highRisk {
-		filtered := make([]query.HighRiskFinding, 0)
-		for _, f := range findings {
-			if f.Severity == "CRITICAL" || f.Severity == "HIGH" {
-				filtered = append(filtered, f)
-			}
+	fmt.Fprintln(logOutput, "Collecting AWS data...")
+	result, err := col.Collect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to collect data: %w", err)
+	}
+
+	fmt.Fprintln(logOutput, "Building access graph...")
+	g, err := graph.Build(result)
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	fmt.Fprintln(logOutput, "Scanning for confused-deputy risks...")
+
+	findings := query.New(g).FindConfusedDeputyRisks()
+
+	defer printQuerySummary(start, fmt.Sprintf("Found %d unpinned service-principal grant(s)", len(findings)))
+
+	if format == "json" {
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal output: %w", err)
 		}
-		findings = filtered
+		fmt.Println(string(data))
+		return nil
 	}
 
-	// Print results using output formatter
-	return output.PrintReport(format, result.AccountID, findings)
+	if len(findings) == 0 {
+		fmt.Println("No confused-deputy risks found")
+		return nil
+	}
+
+	for _, f := range findings {
+		fmt.Printf("%s (%s)\n", f.Resource.ARN, f.Resource.Type)
+		if f.Sid != "" {
+			fmt.Printf("  Statement: %s\n", f.Sid)
+		}
+		fmt.Printf("  Service: %s\n", strings.Join(f.ServicePrincipals, ", "))
+		fmt.Printf("  Actions: %s\n", strings.Join(f.Actions, ", "))
+		fmt.Printf("  Fix: add an aws:SourceAccount or aws:SourceArn condition\n\n")
+	}
+	return nil
 }
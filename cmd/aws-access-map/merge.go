@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// mergeCmd implements the "merge" command: combine per-account collection
+// files into one dataset for organization-wide offline queries
+func mergeCmd() *cobra.Command {
+	var (
+		inputFiles []string
+		outputFile string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "merge",
+		Short: "Combine multiple collection files into one dataset",
+		Long: `Merge per-account collection files (from running 'collect' against each
+account) into a single combined dataset. Principals and resources are
+deduplicated by ARN and SCP attachments are preserved, so the merged file
+can be fed to 'simulate who-can' and friends for cross-account queries
+without needing 'collect --all-accounts' access.`,
+		Example: `  # Merge two account snapshots
+  aws-access-map merge --input prod.json --input staging.json -o org.json
+
+  # Query across the merged data offline
+  aws-access-map simulate who-can "arn:aws:s3:::shared-bucket/*" \
+    --action s3:GetObject --data org.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(inputFiles) < 2 {
+				return fmt.Errorf("at least two --input files are required")
+			}
+
+			results := make([]*types.CollectionResult, 0, len(inputFiles))
+			for _, path := range inputFiles {
+				result, err := simulation.LoadFromFile(path)
+				if err != nil {
+					return fmt.Errorf("failed to load %s: %w", path, err)
+				}
+				results = append(results, result)
+			}
+
+			merged, err := simulation.MergeResults(results)
+			if err != nil {
+				return fmt.Errorf("failed to merge: %w", err)
+			}
+
+			data, err := json.MarshalIndent(merged, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal data: %w", err)
+			}
+
+			if outputFile == "-" {
+				fmt.Println(string(data))
+			} else {
+				if err := os.WriteFile(outputFile, data, 0644); err != nil {
+					return fmt.Errorf("failed to write file: %w", err)
+				}
+			}
+
+			fmt.Fprintf(os.Stderr, "Merged %d file(s): %d principal(s), %d resource(s)\n",
+				len(inputFiles), len(merged.Principals), len(merged.Resources))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&inputFiles, "input", nil, "Collection file to merge (repeatable)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "merged-access-data.json", "Output file for merged data; '-' writes stdout")
+	_ = cmd.MarkFlagRequired("input")
+
+	return cmd
+}
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/pfrederiksen/aws-access-map/internal/graph"
 	"github.com/pfrederiksen/aws-access-map/internal/query"
 	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/output"
 	"github.com/pfrederiksen/aws-access-map/pkg/types"
 )
 
@@ -35,11 +38,22 @@ policy changes before deployment, CI/CD integration, and local development.`,
 	cmd.AddCommand(simulateWhoCanCmd())
 	cmd.AddCommand(simulateDiffCmd())
 	cmd.AddCommand(simulateTestCmd())
+	cmd.AddCommand(simulateTestSCPCmd())
 	cmd.AddCommand(simulateValidateCmd())
+	cmd.AddCommand(simulateReportCmd())
 
 	return cmd
 }
 
+// loadSimulateData loads a CollectionResult from path, reading stdin when
+// the path is "-" so pipelines can pipe generated data without a temp file
+func loadSimulateData(path string) (*types.CollectionResult, error) {
+	if path == "-" {
+		return simulation.LoadFromReader(os.Stdin)
+	}
+	return simulation.LoadFromFile(path)
+}
+
 // simulateWhoCanCmd implements the "simulate who-can" subcommand
 func simulateWhoCanCmd() *cobra.Command {
 	var dataFile string
@@ -60,6 +74,7 @@ This command does not connect to AWS and works entirely with local files.`,
   aws-access-map simulate who-can "arn:aws:lambda:us-east-1:123:function:fn" --action lambda:InvokeFunction --data policies.json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			start := time.Now()
 			resourceARN := args[0]
 
 			// Validate inputs
@@ -69,29 +84,41 @@ This command does not connect to AWS and works entirely with local files.`,
 			if action == "" {
 				return fmt.Errorf("--action is required")
 			}
-
-			// Load data from file
-			result, err := simulation.LoadFromFile(dataFile)
-			if err != nil {
-				return fmt.Errorf("failed to load data: %w", err)
+			if err := validateQueryARN("resource", resourceARN); err != nil {
+				return err
 			}
 
-			// Build graph
-			g, err := graph.Build(result)
+			// Load data and build the graph; org-wide datasets (from
+			// 'collect --all-accounts') become one combined cross-account graph
+			var g *graph.Graph
+			var err error
+			if dataFile == "-" {
+				g, err = simulation.LoadGraphFromReader(os.Stdin)
+			} else {
+				g, err = simulation.LoadGraphFromFile(dataFile)
+			}
 			if err != nil {
-				return fmt.Errorf("failed to build graph: %w", err)
+				return fmt.Errorf("failed to load data: %w", err)
 			}
 
 			// Create query engine with evaluation context
 			evalCtx := buildEvaluationContext()
 			q := query.New(g).WithContext(evalCtx)
 
+			// Expand friendly names like "my-bucket" to the full resource ARN
+			resourceARN, err = q.ResolveResource(resourceARN)
+			if err != nil {
+				return err
+			}
+
 			// Query for principals
 			principals, err := q.WhoCan(resourceARN, action)
 			if err != nil {
 				return fmt.Errorf("failed to query: %w", err)
 			}
 
+			defer printQuerySummary(start, fmt.Sprintf("Found %d principal(s)", len(principals)))
+
 			// Output results
 			if format == "json" {
 				data, err := json.MarshalIndent(principals, "", "  ")
@@ -118,7 +145,7 @@ This command does not connect to AWS and works entirely with local files.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&dataFile, "data", "", "Local policy data file (JSON)")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Local policy data file (JSON); '-' reads stdin")
 	cmd.Flags().StringVar(&action, "action", "", "Action to check (e.g., s3:GetObject, *)")
 
 	_ = cmd.MarkFlagRequired("data")
@@ -135,7 +162,10 @@ func simulateDiffCmd() *cobra.Command {
 		Use:   "diff",
 		Short: "Compare access between two policy sets",
 		Long: `Compare who can access a resource between two policy sets.
-Shows which principals gained access, lost access, or kept the same access.`,
+Shows which principals gained access, lost access, or kept the same access.
+
+Either input may be '-' to read from stdin, but stdin is a single stream so
+only one of --before and --after can use it at a time.`,
 		Example: `  # Compare before and after policy changes
   aws-access-map simulate diff --before current.json --after proposed.json --action "*"
 
@@ -159,14 +189,19 @@ Shows which principals gained access, lost access, or kept the same access.`,
 			if action == "" {
 				action = "*"
 			}
+			// Stdin is a single stream; it can feed one side of the diff
+			// but not both
+			if beforeFile == "-" && afterFile == "-" {
+				return fmt.Errorf("only one of --before and --after can read from stdin ('-')")
+			}
 
-			// Load both files
-			beforeResult, err := simulation.LoadFromFile(beforeFile)
+			// Load both inputs
+			beforeResult, err := loadSimulateData(beforeFile)
 			if err != nil {
 				return fmt.Errorf("failed to load before file: %w", err)
 			}
 
-			afterResult, err := simulation.LoadFromFile(afterFile)
+			afterResult, err := loadSimulateData(afterFile)
 			if err != nil {
 				return fmt.Errorf("failed to load after file: %w", err)
 			}
@@ -198,6 +233,11 @@ Shows which principals gained access, lost access, or kept the same access.`,
 				return nil
 			}
 
+			// Markdown output for CI bots posting PR comments
+			if format == "markdown" {
+				return output.PrintAccessDiffMarkdown(resourceARN, action, diff)
+			}
+
 			// Text output
 			fmt.Printf("Access Diff for %s (action: %s)\n\n", resourceARN, action)
 
@@ -229,8 +269,8 @@ Shows which principals gained access, lost access, or kept the same access.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&beforeFile, "before", "", "Policy data before changes (JSON)")
-	cmd.Flags().StringVar(&afterFile, "after", "", "Policy data after changes (JSON)")
+	cmd.Flags().StringVar(&beforeFile, "before", "", "Policy data before changes (JSON); '-' reads stdin")
+	cmd.Flags().StringVar(&afterFile, "after", "", "Policy data after changes (JSON); '-' reads stdin")
 	cmd.Flags().StringVar(&resourceARN, "resource", "*", "Resource ARN to check (default: *)")
 	cmd.Flags().StringVar(&action, "action", "*", "Action to check (default: *)")
 
@@ -267,7 +307,7 @@ Shows what access the policy grants and any security concerns.`,
 			}
 
 			// Load base data
-			baseResult, err := simulation.LoadFromFile(dataFile)
+			baseResult, err := loadSimulateData(dataFile)
 			if err != nil {
 				return fmt.Errorf("failed to load base data: %w", err)
 			}
@@ -311,13 +351,18 @@ Shows what access the policy grants and any security concerns.`,
 			fmt.Printf("Principal: %s\n", principalARN)
 			fmt.Printf("New Policy: %s\n\n", addPolicyFile)
 
-			// Check if new policy grants admin access
+			// Check if new policy grants admin access, distinguishing an
+			// unconditional *:* from one gated by conditions
 			afterQuery := query.New(afterGraph)
-			adminPrincipals, _ := afterQuery.WhoCan("*", "*")
-
-			for _, p := range adminPrincipals {
-				if p.ARN == principalARN {
-					fmt.Println("⚠️  WARNING: This policy grants full admin access (*:* on *)")
+			adminDecisions, _ := afterQuery.WhoCanDetailed("*", "*")
+
+			for _, d := range adminDecisions {
+				if d.Principal.ARN == principalARN {
+					if d.Decision == graph.DecisionConditional {
+						fmt.Printf("⚠️  WARNING: This policy grants admin access (*:* on *) under conditions: %s\n", strings.Join(d.Conditions, ", "))
+					} else {
+						fmt.Println("⚠️  WARNING: This policy grants full admin access (*:* on *)")
+					}
 					break
 				}
 			}
@@ -333,7 +378,7 @@ Shows what access the policy grants and any security concerns.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&dataFile, "data", "", "Base policy data file (JSON)")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Base policy data file (JSON); '-' reads stdin")
 	cmd.Flags().StringVar(&addPolicyFile, "add-policy", "", "Policy to add (JSON file)")
 	cmd.Flags().StringVar(&principalARN, "principal", "", "Principal ARN to modify")
 
@@ -344,6 +389,209 @@ Shows what access the policy grants and any security concerns.`,
 	return cmd
 }
 
+// simulateTestSCPCmd implements the "simulate test-scp" subcommand
+func simulateTestSCPCmd() *cobra.Command {
+	var dataFile, scpFile string
+	var targets []string
+
+	cmd := &cobra.Command{
+		Use:   "test-scp",
+		Short: "Test a proposed SCP against current access",
+		Long: `Attach a proposed Service Control Policy to local data and report what
+access it would remove. The SCP is attached to the given targets (account
+IDs, OU IDs, or a root ID), the graph is rebuilt, and who-can results are
+diffed across every action currently granted plus the actions the SCP names.`,
+		Example: `  # What would denying s3:DeleteBucket break?
+  aws-access-map simulate test-scp \
+    --data current.json \
+    --scp deny-delete-bucket.json \
+    --targets 123456789012
+
+  # Attach to an OU
+  aws-access-map simulate test-scp --data org.json --scp new-scp.json --targets ou-ab12-cdefghij`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Validate inputs
+			if dataFile == "" {
+				return fmt.Errorf("--data is required")
+			}
+			if scpFile == "" {
+				return fmt.Errorf("--scp is required")
+			}
+
+			// Load base data
+			baseResult, err := loadSimulateData(dataFile)
+			if err != nil {
+				return fmt.Errorf("failed to load base data: %w", err)
+			}
+
+			// Load the proposed SCP
+			scpData, err := os.ReadFile(scpFile)
+			if err != nil {
+				return fmt.Errorf("failed to read SCP file: %w", err)
+			}
+			var scp types.PolicyDocument
+			if err := json.Unmarshal(scpData, &scp); err != nil {
+				return fmt.Errorf("failed to parse SCP JSON: %w", err)
+			}
+
+			// Default to a root attachment so the SCP applies everywhere
+			scpTargets := make([]types.SCPTarget, 0, len(targets))
+			for _, id := range targets {
+				scpTargets = append(scpTargets, scpTargetFromID(id))
+			}
+			if len(scpTargets) == 0 {
+				scpTargets = []types.SCPTarget{{Type: types.SCPTargetTypeRoot, ID: "ROOT"}}
+			}
+
+			diffs, err := simulation.EvaluateSCPImpact(baseResult, scp, scpTargets)
+			if err != nil {
+				return err
+			}
+
+			// Output results
+			if format == "json" {
+				data, err := json.MarshalIndent(diffs, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("Testing SCP %s against %s\n\n", scpFile, dataFile)
+
+			if len(diffs) == 0 {
+				fmt.Println("✅ No current access would change")
+				return nil
+			}
+
+			for _, d := range diffs {
+				fmt.Printf("Action: %s\n", d.Action)
+				if len(d.Diff.Revoked) > 0 {
+					fmt.Printf("  ❌ ACCESS REMOVED (%d principals):\n", len(d.Diff.Revoked))
+					for _, arn := range d.Diff.Revoked {
+						fmt.Printf("    - %s\n", arn)
+					}
+				}
+				// An SCP can only deny, but report grants too in case the
+				// comparison surfaces something unexpected
+				if len(d.Diff.Granted) > 0 {
+					fmt.Printf("  ✅ ACCESS GRANTED (%d principals):\n", len(d.Diff.Granted))
+					for _, arn := range d.Diff.Granted {
+						fmt.Printf("    + %s\n", arn)
+					}
+				}
+				fmt.Println()
+			}
+			fmt.Printf("%d action(s) affected\n", len(diffs))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dataFile, "data", "", "Base policy data file (JSON); '-' reads stdin")
+	cmd.Flags().StringVar(&scpFile, "scp", "", "Proposed SCP policy document (JSON file)")
+	cmd.Flags().StringSliceVar(&targets, "targets", nil, "Attachment targets: account IDs, OU IDs (ou-*), or a root ID (r-*); defaults to root")
+
+	_ = cmd.MarkFlagRequired("data")
+	_ = cmd.MarkFlagRequired("scp")
+
+	return cmd
+}
+
+// scpTargetFromID infers the SCP target type from the ID shape AWS
+// Organizations uses: ou-* for OUs, r-* for roots, 12-digit account IDs
+func scpTargetFromID(id string) types.SCPTarget {
+	switch {
+	case strings.HasPrefix(id, "ou-"):
+		return types.SCPTarget{Type: types.SCPTargetTypeOrganizationalUnit, ID: id}
+	case strings.HasPrefix(id, "r-"):
+		return types.SCPTarget{Type: types.SCPTargetTypeRoot, ID: id}
+	default:
+		return types.SCPTarget{Type: types.SCPTargetTypeAccount, ID: id}
+	}
+}
+
+// simulateReportCmd implements the "simulate report" subcommand
+func simulateReportCmd() *cobra.Command {
+	var dataFile string
+	var highRisk bool
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate a security report from local data",
+		Long: `Run the same high-risk access analysis as the live report command, but
+against a local policy snapshot instead of a live AWS account. Useful for
+running security analysis in CI against committed policy data.`,
+		Example: `  # Full report from a committed snapshot
+  aws-access-map simulate report --data policies.json
+
+  # Only CRITICAL and HIGH findings
+  aws-access-map simulate report --data policies.json --high-risk
+
+  # Machine-readable output for CI
+  aws-access-map simulate report --data policies.json --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start := time.Now()
+
+			// Validate inputs
+			if dataFile == "" {
+				return fmt.Errorf("--data is required")
+			}
+			if format != "text" && format != "json" && format != "markdown" {
+				return fmt.Errorf("invalid format: %s (must be 'text', 'json', or 'markdown')", format)
+			}
+
+			// Load data
+			result, err := loadSimulateData(dataFile)
+			if err != nil {
+				return fmt.Errorf("failed to load data: %w", err)
+			}
+
+			// Build graph
+			g, err := graph.Build(result)
+			if err != nil {
+				return fmt.Errorf("failed to build graph: %w", err)
+			}
+
+			// Analyze with evaluation context, same as the live report
+			evalCtx := buildEvaluationContext(result)
+			engine := query.New(g).WithContext(evalCtx)
+
+			findings, err := engine.FindHighRiskAccess()
+			if err != nil {
+				return fmt.Errorf("analysis failed: %w", err)
+			}
+
+			// Filter to only high-risk if flag is set
+			if highRisk {
+				filtered := make([]query.HighRiskFinding, 0)
+				for _, f := range findings {
+					if f.Severity == "CRITICAL" || f.Severity == "HIGH" {
+						filtered = append(filtered, f)
+					}
+				}
+				findings = filtered
+			}
+
+			defer printQuerySummary(start, fmt.Sprintf("Found %d finding(s)", len(findings)))
+
+			// Print results using output formatter
+			if templateFile != "" {
+				return output.PrintReportTemplate(templateFile, result.AccountID, findings)
+			}
+			return output.PrintReport(format, result.AccountID, findings)
+		},
+	}
+
+	cmd.Flags().StringVar(&dataFile, "data", "", "Policy data file (JSON); '-' reads stdin")
+	cmd.Flags().BoolVar(&highRisk, "high-risk", false, "Only show CRITICAL and HIGH severity findings")
+
+	_ = cmd.MarkFlagRequired("data")
+
+	return cmd
+}
+
 // simulateValidateCmd implements the "simulate validate" subcommand
 func simulateValidateCmd() *cobra.Command {
 	var dataFile string
@@ -367,7 +615,7 @@ func simulateValidateCmd() *cobra.Command {
 			}
 
 			// Load data
-			result, err := simulation.LoadFromFile(dataFile)
+			result, err := loadSimulateData(dataFile)
 			if err != nil {
 				return fmt.Errorf("failed to load data: %w", err)
 			}
@@ -382,26 +630,45 @@ func simulateValidateCmd() *cobra.Command {
 			q := query.New(g)
 
 			issues := []string{}
-
-			// Check 1: Full admin access
-			adminPrincipals, err := q.WhoCan("*", "*")
-			if err == nil && len(adminPrincipals) > 0 {
-				issues = append(issues, fmt.Sprintf("⚠️  %d principals have full admin access (*:* on *)", len(adminPrincipals)))
-				for _, p := range adminPrincipals {
-					issues = append(issues, fmt.Sprintf("    - %s (%s)", p.Name, p.ARN))
+			notes := []string{}
+
+			// Check 1: Full admin access. The tri-state query separates
+			// unconditional admins (a CI-gating issue) from principals whose
+			// *:* allow only applies under conditions (e.g. MFA required) -
+			// the latter are surfaced but do not fail the run
+			adminDecisions, err := q.WhoCanDetailed("*", "*")
+			if err == nil {
+				var full, conditional []query.PrincipalDecision
+				for _, d := range adminDecisions {
+					if d.Decision == graph.DecisionConditional {
+						conditional = append(conditional, d)
+					} else {
+						full = append(full, d)
+					}
+				}
+				if len(full) > 0 {
+					issues = append(issues, fmt.Sprintf("⚠️  %d principals have full admin access (*:* on *)", len(full)))
+					for _, d := range full {
+						issues = append(issues, fmt.Sprintf("    - %s (%s)", d.Principal.Name, d.Principal.ARN))
+					}
+				}
+				if len(conditional) > 0 {
+					notes = append(notes, fmt.Sprintf("ℹ️  %d principals have admin access only under conditions", len(conditional)))
+					for _, d := range conditional {
+						notes = append(notes, fmt.Sprintf("    - %s (%s) [%s]", d.Principal.Name, d.Principal.ARN, strings.Join(d.Conditions, ", ")))
+					}
 				}
 			}
 
-			// Check 2: Public access (principals with Type "public" or "*" in ARN)
-			publicCount := 0
-			for _, principal := range result.Principals {
-				if principal.Type == types.PrincipalTypePublic || principal.ARN == "*" {
-					publicCount++
+			// Check 2: Public access (resource policies open to "*" or
+			// arn:aws:iam::*:root without an audience-restricting condition)
+			publicResources := q.FindPublicAccess()
+			if len(publicResources) > 0 {
+				issues = append(issues, fmt.Sprintf("⚠️  %d resources allow public access", len(publicResources)))
+				for _, pr := range publicResources {
+					issues = append(issues, fmt.Sprintf("    - %s (%s)", pr.Resource.ARN, strings.Join(pr.Actions, ", ")))
 				}
 			}
-			if publicCount > 0 {
-				issues = append(issues, fmt.Sprintf("⚠️  %d resources allow public access", publicCount))
-			}
 
 			// Check 3: Principals with no policies (potentially unused)
 			unusedCount := 0
@@ -414,13 +681,28 @@ func simulateValidateCmd() *cobra.Command {
 				issues = append(issues, fmt.Sprintf("ℹ️  %d principals have no policies (potentially unused)", unusedCount))
 			}
 
-			// Output results
-			if len(issues) > 0 {
+			// Output results. Notes are displayed alongside issues but only
+			// issues drive the non-zero CI exit code
+			if format == "markdown" {
+				if err := output.PrintValidationMarkdown(append(issues, notes...)); err != nil {
+					return err
+				}
+				if len(issues) > 0 {
+					os.Exit(1) // Non-zero exit for CI/CD
+				}
+				return nil
+			}
+			if len(issues) > 0 || len(notes) > 0 {
 				fmt.Println("Security Issues Found:")
 				for _, issue := range issues {
 					fmt.Println(issue)
 				}
-				os.Exit(1) // Non-zero exit for CI/CD
+				for _, note := range notes {
+					fmt.Println(note)
+				}
+				if len(issues) > 0 {
+					os.Exit(1) // Non-zero exit for CI/CD
+				}
 			} else {
 				fmt.Println("✅ No security issues detected")
 			}
@@ -429,7 +711,7 @@ func simulateValidateCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&dataFile, "data", "", "Policy data file (JSON)")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Policy data file (JSON); '-' reads stdin")
 
 	_ = cmd.MarkFlagRequired("data")
 
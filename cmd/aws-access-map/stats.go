@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/collector"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// statsCmd implements the "stats" command: structural metrics about a
+// collected dataset, as a quick completeness and health check
+func statsCmd() *cobra.Command {
+	var dataFile string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show structural statistics for a collected dataset",
+		Long: `Report structural metrics about a collected dataset: principals and
+resources by type, trust relationships, permission boundary coverage, public
+resources, and allow-edge density.
+
+A quick sanity check before querying: a dataset with zero resources or no
+trust relationships usually means collection ran with missing permissions.`,
+		Example: `  aws-access-map stats --data collected.json
+  aws-access-map stats --data collected.json --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+			}
+
+			var result *types.CollectionResult
+			var err error
+			if dataFile != "" {
+				result, err = simulation.LoadFromFile(dataFile)
+				if err != nil {
+					return fmt.Errorf("failed to load data: %w", err)
+				}
+			} else {
+				if err := ensureOnline(); err != nil {
+					return err
+				}
+				ctx := context.Background()
+				col, err := collector.New(ctx, region, profile, debug, false)
+				if err != nil {
+					return fmt.Errorf("failed to create collector: %w", err)
+				}
+				fmt.Fprintln(os.Stderr, "Collecting AWS data...")
+				result, err = col.Collect(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to collect data: %w", err)
+				}
+			}
+
+			g, err := graph.Build(result)
+			if err != nil {
+				return fmt.Errorf("failed to build graph: %w", err)
+			}
+
+			stats := g.Stats()
+
+			if format == "json" {
+				data, err := json.MarshalIndent(stats, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal output: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("Principals: %d\n", stats.Principals)
+			printCountsByType(stats.PrincipalsByType)
+			fmt.Printf("Resources: %d\n", stats.Resources)
+			printCountsByType(stats.ResourcesByType)
+			fmt.Printf("Trust relationships: %d\n", stats.TrustRelationships)
+			fmt.Printf("Principals with permission boundaries: %d\n", stats.PrincipalsWithBoundary)
+			fmt.Printf("Publicly accessible resources: %d\n", stats.PublicResources)
+			fmt.Printf("Allow edges: %d (%.1f per principal)\n", stats.AllowEdges, stats.AvgAllowEdges)
+			fmt.Printf("Deny edges: %d\n", stats.DenyEdges)
+			if stats.MostPrivilegedARN != "" {
+				fmt.Printf("Most privileged: %s (%d allow edges)\n", stats.MostPrivilegedARN, stats.MostPrivilegedEdges)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); collects live when omitted")
+
+	return cmd
+}
+
+// printCountsByType renders a type->count map as indented lines, sorted by
+// type name for stable output
+func printCountsByType(counts map[string]int) {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("  %-16s %d\n", key, counts[key])
+	}
+}
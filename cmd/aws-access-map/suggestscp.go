@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/collector"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// suggestSCPCmd implements the "suggest-scp" command: given an undesired
+// access that currently resolves allowed, propose a deny SCP that blocks it
+func suggestSCPCmd() *cobra.Command {
+	var (
+		principalARN string
+		action       string
+		resourceARN  string
+		dataFile     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "suggest-scp",
+		Short: "Propose an SCP deny statement that blocks an undesired access",
+		Long: `Construct and verify a Service Control Policy deny that blocks the given
+principal/action/resource combination. Candidates are tested against the
+access graph: the target access must become denied, and every other grant is
+re-checked so access the deny would break is reported instead of silently
+over-blocked.
+
+The output is advisory - review the snippet and attach it through
+Organizations yourself.`,
+		Example: `  aws-access-map suggest-scp \
+    --principal arn:aws:iam::123456789012:user/contractor \
+    --action s3:DeleteBucket \
+    --resource "arn:aws:s3:::prod-*" --data collected.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+			}
+			if err := validateQueryARN("principal", principalARN); err != nil {
+				return err
+			}
+			if err := validateQueryARN("resource", resourceARN); err != nil {
+				return err
+			}
+
+			var result *types.CollectionResult
+			var err error
+			if dataFile != "" {
+				result, err = simulation.LoadFromFile(dataFile)
+				if err != nil {
+					return fmt.Errorf("failed to load data: %w", err)
+				}
+			} else {
+				if err := ensureOnline(); err != nil {
+					return err
+				}
+				ctx := context.Background()
+				col, err := collector.New(ctx, region, profile, debug, true) // SCPs matter here
+				if err != nil {
+					return fmt.Errorf("failed to create collector: %w", err)
+				}
+				fmt.Fprintln(os.Stderr, "Collecting AWS data...")
+				result, err = col.Collect(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to collect data: %w", err)
+				}
+			}
+
+			g, err := graph.Build(result)
+			if err != nil {
+				return fmt.Errorf("failed to build graph: %w", err)
+			}
+
+			evalCtx := buildEvaluationContext(result)
+			suggestion, err := query.New(g).WithContext(evalCtx).SuggestSCP(principalARN, action, resourceARN)
+			if err != nil {
+				return err
+			}
+
+			if format == "json" {
+				data, err := json.MarshalIndent(suggestion, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal output: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("Suggested SCP to block %s performing %s on %s:\n\n", principalARN, action, resourceARN)
+			snippet, err := json.MarshalIndent(suggestion.Policy, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal policy: %w", err)
+			}
+			fmt.Println(string(snippet))
+
+			if len(suggestion.SideEffects) == 0 {
+				fmt.Println("\nVerified: blocks the target access; no other grants affected.")
+				return nil
+			}
+
+			fmt.Printf("\nWarning: this deny also blocks %d other grant(s):\n", len(suggestion.SideEffects))
+			for _, effect := range suggestion.SideEffects {
+				fmt.Printf("  %s -[%s]-> %s\n", effect.PrincipalARN, effect.Action, effect.ResourceARN)
+			}
+			fmt.Println("\nReview before applying; a narrower deny was not sufficient.")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&principalARN, "principal", "", "Principal ARN whose access should be blocked")
+	cmd.Flags().StringVar(&action, "action", "", "Action to block (e.g. s3:DeleteBucket)")
+	cmd.Flags().StringVar(&resourceARN, "resource", "", "Resource ARN the access targets")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); collects live when omitted")
+
+	_ = cmd.MarkFlagRequired("principal")
+	_ = cmd.MarkFlagRequired("action")
+	_ = cmd.MarkFlagRequired("resource")
+
+	return cmd
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/collector"
+)
+
+// whoamiCmd implements the "whoami" command: a pre-flight check of the
+// current credentials and the permissions a full collect run depends on
+func whoamiCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Verify AWS credentials and the permissions collect needs",
+		Long: `Call STS GetCallerIdentity to show which account and principal the current
+credentials resolve to, then dry-probe the key IAM and per-service list
+calls the collector depends on (requesting at most one item from each).
+Failed probes are mapped to the collection stages that break without them,
+so missing permissions can be fixed before a full collect run instead of
+surfacing as cryptic errors or silently empty results halfway through.`,
+		Example: `  aws-access-map whoami
+
+  # Check a specific profile, as JSON
+  aws-access-map whoami --profile prod --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWhoami()
+		},
+	}
+
+	return cmd
+}
+
+func runWhoami() error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+	}
+
+	ctx := context.Background()
+
+	if err := ensureOnline(); err != nil {
+		return err
+	}
+
+	col, err := collector.New(ctx, region, profile, debug, false)
+	if err != nil {
+		return fmt.Errorf("failed to create collector: %w", err)
+	}
+
+	diag, err := col.Diagnose(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify credentials: %w", err)
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(diag, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Account: %s\n", diag.AccountID)
+	fmt.Printf("Caller:  %s\n\n", diag.CallerARN)
+
+	fmt.Println("Permission probes:")
+	for _, probe := range diag.Probes {
+		status := "ok"
+		if !probe.OK {
+			status = "MISSING"
+		}
+		fmt.Printf("  %-40s %-8s %s\n", probe.Permission, status, probe.Collector)
+	}
+
+	failing := diag.FailingCollectors()
+	if len(failing) == 0 {
+		fmt.Println("\nAll probed permissions are present; collect should run cleanly.")
+	} else {
+		fmt.Printf("\nCollectors likely to fail or return nothing: %s\n", strings.Join(failing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/pfrederiksen/aws-access-map/internal/collector"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// withPolicyCmd implements the "with-policy" command: list every principal
+// that has a specific managed policy attached
+func withPolicyCmd() *cobra.Command {
+	var (
+		policyRef string
+		dataFile  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "with-policy",
+		Short: "List principals with a specific managed policy attached",
+		Long: `Find every principal (user, role, group) whose attached managed policies
+include the given policy, identified by ARN or bare name.
+
+This is about attachment, not effective access: a principal is listed even if
+SCPs or permission boundaries would block the policy's grants. Useful for
+detaching a risky policy fleet-wide, e.g. "who has AdministratorAccess".`,
+		Example: `  aws-access-map with-policy --policy-arn arn:aws:iam::aws:policy/AdministratorAccess
+  aws-access-map with-policy --policy-arn AdministratorAccess --data collected.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+			}
+			if err := validateQueryARN("policy", policyRef); err != nil {
+				return err
+			}
+
+			var result *types.CollectionResult
+			var err error
+			if dataFile != "" {
+				result, err = simulation.LoadFromFile(dataFile)
+				if err != nil {
+					return fmt.Errorf("failed to load data: %w", err)
+				}
+			} else {
+				if err := ensureOnline(); err != nil {
+					return err
+				}
+				ctx := context.Background()
+				col, err := collector.New(ctx, region, profile, debug, false)
+				if err != nil {
+					return fmt.Errorf("failed to create collector: %w", err)
+				}
+				fmt.Fprintln(os.Stderr, "Collecting AWS data...")
+				result, err = col.Collect(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to collect data: %w", err)
+				}
+			}
+
+			g, err := graph.Build(result)
+			if err != nil {
+				return fmt.Errorf("failed to build graph: %w", err)
+			}
+
+			principals := query.New(g).FindPrincipalsWithPolicy(policyRef)
+
+			if format == "json" {
+				out := struct {
+					Policy     string             `json:"policy"`
+					Principals []*types.Principal `json:"principals"`
+				}{policyRef, principals}
+				data, err := json.MarshalIndent(out, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal output: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			if len(principals) == 0 {
+				fmt.Printf("No principals have %s attached.\n", policyRef)
+				return nil
+			}
+			fmt.Printf("Found %d principal(s) with %s attached:\n\n", len(principals), policyRef)
+			for _, p := range principals {
+				fmt.Printf("  %s (%s)\n", p.Name, p.Type)
+				fmt.Printf("    ARN: %s\n\n", p.ARN)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&policyRef, "policy-arn", "", "Managed policy ARN or name to search for")
+	cmd.Flags().StringVar(&dataFile, "data", "", "Previously collected data file (JSON); collects live when omitted")
+
+	_ = cmd.MarkFlagRequired("policy-arn")
+
+	return cmd
+}
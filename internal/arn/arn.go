@@ -0,0 +1,83 @@
+// Package arn validates AWS ARN syntax for query inputs.
+package arn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// knownServices lists the service namespaces this tool understands plus
+// common ones users are likely to query
+var knownServices = map[string]bool{
+	"iam":            true,
+	"sts":            true,
+	"s3":             true,
+	"kms":            true,
+	"sqs":            true,
+	"sns":            true,
+	"secretsmanager": true,
+	"lambda":         true,
+	"apigateway":     true,
+	"execute-api":    true,
+	"ecr":            true,
+	"events":         true,
+	"identitystore":  true,
+	"organizations":  true,
+	"sso":            true,
+	"ec2":            true,
+	"dynamodb":       true,
+	"rds":            true,
+	"logs":           true,
+	"cloudformation": true,
+	"cloudwatch":     true,
+	"ssm":            true,
+}
+
+// validPartitions are the AWS partitions an ARN may belong to
+var validPartitions = map[string]bool{
+	"aws":        true,
+	"aws-cn":     true,
+	"aws-us-gov": true,
+}
+
+// Validate checks that s is a well-formed AWS ARN. It returns a descriptive
+// error for the first problem found: wrong prefix, missing segments, unknown
+// partition or service, or an empty resource part.
+func Validate(s string) error {
+	parts := strings.SplitN(s, ":", 6)
+	if len(parts) < 6 {
+		return fmt.Errorf("expected 6 colon-separated segments (arn:partition:service:region:account:resource), got %d", len(parts))
+	}
+	if parts[0] != "arn" {
+		return fmt.Errorf("must start with %q, got %q", "arn", parts[0])
+	}
+	if !validPartitions[parts[1]] {
+		return fmt.Errorf("unknown partition %q (expected aws, aws-cn, or aws-us-gov)", parts[1])
+	}
+	if parts[2] == "" {
+		return fmt.Errorf("service segment is empty")
+	}
+	if !knownServices[parts[2]] {
+		return fmt.Errorf("unrecognized service %q", parts[2])
+	}
+	if parts[4] != "" && !isAccountID(parts[4]) {
+		return fmt.Errorf("account segment %q is not a 12-digit account ID", parts[4])
+	}
+	if parts[5] == "" {
+		return fmt.Errorf("resource segment is empty")
+	}
+	return nil
+}
+
+// isAccountID reports whether s is a 12-digit AWS account ID
+func isAccountID(s string) bool {
+	if len(s) != 12 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package arn
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		arn     string
+		wantErr bool
+	}{
+		{
+			name:    "valid S3 bucket ARN",
+			arn:     "arn:aws:s3:::my-bucket",
+			wantErr: false,
+		},
+		{
+			name:    "valid IAM role ARN",
+			arn:     "arn:aws:iam::123456789012:role/AppRole",
+			wantErr: false,
+		},
+		{
+			name:    "valid KMS key ARN with region",
+			arn:     "arn:aws:kms:us-east-1:123456789012:key/abc-123",
+			wantErr: false,
+		},
+		{
+			name:    "valid GovCloud partition",
+			arn:     "arn:aws-us-gov:s3:::my-bucket",
+			wantErr: false,
+		},
+		{
+			name:    "too few segments",
+			arn:     "arn:aws:s3:my-bucket",
+			wantErr: true,
+		},
+		{
+			name:    "wrong prefix",
+			arn:     "ARN:aws:s3:::my-bucket",
+			wantErr: true,
+		},
+		{
+			name:    "unknown partition",
+			arn:     "arn:gcp:s3:::my-bucket",
+			wantErr: true,
+		},
+		{
+			name:    "empty service",
+			arn:     "arn:aws::::my-bucket",
+			wantErr: true,
+		},
+		{
+			name:    "unrecognized service",
+			arn:     "arn:aws:s4:::my-bucket",
+			wantErr: true,
+		},
+		{
+			name:    "malformed account ID",
+			arn:     "arn:aws:iam::12345:role/AppRole",
+			wantErr: true,
+		},
+		{
+			name:    "empty resource",
+			arn:     "arn:aws:iam::123456789012:",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.arn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) error = %v, wantErr %v", tt.arn, err, tt.wantErr)
+			}
+		})
+	}
+}
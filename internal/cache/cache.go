@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,8 +18,43 @@ const (
 
 	// CacheDirName is the directory name under user's home for cache storage
 	CacheDirName = ".aws-access-map/cache"
+
+	// FormatVersion is the cache file format version written by this build.
+	// Loading fails for caches whose major version differs, with guidance to
+	// re-collect, so schema changes can't silently misread old files.
+	FormatVersion = "1.0"
 )
 
+// versionedResult wraps a CollectionResult with the cache format version.
+// The embedded pointer keeps the stored JSON flat, so files written before
+// versioning (no cache_version field) still unmarshal cleanly.
+type versionedResult struct {
+	CacheVersion string `json:"cache_version,omitempty"`
+	*types.CollectionResult
+}
+
+// checkFormatVersion validates a stored cache format version against the one
+// this build writes. An empty version means the file predates versioning and
+// is treated as 1.0. Minor version differences are accepted; major version
+// differences error with guidance to re-collect.
+func checkFormatVersion(stored string) error {
+	if stored == "" {
+		stored = "1.0"
+	}
+	if majorVersion(stored) != majorVersion(FormatVersion) {
+		return fmt.Errorf("cache format version %s is incompatible with this build (expects %s); run 'aws-access-map cache clear' and re-collect", stored, FormatVersion)
+	}
+	return nil
+}
+
+// majorVersion returns the part of a version string before the first dot
+func majorVersion(v string) string {
+	if i := strings.Index(v, "."); i >= 0 {
+		return v[:i]
+	}
+	return v
+}
+
 // Save writes a CollectionResult to the cache
 // The cache file is named: <accountID>-<timestamp>.json
 func Save(accountID string, result *types.CollectionResult) error {
@@ -52,8 +88,11 @@ func Save(accountID string, result *types.CollectionResult) error {
 	filename := fmt.Sprintf("%s-%s.json", accountID, timestamp)
 	filePath := filepath.Join(cacheDir, filename)
 
-	// Marshal result to JSON
-	data, err := json.MarshalIndent(result, "", "  ")
+	// Marshal result to JSON with the cache format version
+	data, err := json.MarshalIndent(versionedResult{
+		CacheVersion:     FormatVersion,
+		CollectionResult: result,
+	}, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal result: %w", err)
 	}
@@ -117,10 +156,16 @@ func Load(accountID string, ttl time.Duration) (*types.CollectionResult, error)
 	}
 
 	var result types.CollectionResult
-	if err := json.Unmarshal(data, &result); err != nil {
+	wrapper := versionedResult{CollectionResult: &result}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal cache: %w", err)
 	}
 
+	// Refuse caches written by an incompatible format version
+	if err := checkFormatVersion(wrapper.CacheVersion); err != nil {
+		return nil, err
+	}
+
 	return &result, nil
 }
 
@@ -152,6 +197,137 @@ func Clear(accountID string) error {
 	return clearAccountCache(cacheDir, accountID)
 }
 
+// CacheFileInfo describes one file a Clear call would remove
+type CacheFileInfo struct {
+	Path string
+	Size int64
+}
+
+// ClearPreview lists the cache files Clear would delete, without deleting
+// anything. With an accountID it mirrors clearAccountCache (metadata files
+// are preserved there, so they are not listed); with an empty accountID it
+// mirrors the full-directory removal and lists every file.
+func ClearPreview(accountID string) ([]CacheFileInfo, error) {
+	// Get cache directory
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	// Check if cache directory exists
+	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
+		// Nothing to clear
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var files []CacheFileInfo
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		if accountID != "" {
+			// Match the per-account deletion rules
+			if !strings.HasPrefix(name, accountID+"-") || !strings.HasSuffix(name, ".json") {
+				continue
+			}
+			if strings.HasSuffix(name, "-metadata.json") {
+				continue
+			}
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, CacheFileInfo{
+			Path: filepath.Join(cacheDir, name),
+			Size: info.Size(),
+		})
+	}
+
+	return files, nil
+}
+
+// AccountCacheInfo describes the most recent cache file for one account
+type AccountCacheInfo struct {
+	AccountID string
+	Path      string
+	ModTime   time.Time
+	Size      int64
+}
+
+// ListCaches enumerates every account with a cache file, returning the
+// latest file per account sorted by account ID. Metadata files are skipped.
+// A missing cache directory yields an empty list, not an error.
+func ListCaches() ([]AccountCacheInfo, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return listCachesIn(cacheDir)
+}
+
+// listCachesIn scans a cache directory and picks the most recent cache file
+// per account prefix, mirroring findLatestCacheFile's matching rules
+func listCachesIn(cacheDir string) ([]AccountCacheInfo, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	latest := make(map[string]AccountCacheInfo)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".json") || strings.HasSuffix(name, "-metadata.json") {
+			continue
+		}
+
+		// Cache files are named <accountID>-<timestamp>.json
+		accountID, _, found := strings.Cut(name, "-")
+		if !found || accountID == "" {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if current, ok := latest[accountID]; !ok || info.ModTime().After(current.ModTime) {
+			latest[accountID] = AccountCacheInfo{
+				AccountID: accountID,
+				Path:      filepath.Join(cacheDir, name),
+				ModTime:   info.ModTime(),
+				Size:      info.Size(),
+			}
+		}
+	}
+
+	caches := make([]AccountCacheInfo, 0, len(latest))
+	for _, info := range latest {
+		caches = append(caches, info)
+	}
+	sort.Slice(caches, func(i, j int) bool { return caches[i].AccountID < caches[j].AccountID })
+
+	return caches, nil
+}
+
 // getCacheDir returns the cache directory path
 // Expands ~ to user's home directory
 func getCacheDir() (string, error) {
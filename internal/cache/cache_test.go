@@ -3,6 +3,7 @@ package cache
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -238,6 +239,64 @@ func TestClear_AllAccounts(t *testing.T) {
 	}
 }
 
+// TestClearPreview tests listing cache files without deleting them
+func TestClearPreview(t *testing.T) {
+	tempDir := setupTestCacheDir(t)
+	defer cleanupTestCacheDir(t, tempDir)
+
+	account1 := "111111111111"
+	account2 := "222222222222"
+
+	result1 := &types.CollectionResult{AccountID: account1, Regions: []string{"us-east-1"}}
+	result2 := &types.CollectionResult{AccountID: account2, Regions: []string{"us-west-2"}}
+
+	if err := Save(account1, result1); err != nil {
+		t.Fatalf("Failed to save account1: %v", err)
+	}
+
+	if err := Save(account2, result2); err != nil {
+		t.Fatalf("Failed to save account2: %v", err)
+	}
+
+	// Preview for a single account lists only that account's file
+	files, err := ClearPreview(account1)
+	if err != nil {
+		t.Fatalf("ClearPreview(account1) failed: %v", err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 file for account1, got %d", len(files))
+	}
+
+	if !strings.Contains(files[0].Path, account1) {
+		t.Errorf("Expected path to contain %s, got %s", account1, files[0].Path)
+	}
+
+	if files[0].Size <= 0 {
+		t.Errorf("Expected positive file size, got %d", files[0].Size)
+	}
+
+	// Preview for all accounts lists both files
+	files, err = ClearPreview("")
+	if err != nil {
+		t.Fatalf("ClearPreview(\"\") failed: %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Errorf("Expected 2 files for all accounts, got %d", len(files))
+	}
+
+	// Nothing was actually deleted
+	loaded1, err := Load(account1, DefaultTTL)
+	if err != nil {
+		t.Fatalf("Load account1 failed: %v", err)
+	}
+
+	if loaded1 == nil {
+		t.Error("Expected account1 cache to survive the preview")
+	}
+}
+
 // TestSave_OverwritesOldCache tests that saving removes old cache files
 func TestSave_OverwritesOldCache(t *testing.T) {
 	tempDir := setupTestCacheDir(t)
@@ -389,6 +448,98 @@ func setupTestCacheDir(t *testing.T) string {
 	return tempDir
 }
 
+// TestLoad_IncompatibleVersion tests that a cache written by a different
+// major format version is refused with guidance instead of misread
+func TestLoad_IncompatibleVersion(t *testing.T) {
+	tempDir := setupTestCacheDir(t)
+	defer cleanupTestCacheDir(t, tempDir)
+
+	accountID := "999999999999"
+
+	result := &types.CollectionResult{
+		AccountID:   accountID,
+		CollectedAt: time.Now(),
+	}
+	if err := Save(accountID, result); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	// Rewrite the cache file claiming a future major version
+	cacheFile, _, err := GetCacheInfo(accountID)
+	if err != nil || cacheFile == "" {
+		t.Fatalf("Failed to locate cache file: %v", err)
+	}
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		t.Fatalf("Failed to read cache file: %v", err)
+	}
+	tampered := []byte(strings.Replace(string(data), `"cache_version": "`+FormatVersion+`"`, `"cache_version": "2.0"`, 1))
+	if err := os.WriteFile(cacheFile, tampered, 0644); err != nil {
+		t.Fatalf("Failed to rewrite cache file: %v", err)
+	}
+
+	if _, err := Load(accountID, DefaultTTL); err == nil {
+		t.Error("Expected an incompatible-version error, got nil")
+	}
+}
+
+// TestLoad_LegacyUnversionedCache tests that files written before versioning
+// (no cache_version field) still load
+func TestLoad_LegacyUnversionedCache(t *testing.T) {
+	tempDir := setupTestCacheDir(t)
+	defer cleanupTestCacheDir(t, tempDir)
+
+	accountID := "999999999999"
+
+	if err := Save(accountID, &types.CollectionResult{
+		AccountID:   accountID,
+		CollectedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	// Strip the cache_version field to simulate a pre-versioning file
+	cacheFile, _, err := GetCacheInfo(accountID)
+	if err != nil || cacheFile == "" {
+		t.Fatalf("Failed to locate cache file: %v", err)
+	}
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		t.Fatalf("Failed to read cache file: %v", err)
+	}
+	legacy := []byte(strings.Replace(string(data), `"cache_version": "`+FormatVersion+`",`, "", 1))
+	if err := os.WriteFile(cacheFile, legacy, 0644); err != nil {
+		t.Fatalf("Failed to rewrite cache file: %v", err)
+	}
+
+	loaded, err := Load(accountID, DefaultTTL)
+	if err != nil {
+		t.Fatalf("Legacy cache should load, got error: %v", err)
+	}
+	if loaded == nil || loaded.AccountID != accountID {
+		t.Errorf("Legacy cache loaded incorrectly: %+v", loaded)
+	}
+}
+
+func TestCheckFormatVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{FormatVersion, false},
+		{"", false},    // Pre-versioning file, treated as 1.0
+		{"1.1", false}, // Minor bumps stay compatible
+		{"2.0", true},
+		{"0.9", true},
+	}
+
+	for _, tt := range tests {
+		if err := checkFormatVersion(tt.version); (err != nil) != tt.wantErr {
+			t.Errorf("checkFormatVersion(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+		}
+	}
+}
+
 // Helper function to cleanup test cache directory
 func cleanupTestCacheDir(t *testing.T, tempDir string) {
 	t.Helper()
@@ -401,3 +552,46 @@ func cleanupTestCacheDir(t *testing.T, tempDir string) {
 		_ = Clear(accountID) // Best effort cleanup
 	}
 }
+
+// TestListCachesIn tests per-account enumeration of a cache directory
+func TestListCachesIn(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name string, modTime time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set mtime on %s: %v", name, err)
+		}
+	}
+
+	now := time.Now()
+	write("111111111111-20260101-000000.json", now.Add(-48*time.Hour))
+	write("111111111111-20260102-000000.json", now.Add(-1*time.Hour))
+	write("222222222222-20260101-000000.json", now.Add(-2*time.Hour))
+	write("111111111111-metadata.json", now) // metadata must be skipped
+	write("notes.txt", now)                  // non-JSON must be skipped
+
+	caches, err := listCachesIn(dir)
+	if err != nil {
+		t.Fatalf("listCachesIn returned error: %v", err)
+	}
+
+	if len(caches) != 2 {
+		t.Fatalf("Expected 2 accounts, got %d", len(caches))
+	}
+
+	// Sorted by account ID, latest file per account
+	if caches[0].AccountID != "111111111111" || caches[1].AccountID != "222222222222" {
+		t.Errorf("Unexpected account order: %s, %s", caches[0].AccountID, caches[1].AccountID)
+	}
+	if filepath.Base(caches[0].Path) != "111111111111-20260102-000000.json" {
+		t.Errorf("Expected latest file for account 1, got %s", caches[0].Path)
+	}
+	if caches[1].Size != 2 {
+		t.Errorf("Expected size 2 for account 2, got %d", caches[1].Size)
+	}
+}
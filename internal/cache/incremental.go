@@ -11,15 +11,21 @@ import (
 	"github.com/pfrederiksen/aws-access-map/pkg/types"
 )
 
+// OUHierarchyMaxAge bounds how long a cached OU hierarchy is reused before
+// the ListParents walk is repeated. Accounts rarely move between OUs, so the
+// default is generous; callers can lower it for fast-churning organizations
+var OUHierarchyMaxAge = 7 * 24 * time.Hour
+
 // IncrementalStats tracks statistics for incremental collection
 type IncrementalStats struct {
-	Mode             string        `json:"mode"` // "full" or "incremental"
-	DurationSeconds  float64       `json:"duration_seconds"`
-	ResourcesTotal   int           `json:"resources_total"`
-	ResourcesFetched int           `json:"resources_fetched"`
-	ResourcesCached  int           `json:"resources_cached"`
-	ChangePercentage float64       `json:"change_percentage"`
-	Changes          *ChangeSet    `json:"changes,omitempty"`
+	Mode              string     `json:"mode"` // "full" or "incremental"
+	DurationSeconds   float64    `json:"duration_seconds"`
+	ResourcesTotal    int        `json:"resources_total"`
+	ResourcesFetched  int        `json:"resources_fetched"`
+	ResourcesCached   int        `json:"resources_cached"`
+	ChangePercentage  float64    `json:"change_percentage"`
+	OUHierarchyReused bool       `json:"ou_hierarchy_reused,omitempty"` // ListParents walk skipped via cache
+	Changes           *ChangeSet `json:"changes,omitempty"`
 }
 
 // IncrementalCollect performs delta collection based on previous cache
@@ -49,8 +55,29 @@ func IncrementalCollect(ctx context.Context, col *collector.Collector, previousC
 	// Extract metadata from previous cache
 	oldMetadata := ExtractMetadata(previousCache)
 
-	// Perform a new full collection (for MVP - we need fresh data to compare)
-	// In a production implementation, this would query AWS APIs for metadata only
+	// Reuse the cached OU hierarchy when it is fresh enough: accounts rarely
+	// move between OUs, and the ListParents walk is one call per hierarchy
+	// level on every collection. A stale hierarchy falls through to a re-walk
+	ouReused := false
+	if previousCache.OUHierarchy != nil && time.Since(previousCache.CollectedAt) <= OUHierarchyMaxAge {
+		col = col.WithCachedOUHierarchy(previousCache.OUHierarchy)
+		ouReused = true
+	}
+
+	// Seed the collector with reusable resources so services that can
+	// fingerprint a resource from its list entry (currently S3) skip the
+	// per-resource policy fetches when nothing changed. Other services still
+	// re-collect in full
+	reusable := make(map[string]*types.Resource)
+	for _, resource := range previousCache.Resources {
+		if resource.Type == types.ResourceTypeS3 && resource.ETag != "" {
+			reusable[resource.ARN] = resource
+		}
+	}
+	if len(reusable) > 0 {
+		col = col.WithReusableResources(reusable)
+	}
+
 	newResult, err := col.Collect(ctx)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to collect new data: %w", err)
@@ -62,25 +89,26 @@ func IncrementalCollect(ctx context.Context, col *collector.Collector, previousC
 	// Detect changes
 	changes := DetectChanges(oldMetadata, newMetadata)
 
-	// Calculate statistics
+	// Calculate statistics. Cached = resources served from the reusable set;
+	// fetched = everything the collector actually had to pull from AWS
 	totalResources := len(newResult.Resources)
-	resourcesFetched := changes.TotalChanges()
-	resourcesCached := totalResources - resourcesFetched
+	resourcesCached := col.ReusedResourceCount()
+	resourcesFetched := totalResources - resourcesCached
 	changePercentage := changes.ChangePercentage(totalResources)
 
 	stats := &IncrementalStats{
-		Mode:             "incremental",
-		DurationSeconds:  time.Since(startTime).Seconds(),
-		ResourcesTotal:   totalResources,
-		ResourcesFetched: resourcesFetched,
-		ResourcesCached:  resourcesCached,
-		ChangePercentage: changePercentage,
-		Changes:          changes,
+		Mode:              "incremental",
+		DurationSeconds:   time.Since(startTime).Seconds(),
+		ResourcesTotal:    totalResources,
+		ResourcesFetched:  resourcesFetched,
+		ResourcesCached:   resourcesCached,
+		ChangePercentage:  changePercentage,
+		OUHierarchyReused: ouReused,
+		Changes:           changes,
 	}
 
-	// For MVP, we return the full new result
-	// In production, we would merge old cache + delta
-	// This is acceptable because we still detect and report changes
+	// The result is already merged: reused resources came straight from the
+	// previous cache, everything else was freshly collected
 
 	return newResult, stats, nil
 }
@@ -155,6 +183,11 @@ func LoadMetadata(accountID string) (*CacheMetadata, error) {
 		return nil, fmt.Errorf("failed to parse metadata JSON: %w", err)
 	}
 
+	// Refuse metadata written by an incompatible format version
+	if err := checkFormatVersion(metadata.Version); err != nil {
+		return nil, fmt.Errorf("metadata for account %s: %w", accountID, err)
+	}
+
 	return &metadata, nil
 }
 
@@ -216,6 +249,9 @@ func PrintIncrementalStats(stats *IncrementalStats, debug bool) {
 		fmt.Fprintf(os.Stderr, "Resources Fetched: %d\n", stats.ResourcesFetched)
 		fmt.Fprintf(os.Stderr, "Resources Cached: %d\n", stats.ResourcesCached)
 		fmt.Fprintf(os.Stderr, "Change Percentage: %.2f%%\n", stats.ChangePercentage)
+		if stats.OUHierarchyReused {
+			fmt.Fprintf(os.Stderr, "OU Hierarchy: reused from cache (ListParents walk skipped)\n")
+		}
 
 		if stats.Changes != nil && stats.Changes.HasChanges() {
 			fmt.Fprintf(os.Stderr, "\nChanges Detected:\n")
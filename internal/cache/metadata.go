@@ -18,14 +18,23 @@ type ResourceMetadata struct {
 	ETag         string    `json:"etag"`        // S3/resource-specific ETag
 }
 
+// PrincipalMetadata tracks per-principal hashes to detect policy changes
+type PrincipalMetadata struct {
+	ARN        string `json:"arn"`
+	Type       string `json:"type"`
+	PolicyHash string `json:"policy_hash"` // SHA256 over policies, trust policy and boundary
+}
+
 // CacheMetadata extends CollectionResult with metadata for incremental updates
 type CacheMetadata struct {
-	Version         string                       `json:"version"` // Cache format version
-	AccountID       string                       `json:"account_id"`
-	CollectedAt     time.Time                    `json:"collected_at"`
-	Resources       map[string]*ResourceMetadata `json:"resources"`        // ARN -> metadata
-	PrincipalsHash  string                       `json:"principals_hash"`  // Hash of all principal ARNs
-	PrincipalsCount int                          `json:"principals_count"` // Count for quick check
+	Version         string                        `json:"version"` // Cache format version
+	AccountID       string                        `json:"account_id"`
+	CollectedAt     time.Time                     `json:"collected_at"`
+	Resources       map[string]*ResourceMetadata  `json:"resources"`        // ARN -> metadata
+	Principals      map[string]*PrincipalMetadata `json:"principals,omitempty"` // ARN -> metadata (absent in pre-diff metadata files)
+	PrincipalsHash  string                        `json:"principals_hash"`  // Hash of all principal ARNs
+	PrincipalsCount int                           `json:"principals_count"` // Count for quick check
+	ParentOUsHash   string                        `json:"parent_ous_hash,omitempty"` // Hash of the OU hierarchy, to detect account moves
 }
 
 // ChangeSet represents detected changes between two collection runs
@@ -33,6 +42,10 @@ type ChangeSet struct {
 	Added    []string // ARNs of new resources
 	Removed  []string // ARNs of deleted resources
 	Modified []string // ARNs of resources with changed policies
+
+	PrincipalsAdded    []string // ARNs of new principals
+	PrincipalsRemoved  []string // ARNs of deleted principals
+	PrincipalsModified []string // ARNs of principals with changed policies
 }
 
 // ComputePolicyHash calculates SHA256 hash of a policy document
@@ -78,6 +91,45 @@ func ComputePrincipalHash(principals []*types.Principal) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// ComputePrincipalPolicyHash calculates a SHA256 hash covering everything
+// that defines a principal's access: inline/attached policies, trust policy
+// and permissions boundary. Two collections of the same unchanged principal
+// produce the same hash.
+func ComputePrincipalPolicyHash(principal *types.Principal) string {
+	if principal == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(struct {
+		Policies    []types.PolicyDocument
+		TrustPolicy *types.PolicyDocument
+		Boundary    *types.PolicyDocument
+	}{principal.Policies, principal.TrustPolicy, principal.PermissionsBoundary})
+	if err != nil {
+		return ""
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// ComputeParentOUsHash calculates SHA256 hash of an account's OU hierarchy.
+// Order matters (the list is the path from account to root), so the OUs are
+// hashed as-is rather than sorted
+func ComputeParentOUsHash(hierarchy *types.OUHierarchy) string {
+	if hierarchy == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(hierarchy.ParentOUs)
+	if err != nil {
+		return ""
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
 // ExtractMetadata extracts metadata from a CollectionResult
 func ExtractMetadata(result *types.CollectionResult) *CacheMetadata {
 	if result == nil {
@@ -85,12 +137,23 @@ func ExtractMetadata(result *types.CollectionResult) *CacheMetadata {
 	}
 
 	metadata := &CacheMetadata{
-		Version:         "1.0",
+		Version:         FormatVersion,
 		AccountID:       result.AccountID,
 		CollectedAt:     result.CollectedAt,
 		Resources:       make(map[string]*ResourceMetadata),
+		Principals:      make(map[string]*PrincipalMetadata),
 		PrincipalsHash:  ComputePrincipalHash(result.Principals),
 		PrincipalsCount: len(result.Principals),
+		ParentOUsHash:   ComputeParentOUsHash(result.OUHierarchy),
+	}
+
+	// Extract per-principal metadata for change tracking
+	for _, principal := range result.Principals {
+		metadata.Principals[principal.ARN] = &PrincipalMetadata{
+			ARN:        principal.ARN,
+			Type:       string(principal.Type),
+			PolicyHash: ComputePrincipalPolicyHash(principal),
+		}
 	}
 
 	// Extract resource metadata
@@ -100,6 +163,7 @@ func ExtractMetadata(result *types.CollectionResult) *CacheMetadata {
 			Type:         string(resource.Type),
 			LastModified: result.CollectedAt, // Use collection time as proxy
 			PolicyHash:   ComputePolicyHash(resource.ResourcePolicy),
+			ETag:         resource.ETag,
 		}
 
 		metadata.Resources[resource.ARN] = resourceMeta
@@ -140,12 +204,32 @@ func DetectChanges(old, new *CacheMetadata) *ChangeSet {
 		}
 	}
 
+	// Diff principals the same way. Metadata files written before
+	// per-principal hashes existed have no Principals map; require both
+	// sides to carry it so an upgrade doesn't report every principal as added
+	if len(old.Principals) > 0 && len(new.Principals) > 0 {
+		for arn, newMeta := range new.Principals {
+			oldMeta, exists := old.Principals[arn]
+			if !exists {
+				changes.PrincipalsAdded = append(changes.PrincipalsAdded, arn)
+			} else if oldMeta.PolicyHash != newMeta.PolicyHash {
+				changes.PrincipalsModified = append(changes.PrincipalsModified, arn)
+			}
+		}
+		for arn := range old.Principals {
+			if _, exists := new.Principals[arn]; !exists {
+				changes.PrincipalsRemoved = append(changes.PrincipalsRemoved, arn)
+			}
+		}
+	}
+
 	return changes
 }
 
 // HasChanges returns true if the changeset contains any changes
 func (cs *ChangeSet) HasChanges() bool {
-	return len(cs.Added) > 0 || len(cs.Removed) > 0 || len(cs.Modified) > 0
+	return len(cs.Added) > 0 || len(cs.Removed) > 0 || len(cs.Modified) > 0 ||
+		len(cs.PrincipalsAdded) > 0 || len(cs.PrincipalsRemoved) > 0 || len(cs.PrincipalsModified) > 0
 }
 
 // TotalChanges returns the total number of changes
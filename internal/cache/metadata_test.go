@@ -152,6 +152,36 @@ func TestExtractMetadata(t *testing.T) {
 	}
 }
 
+// TestExtractMetadata_ETag tests that resource fingerprints carry through to
+// the metadata used for incremental collection
+func TestExtractMetadata_ETag(t *testing.T) {
+	result := &types.CollectionResult{
+		AccountID:   "123456789012",
+		CollectedAt: time.Now(),
+		Resources: []*types.Resource{
+			{
+				ARN:  "arn:aws:s3:::bucket1",
+				Type: types.ResourceTypeS3,
+				ETag: "2024-01-15T10:00:00Z",
+			},
+		},
+	}
+
+	metadata := ExtractMetadata(result)
+	if metadata == nil {
+		t.Fatal("ExtractMetadata() returned nil")
+	}
+
+	resourceMeta, exists := metadata.Resources["arn:aws:s3:::bucket1"]
+	if !exists {
+		t.Fatal("ExtractMetadata() missing resource metadata for bucket1")
+	}
+
+	if resourceMeta.ETag != "2024-01-15T10:00:00Z" {
+		t.Errorf("ExtractMetadata() ETag = %q, want the resource fingerprint", resourceMeta.ETag)
+	}
+}
+
 // TestExtractMetadata_NilResult tests handling of nil result
 func TestExtractMetadata_NilResult(t *testing.T) {
 	metadata := ExtractMetadata(nil)
@@ -334,3 +364,158 @@ func TestChangeSet_HasChanges(t *testing.T) {
 		t.Error("ChangeSet with additions should have changes")
 	}
 }
+
+func TestComputeParentOUsHash(t *testing.T) {
+	hierarchy := &types.OUHierarchy{
+		AccountID: "123456789012",
+		ParentOUs: []string{"ou-ab12-child", "ou-ab12-parent"},
+	}
+
+	hash1 := ComputeParentOUsHash(hierarchy)
+	if hash1 == "" {
+		t.Error("ComputeParentOUsHash() returned empty string for valid hierarchy")
+	}
+
+	// Same hierarchy produces same hash
+	hash2 := ComputeParentOUsHash(hierarchy)
+	if hash1 != hash2 {
+		t.Error("ComputeParentOUsHash() not deterministic")
+	}
+
+	// A moved account (different OU path) produces a different hash
+	moved := &types.OUHierarchy{
+		AccountID: "123456789012",
+		ParentOUs: []string{"ou-ab12-other", "ou-ab12-parent"},
+	}
+	if ComputeParentOUsHash(moved) == hash1 {
+		t.Error("ComputeParentOUsHash() should differ when the OU path changes")
+	}
+
+	// Nil hierarchy hashes to empty
+	if ComputeParentOUsHash(nil) != "" {
+		t.Error("ComputeParentOUsHash(nil) should return empty string")
+	}
+}
+
+func TestDetectChanges_Principals(t *testing.T) {
+	makeResult := func(principals ...*types.Principal) *types.CollectionResult {
+		return &types.CollectionResult{
+			AccountID:   "123456789012",
+			CollectedAt: time.Now(),
+			Principals:  principals,
+		}
+	}
+
+	alice := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/alice",
+		Type: types.PrincipalTypeUser,
+		Name: "alice",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "*"},
+				},
+			},
+		},
+	}
+	bob := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/bob",
+		Type: types.PrincipalTypeUser,
+		Name: "bob",
+	}
+
+	// Bob removed, Carol added, Alice's policy broadened
+	aliceChanged := &types.Principal{
+		ARN:  alice.ARN,
+		Type: types.PrincipalTypeUser,
+		Name: "alice",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{Effect: types.EffectAllow, Action: "s3:*", Resource: "*"},
+				},
+			},
+		},
+	}
+	carol := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/carol",
+		Type: types.PrincipalTypeUser,
+		Name: "carol",
+	}
+
+	old := ExtractMetadata(makeResult(alice, bob))
+	new := ExtractMetadata(makeResult(aliceChanged, carol))
+
+	changes := DetectChanges(old, new)
+
+	if len(changes.PrincipalsAdded) != 1 || changes.PrincipalsAdded[0] != carol.ARN {
+		t.Errorf("PrincipalsAdded = %v, want [%s]", changes.PrincipalsAdded, carol.ARN)
+	}
+	if len(changes.PrincipalsRemoved) != 1 || changes.PrincipalsRemoved[0] != bob.ARN {
+		t.Errorf("PrincipalsRemoved = %v, want [%s]", changes.PrincipalsRemoved, bob.ARN)
+	}
+	if len(changes.PrincipalsModified) != 1 || changes.PrincipalsModified[0] != alice.ARN {
+		t.Errorf("PrincipalsModified = %v, want [%s]", changes.PrincipalsModified, alice.ARN)
+	}
+	if !changes.HasChanges() {
+		t.Error("HasChanges() should report principal-only changes")
+	}
+}
+
+func TestDetectChanges_PrincipalsMissingInOldMetadata(t *testing.T) {
+	principal := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/alice",
+		Type: types.PrincipalTypeUser,
+		Name: "alice",
+	}
+	old := ExtractMetadata(&types.CollectionResult{AccountID: "123456789012", Principals: []*types.Principal{principal}})
+	new := ExtractMetadata(&types.CollectionResult{AccountID: "123456789012", Principals: []*types.Principal{principal}})
+
+	// Simulate a metadata file written before per-principal hashing existed:
+	// the diff must not report every principal as added
+	old.Principals = nil
+
+	changes := DetectChanges(old, new)
+	if len(changes.PrincipalsAdded) != 0 || len(changes.PrincipalsRemoved) != 0 || len(changes.PrincipalsModified) != 0 {
+		t.Errorf("expected no principal changes without old metadata, got %+v", changes)
+	}
+}
+
+func TestComputePrincipalPolicyHash(t *testing.T) {
+	principal := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/alice",
+		Type: types.PrincipalTypeUser,
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "*"},
+				},
+			},
+		},
+	}
+
+	hash1 := ComputePrincipalPolicyHash(principal)
+	hash2 := ComputePrincipalPolicyHash(principal)
+	if hash1 == "" || hash1 != hash2 {
+		t.Errorf("hash should be stable and non-empty, got %q and %q", hash1, hash2)
+	}
+
+	// A boundary change must alter the hash
+	withBoundary := *principal
+	withBoundary.PermissionsBoundary = &types.PolicyDocument{
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{Effect: types.EffectAllow, Action: "s3:*", Resource: "*"},
+		},
+	}
+	if ComputePrincipalPolicyHash(&withBoundary) == hash1 {
+		t.Error("boundary change should change the hash")
+	}
+
+	if ComputePrincipalPolicyHash(nil) != "" {
+		t.Error("ComputePrincipalPolicyHash(nil) should return empty string")
+	}
+}
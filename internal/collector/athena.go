@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/athena"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// collectAthenaResources collects Athena workgroups so queries can target them
+// (workgroups have no resource policies; access is IAM- or LF-governed)
+func (c *Collector) collectAthenaResources(ctx context.Context, accountID string) ([]*types.Resource, error) {
+	var resources []*types.Resource
+
+	athenaClient := athena.NewFromConfig(c.baseCfg)
+
+	paginator := athena.NewListWorkGroupsPaginator(athenaClient, &athena.ListWorkGroupsInput{})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			// If we don't have Athena permissions, that's OK - just return empty
+			if c.debug {
+				fmt.Printf("DEBUG: Failed to list Athena workgroups (may lack permissions): %v\n", err)
+			}
+			return resources, nil
+		}
+
+		for _, wg := range page.WorkGroups {
+			if wg.Name == nil {
+				continue
+			}
+
+			resources = append(resources, &types.Resource{
+				ARN:       fmt.Sprintf("arn:aws:athena:%s:%s:workgroup/%s", c.region, accountID, *wg.Name),
+				Type:      types.ResourceTypeAthena,
+				Name:      *wg.Name,
+				Region:    c.region,
+				AccountID: accountID,
+			})
+		}
+	}
+
+	if c.debug {
+		fmt.Printf("DEBUG: Found %d Athena workgroups\n", len(resources))
+	}
+
+	return resources, nil
+}
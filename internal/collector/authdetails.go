@@ -0,0 +1,323 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// collectIAMPrincipals gathers users, roles and groups plus the
+// userARN -> groupARNs membership map. The default path reads everything
+// from GetAccountAuthorizationDetails, which returns all principals with
+// their inline and attached policy documents in a handful of paginated
+// calls instead of dozens of List/Get calls per principal. The legacy
+// per-principal walk remains available via WithLegacyCollection, and is
+// also the automatic fallback when the single-call API is denied
+func (c *Collector) collectIAMPrincipals(ctx context.Context) ([]*types.Principal, []*types.Principal, []*types.Principal, map[string][]string, error) {
+	if !c.legacyCollection {
+		users, roles, groups, groupMemberships, err := c.collectIAMViaAuthDetails(ctx)
+		if err == nil || errors.Is(err, ErrAPIBudgetExceeded) {
+			return users, roles, groups, groupMemberships, err
+		}
+		if c.debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: GetAccountAuthorizationDetails unavailable (%v), falling back to per-principal collection\n", err)
+		}
+	}
+
+	users, err := c.collectUsers(ctx)
+	if err != nil {
+		return users, nil, nil, nil, fmt.Errorf("failed to collect users: %w", err)
+	}
+
+	roles, err := c.collectRoles(ctx)
+	if err != nil {
+		return users, roles, nil, nil, fmt.Errorf("failed to collect roles: %w", err)
+	}
+
+	groups, groupMemberships, err := c.collectGroups(ctx)
+	if err != nil {
+		return users, roles, groups, nil, fmt.Errorf("failed to collect groups: %w", err)
+	}
+
+	return users, roles, groups, groupMemberships, nil
+}
+
+// collectIAMViaAuthDetails collects all IAM principals and their policies
+// through iam:GetAccountAuthorizationDetails
+func (c *Collector) collectIAMViaAuthDetails(ctx context.Context) ([]*types.Principal, []*types.Principal, []*types.Principal, map[string][]string, error) {
+	if c.debug {
+		fmt.Fprintln(os.Stderr, "DEBUG: Collecting IAM via GetAccountAuthorizationDetails...")
+	}
+
+	var (
+		userDetails   []iamtypes.UserDetail
+		roleDetails   []iamtypes.RoleDetail
+		groupDetails  []iamtypes.GroupDetail
+		policyDetails []iamtypes.ManagedPolicyDetail
+	)
+
+	paginator := iam.NewGetAccountAuthorizationDetailsPaginator(c.iamClient, &iam.GetAccountAuthorizationDetailsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to get account authorization details: %w", err)
+		}
+		userDetails = append(userDetails, page.UserDetailList...)
+		roleDetails = append(roleDetails, page.RoleDetailList...)
+		groupDetails = append(groupDetails, page.GroupDetailList...)
+		policyDetails = append(policyDetails, page.Policies...)
+		c.reportProgress("IAM authorization details", len(userDetails)+len(roleDetails)+len(groupDetails), 0)
+	}
+
+	// Parse the managed policy documents once up front; attachments below
+	// reference them by ARN
+	managedDocs := c.parseManagedPolicyDetails(policyDetails)
+
+	// GroupList on each user carries group names, not ARNs
+	groupARNsByName := make(map[string]string)
+	for _, group := range groupDetails {
+		if group.Arn != nil && group.GroupName != nil {
+			groupARNsByName[*group.GroupName] = *group.Arn
+		}
+	}
+
+	var users []*types.Principal
+	groupMemberships := make(map[string][]string) // userARN -> []groupARN
+	for _, user := range userDetails {
+		if user.Arn == nil || user.UserName == nil {
+			continue
+		}
+
+		principal := &types.Principal{
+			ARN:       *user.Arn,
+			Type:      types.PrincipalTypeUser,
+			Name:      *user.UserName,
+			AccountID: extractAccountIDFromARN(*user.Arn),
+			Policies:  []types.PolicyDocument{},
+		}
+		principal.Policies = append(principal.Policies, c.parseInlinePolicyDetails(user.UserPolicyList, *user.UserName)...)
+		principal.Policies = append(principal.Policies, c.resolveAttachedPolicies(ctx, user.AttachedManagedPolicies, managedDocs)...)
+		principal.PermissionsBoundary = c.resolvePermissionsBoundary(ctx, user.PermissionsBoundary, managedDocs)
+
+		for _, groupName := range user.GroupList {
+			if groupARN, ok := groupARNsByName[groupName]; ok {
+				groupMemberships[*user.Arn] = append(groupMemberships[*user.Arn], groupARN)
+			}
+		}
+
+		users = append(users, principal)
+	}
+	c.reportProgress("IAM users", len(users), 0)
+
+	var roles []*types.Principal
+	for _, role := range roleDetails {
+		if role.Arn == nil || role.RoleName == nil {
+			continue
+		}
+
+		principal := &types.Principal{
+			ARN:       *role.Arn,
+			Type:      types.PrincipalTypeRole,
+			Name:      *role.RoleName,
+			AccountID: extractAccountIDFromARN(*role.Arn),
+			Policies:  []types.PolicyDocument{},
+		}
+
+		if role.AssumeRolePolicyDocument != nil {
+			trustPolicy, err := c.parsePolicy(*role.AssumeRolePolicyDocument)
+			if err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("failed to parse trust policy for role %s: %w", *role.RoleName, err)
+			}
+			principal.TrustPolicy = trustPolicy
+		}
+
+		if role.RoleLastUsed != nil {
+			principal.RoleLastUsed = role.RoleLastUsed.LastUsedDate
+		}
+
+		principal.Policies = append(principal.Policies, c.parseInlinePolicyDetails(role.RolePolicyList, *role.RoleName)...)
+		principal.Policies = append(principal.Policies, c.resolveAttachedPolicies(ctx, role.AttachedManagedPolicies, managedDocs)...)
+		principal.PermissionsBoundary = c.resolvePermissionsBoundary(ctx, role.PermissionsBoundary, managedDocs)
+
+		roles = append(roles, principal)
+	}
+	c.reportProgress("IAM roles", len(roles), 0)
+
+	var groups []*types.Principal
+	for _, group := range groupDetails {
+		if group.Arn == nil || group.GroupName == nil {
+			continue
+		}
+
+		principal := &types.Principal{
+			ARN:       *group.Arn,
+			Type:      types.PrincipalTypeGroup,
+			Name:      *group.GroupName,
+			AccountID: extractAccountIDFromARN(*group.Arn),
+			Policies:  []types.PolicyDocument{},
+		}
+		principal.Policies = append(principal.Policies, c.parseInlinePolicyDetails(group.GroupPolicyList, *group.GroupName)...)
+		principal.Policies = append(principal.Policies, c.resolveAttachedPolicies(ctx, group.AttachedManagedPolicies, managedDocs)...)
+
+		groups = append(groups, principal)
+	}
+	c.reportProgress("IAM groups", len(groups), 0)
+
+	return users, roles, groups, groupMemberships, nil
+}
+
+// parseManagedPolicyDetails parses the managed policy documents included in
+// the authorization-details payload, keyed by policy ARN. Parsed documents
+// also seed the managed-policy cache so later getManagedPolicyDocument
+// lookups (e.g. for a boundary policy) cost no API call
+func (c *Collector) parseManagedPolicyDetails(details []iamtypes.ManagedPolicyDetail) map[string]*types.PolicyDocument {
+	docs := make(map[string]*types.PolicyDocument)
+
+	for _, detail := range details {
+		if detail.Arn == nil {
+			continue
+		}
+		arn := *detail.Arn
+
+		versionID := ""
+		if detail.DefaultVersionId != nil {
+			versionID = *detail.DefaultVersionId
+		}
+
+		// The payload carries every stored version with its document; parse
+		// the default version as the effective policy and keep the rest as
+		// history. Matching getPolicyVersions, the default version's entry
+		// omits the document since the effective policy already holds it
+		var doc *types.PolicyDocument
+		var versions []types.PolicyVersion
+		for _, version := range detail.PolicyVersionList {
+			if version.Document == nil {
+				continue
+			}
+			parsed, err := c.parsePolicy(*version.Document)
+			if err != nil {
+				if c.debug {
+					fmt.Fprintf(os.Stderr, "DEBUG: Failed to parse version of managed policy %s: %v\n", arn, err)
+				}
+				continue
+			}
+
+			entry := types.PolicyVersion{
+				IsDefault:  version.IsDefaultVersion,
+				CreateDate: version.CreateDate,
+			}
+			if version.VersionId != nil {
+				entry.VersionID = *version.VersionId
+			}
+			if version.IsDefaultVersion {
+				doc = parsed
+			} else {
+				entry.Document = parsed
+			}
+			versions = append(versions, entry)
+		}
+		if doc == nil {
+			continue
+		}
+
+		doc.SourcePolicyARN = arn
+		if detail.PolicyName != nil {
+			doc.SourcePolicyName = *detail.PolicyName
+		}
+		if c.allPolicyVersions && !strings.HasPrefix(arn, "arn:aws:iam::aws:policy/") && len(versions) > 1 {
+			doc.PolicyVersions = versions
+		}
+
+		docs[arn] = doc
+
+		if c.managedPolicyCache == nil {
+			c.managedPolicyCache = make(map[string]*types.PolicyDocument)
+		}
+		c.managedPolicyCache[arn+":"+versionID] = doc
+	}
+
+	return docs
+}
+
+// parseInlinePolicyDetails parses the inline policy documents embedded in an
+// authorization-details principal entry. Unparseable documents are skipped
+// with a debug log rather than failing the whole collection
+func (c *Collector) parseInlinePolicyDetails(details []iamtypes.PolicyDetail, principalName string) []types.PolicyDocument {
+	var policies []types.PolicyDocument
+
+	for _, detail := range details {
+		if detail.PolicyDocument == nil {
+			continue
+		}
+		doc, err := c.parsePolicy(*detail.PolicyDocument)
+		if err != nil {
+			if c.debug {
+				name := ""
+				if detail.PolicyName != nil {
+					name = *detail.PolicyName
+				}
+				fmt.Fprintf(os.Stderr, "DEBUG: Failed to parse inline policy %s for %s: %v\n", name, principalName, err)
+			}
+			continue
+		}
+		policies = append(policies, *doc)
+	}
+
+	return policies
+}
+
+// resolveAttachedPolicies maps a principal's attached managed policies to
+// their parsed documents, falling back to a direct fetch for any policy the
+// authorization-details payload did not include
+func (c *Collector) resolveAttachedPolicies(ctx context.Context, attached []iamtypes.AttachedPolicy, managedDocs map[string]*types.PolicyDocument) []types.PolicyDocument {
+	var policies []types.PolicyDocument
+
+	for _, attachment := range attached {
+		if attachment.PolicyArn == nil {
+			continue
+		}
+		if doc, ok := managedDocs[*attachment.PolicyArn]; ok {
+			policies = append(policies, *doc)
+			continue
+		}
+
+		doc, err := c.getManagedPolicyDocument(ctx, *attachment.PolicyArn)
+		if err != nil {
+			if c.debug {
+				fmt.Fprintf(os.Stderr, "DEBUG: Failed to get managed policy %s: %v\n", *attachment.PolicyArn, err)
+			}
+			continue
+		}
+		policies = append(policies, *doc)
+	}
+
+	return policies
+}
+
+// resolvePermissionsBoundary resolves an attached permissions boundary to
+// its policy document. Best effort: a missing boundary document degrades to
+// analysis without the boundary, matching the legacy path
+func (c *Collector) resolvePermissionsBoundary(ctx context.Context, boundary *iamtypes.AttachedPermissionsBoundary, managedDocs map[string]*types.PolicyDocument) *types.PolicyDocument {
+	if boundary == nil || boundary.PermissionsBoundaryArn == nil {
+		return nil
+	}
+
+	if doc, ok := managedDocs[*boundary.PermissionsBoundaryArn]; ok {
+		return doc
+	}
+
+	doc, err := c.getManagedPolicyDocument(ctx, *boundary.PermissionsBoundaryArn)
+	if err != nil {
+		if c.debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: Failed to get permission boundary %s: %v\n", *boundary.PermissionsBoundaryArn, err)
+		}
+		return nil
+	}
+	return doc
+}
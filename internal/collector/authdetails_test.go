@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestParseManagedPolicyDetails(t *testing.T) {
+	// GetAccountAuthorizationDetails returns URL-encoded documents
+	defaultDoc := url.QueryEscape(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`)
+	oldDoc := url.QueryEscape(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`)
+
+	details := []iamtypes.ManagedPolicyDetail{
+		{
+			Arn:              aws.String("arn:aws:iam::123456789012:policy/ReadOnly"),
+			PolicyName:       aws.String("ReadOnly"),
+			DefaultVersionId: aws.String("v2"),
+			PolicyVersionList: []iamtypes.PolicyVersion{
+				{VersionId: aws.String("v2"), IsDefaultVersion: true, Document: &defaultDoc},
+				{VersionId: aws.String("v1"), IsDefaultVersion: false, Document: &oldDoc},
+			},
+		},
+	}
+
+	c := &Collector{managedPolicyCache: make(map[string]*types.PolicyDocument)}
+	docs := c.parseManagedPolicyDetails(details)
+
+	doc, ok := docs["arn:aws:iam::123456789012:policy/ReadOnly"]
+	if !ok {
+		t.Fatalf("expected policy in parsed map, got %v", docs)
+	}
+	if doc.SourcePolicyARN != "arn:aws:iam::123456789012:policy/ReadOnly" {
+		t.Errorf("SourcePolicyARN = %s", doc.SourcePolicyARN)
+	}
+	if doc.SourcePolicyName != "ReadOnly" {
+		t.Errorf("SourcePolicyName = %s", doc.SourcePolicyName)
+	}
+	// The default version's document is the effective policy
+	if len(doc.Statements) != 1 || doc.Statements[0].Action != "s3:GetObject" {
+		t.Errorf("unexpected effective policy statements: %+v", doc.Statements)
+	}
+	// Version history is only kept under --all-policy-versions
+	if doc.PolicyVersions != nil {
+		t.Errorf("expected no version history without allPolicyVersions, got %v", doc.PolicyVersions)
+	}
+
+	// The parsed document seeds the managed-policy cache for boundary lookups
+	if _, ok := c.managedPolicyCache["arn:aws:iam::123456789012:policy/ReadOnly:v2"]; !ok {
+		t.Error("expected parsed policy to seed managedPolicyCache")
+	}
+}
+
+func TestParseManagedPolicyDetails_AllVersions(t *testing.T) {
+	defaultDoc := url.QueryEscape(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`)
+	oldDoc := url.QueryEscape(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`)
+
+	details := []iamtypes.ManagedPolicyDetail{
+		{
+			Arn:              aws.String("arn:aws:iam::123456789012:policy/ReadOnly"),
+			PolicyName:       aws.String("ReadOnly"),
+			DefaultVersionId: aws.String("v2"),
+			PolicyVersionList: []iamtypes.PolicyVersion{
+				{VersionId: aws.String("v2"), IsDefaultVersion: true, Document: &defaultDoc},
+				{VersionId: aws.String("v1"), IsDefaultVersion: false, Document: &oldDoc},
+			},
+		},
+	}
+
+	c := &Collector{allPolicyVersions: true, managedPolicyCache: make(map[string]*types.PolicyDocument)}
+	docs := c.parseManagedPolicyDetails(details)
+
+	doc := docs["arn:aws:iam::123456789012:policy/ReadOnly"]
+	if doc == nil {
+		t.Fatal("expected policy in parsed map")
+	}
+	if len(doc.PolicyVersions) != 2 {
+		t.Fatalf("expected 2 versions in history, got %d", len(doc.PolicyVersions))
+	}
+	// Matching getPolicyVersions, the default entry omits its document
+	for _, version := range doc.PolicyVersions {
+		if version.IsDefault && version.Document != nil {
+			t.Error("default version entry should not duplicate the document")
+		}
+		if !version.IsDefault && version.Document == nil {
+			t.Error("non-default version entry should carry its document")
+		}
+	}
+}
+
+func TestParseInlinePolicyDetails(t *testing.T) {
+	good := url.QueryEscape(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sqs:SendMessage","Resource":"*"}]}`)
+	bad := "not-a-policy"
+
+	c := &Collector{}
+	policies := c.parseInlinePolicyDetails([]iamtypes.PolicyDetail{
+		{PolicyName: aws.String("good"), PolicyDocument: &good},
+		{PolicyName: aws.String("bad"), PolicyDocument: &bad},
+		{PolicyName: aws.String("empty")},
+	}, "alice")
+
+	// The unparseable and empty entries are skipped, not fatal
+	if len(policies) != 1 {
+		t.Fatalf("expected 1 parsed policy, got %d", len(policies))
+	}
+	if action := policies[0].Statements[0].Action; action != "sqs:SendMessage" {
+		t.Errorf("unexpected action: %v", action)
+	}
+}
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ErrAPIBudgetExceeded is returned by SDK calls once the --max-api-calls
+// budget is spent. Collect treats it as a signal to stop gracefully with a
+// partial result rather than as a collection failure.
+var ErrAPIBudgetExceeded = errors.New("API call budget exceeded")
+
+// apiCallCounter counts outgoing SDK API calls per service and optionally
+// enforces a global budget across a whole collection run. It is installed as
+// shared middleware on the AWS config, so every client built from that
+// config (including assumed-role clients for member accounts) is covered.
+type apiCallCounter struct {
+	mu         sync.Mutex
+	total      int
+	perService map[string]int
+	max        int // 0 = unlimited
+	exceeded   bool
+}
+
+func newAPICallCounter() *apiCallCounter {
+	return &apiCallCounter{
+		perService: make(map[string]int),
+	}
+}
+
+// setMax sets the global call budget (0 disables enforcement)
+func (a *apiCallCounter) setMax(max int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.max = max
+}
+
+// limit returns the configured budget (0 = unlimited)
+func (a *apiCallCounter) limit() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.max
+}
+
+// wasExceeded reports whether any call was refused due to the budget
+func (a *apiCallCounter) wasExceeded() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.exceeded
+}
+
+// record counts one call against the budget, returning ErrAPIBudgetExceeded
+// once the budget is spent
+func (a *apiCallCounter) record(service string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.max > 0 && a.total >= a.max {
+		a.exceeded = true
+		return fmt.Errorf("%w (limit: %d)", ErrAPIBudgetExceeded, a.max)
+	}
+	a.total++
+	a.perService[service]++
+	return nil
+}
+
+// middleware returns a stack mutator that counts every API call and refuses
+// calls beyond the configured budget
+func (a *apiCallCounter) middleware() func(*middleware.Stack) error {
+	counter := middleware.InitializeMiddlewareFunc("APICallBudget",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			if err := a.record(awsmiddleware.GetServiceID(ctx)); err != nil {
+				return middleware.InitializeOutput{}, middleware.Metadata{}, err
+			}
+			return next.HandleInitialize(ctx, in)
+		})
+
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(counter, middleware.Before)
+	}
+}
+
+// report writes per-service call totals, sorted by call count descending
+func (a *apiCallCounter) report(w io.Writer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	type serviceCount struct {
+		service string
+		count   int
+	}
+	counts := make([]serviceCount, 0, len(a.perService))
+	for service, count := range a.perService {
+		counts = append(counts, serviceCount{service, count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].service < counts[j].service
+	})
+
+	fmt.Fprintf(w, "API calls made: %d", a.total)
+	if a.max > 0 {
+		fmt.Fprintf(w, " (budget: %d)", a.max)
+	}
+	fmt.Fprintln(w)
+	for _, sc := range counts {
+		fmt.Fprintf(w, "  %s: %d\n", sc.service, sc.count)
+	}
+}
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAPICallCounter_Unlimited(t *testing.T) {
+	counter := newAPICallCounter()
+
+	for i := 0; i < 100; i++ {
+		if err := counter.record("IAM"); err != nil {
+			t.Fatalf("record() error with no budget: %v", err)
+		}
+	}
+	if counter.wasExceeded() {
+		t.Error("Counter without a budget must never report exceeded")
+	}
+}
+
+func TestAPICallCounter_BudgetEnforced(t *testing.T) {
+	counter := newAPICallCounter()
+	counter.setMax(3)
+
+	for i := 0; i < 3; i++ {
+		if err := counter.record("S3"); err != nil {
+			t.Fatalf("record() call %d failed within budget: %v", i+1, err)
+		}
+	}
+
+	err := counter.record("S3")
+	if err == nil {
+		t.Fatal("Expected an error once the budget is spent")
+	}
+	if !errors.Is(err, ErrAPIBudgetExceeded) {
+		t.Errorf("Expected ErrAPIBudgetExceeded, got %v", err)
+	}
+	if !counter.wasExceeded() {
+		t.Error("Expected wasExceeded() to be true after a refused call")
+	}
+}
+
+func TestAPICallCounter_Report(t *testing.T) {
+	counter := newAPICallCounter()
+	counter.setMax(10)
+	counter.record("IAM")
+	counter.record("IAM")
+	counter.record("S3")
+
+	var buf bytes.Buffer
+	counter.report(&buf)
+	out := buf.String()
+
+	if !strings.Contains(out, "API calls made: 3 (budget: 10)") {
+		t.Errorf("Report missing total line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "IAM: 2") || !strings.Contains(out, "S3: 1") {
+		t.Errorf("Report missing per-service counts, got:\n%s", out)
+	}
+	// IAM (2 calls) must sort before S3 (1 call)
+	if strings.Index(out, "IAM") > strings.Index(out, "S3") {
+		t.Errorf("Expected services sorted by call count, got:\n%s", out)
+	}
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// orgCheckpointPath returns the file used to persist in-progress organization
+// collections so an interrupted run can be resumed
+func orgCheckpointPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	dir := filepath.Join(home, ".aws-access-map")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	return filepath.Join(dir, "org-checkpoint.json"), nil
+}
+
+// saveOrgCheckpoint persists the in-progress result atomically (write to a
+// temp file, then rename) so a crash mid-write can't corrupt the checkpoint
+func saveOrgCheckpoint(result *types.MultiAccountCollectionResult) error {
+	path, err := orgCheckpointPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to finalize checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// loadOrgCheckpoint returns the saved in-progress result, or nil if no
+// checkpoint exists
+func loadOrgCheckpoint() (*types.MultiAccountCollectionResult, error) {
+	path, err := orgCheckpointPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var result types.MultiAccountCollectionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+
+	return &result, nil
+}
+
+// clearOrgCheckpoint removes the checkpoint after a completed collection
+func clearOrgCheckpoint() error {
+	path, err := orgCheckpointPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestOrgCheckpoint_RoundTrip(t *testing.T) {
+	// Isolate the checkpoint in a temp home directory
+	t.Setenv("HOME", t.TempDir())
+
+	result := &types.MultiAccountCollectionResult{
+		Accounts: map[string]*types.CollectionResult{
+			"111111111111": {
+				AccountID: "111111111111",
+				Principals: []*types.Principal{
+					{
+						ARN:  "arn:aws:iam::111111111111:user/alice",
+						Type: types.PrincipalTypeUser,
+						Name: "alice",
+					},
+				},
+			},
+		},
+		OUHierarchy: map[string]*types.OUHierarchy{
+			"111111111111": {AccountID: "111111111111", ParentOUs: []string{"ou-1234"}},
+		},
+		CollectedAt:    time.Now(),
+		OrganizationID: "o-example",
+		SuccessCount:   1,
+	}
+
+	if err := saveOrgCheckpoint(result); err != nil {
+		t.Fatalf("saveOrgCheckpoint() failed: %v", err)
+	}
+
+	loaded, err := loadOrgCheckpoint()
+	if err != nil {
+		t.Fatalf("loadOrgCheckpoint() failed: %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("loadOrgCheckpoint() returned nil after save")
+	}
+
+	if loaded.OrganizationID != "o-example" {
+		t.Errorf("organization ID = %s, want o-example", loaded.OrganizationID)
+	}
+	if len(loaded.Accounts) != 1 {
+		t.Fatalf("loaded %d accounts, want 1", len(loaded.Accounts))
+	}
+	account, ok := loaded.Accounts["111111111111"]
+	if !ok {
+		t.Fatal("account 111111111111 missing from checkpoint")
+	}
+	if len(account.Principals) != 1 || account.Principals[0].Name != "alice" {
+		t.Errorf("unexpected principals in checkpoint: %+v", account.Principals)
+	}
+
+	// Clearing removes the checkpoint
+	if err := clearOrgCheckpoint(); err != nil {
+		t.Fatalf("clearOrgCheckpoint() failed: %v", err)
+	}
+	loaded, err = loadOrgCheckpoint()
+	if err != nil {
+		t.Fatalf("loadOrgCheckpoint() after clear failed: %v", err)
+	}
+	if loaded != nil {
+		t.Error("loadOrgCheckpoint() returned data after clear, want nil")
+	}
+}
+
+func TestLoadOrgCheckpoint_NoCheckpoint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	loaded, err := loadOrgCheckpoint()
+	if err != nil {
+		t.Fatalf("loadOrgCheckpoint() failed: %v", err)
+	}
+	if loaded != nil {
+		t.Error("loadOrgCheckpoint() returned data with no checkpoint saved")
+	}
+}
+
+func TestClearOrgCheckpoint_NoCheckpoint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Clearing a nonexistent checkpoint is not an error
+	if err := clearOrgCheckpoint(); err != nil {
+		t.Errorf("clearOrgCheckpoint() failed: %v", err)
+	}
+}
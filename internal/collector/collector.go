@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	organizationstypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
@@ -20,10 +22,17 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/sync/errgroup"
 	"github.com/pfrederiksen/aws-access-map/internal/policy"
 	"github.com/pfrederiksen/aws-access-map/pkg/types"
 )
 
+// ProgressFunc receives collection progress updates. done is the number of
+// items (or steps) completed so far within the stage; total is the step count
+// when known and 0 when the stage streams paginated results of unknown size
+type ProgressFunc func(stage string, done, total int)
+
 // Collector handles fetching data from AWS APIs
 type Collector struct {
 	iamClient            *iam.Client
@@ -38,11 +47,29 @@ type Collector struct {
 	profile              string
 	debug                bool
 	includeSCPs          bool
+	iamOnly              bool       // Skip resource-policy collection (identity-only analysis)
+	allPolicyVersions    bool       // Also fetch historical versions of customer-managed policies
+	resume               bool       // Resume an interrupted org collection from checkpoint
+	legacyCollection     bool       // Collect IAM with per-principal List/Get calls instead of GetAccountAuthorizationDetails
+	assumeRoleARN        string     // Role to assume before collecting (single-account cross-account audits)
+	externalID           string     // External ID passed on sts:AssumeRole, for confused-deputy protected roles
+	resourceConcurrency  int        // Per-service resource collector parallelism (0 = default)
+	cachedOUHierarchy    *types.OUHierarchy // Previously collected OU hierarchy to reuse, skipping the ListParents walk
+	extraRegions         []string   // Additional regions to collect regional resources from (global services run once)
+	reusableResources    map[string]*types.Resource // Previously collected resources keyed by ARN, reused when their ETag still matches
+	reusedResources      int          // How many resources the last Collect served from reusableResources
+	progress             ProgressFunc // Optional per-stage progress callback (nil = no reporting)
 	baseCfg              aws.Config // Store config for multi-account use
+	apiCalls             *apiCallCounter // Shared API call counter/budget across all clients
+
+	// Interned managed-policy documents keyed by policy ARN+version, so
+	// policies attached to many principals are fetched and stored once
+	managedPolicyCache map[string]*types.PolicyDocument
 }
 
-// New creates a new Collector instance
-func New(ctx context.Context, region, profile string, debug bool, includeSCPs bool) (*Collector, error) {
+// New creates a new Collector instance. An optional RetryConfig tunes how
+// throttled API calls back off and retry (at most one may be given)
+func New(ctx context.Context, region, profile string, debug bool, includeSCPs bool, retryCfg ...RetryConfig) (*Collector, error) {
 	var opts []func(*config.LoadOptions) error
 
 	if profile != "" {
@@ -55,6 +82,20 @@ func New(ctx context.Context, region, profile string, debug bool, includeSCPs bo
 	}
 	opts = append(opts, config.WithRegion(region))
 
+	// Count every API call through shared middleware so --max-api-calls can
+	// budget the whole run; clients built later from this config inherit it
+	apiCalls := newAPICallCounter()
+	opts = append(opts, config.WithAPIOptions([]func(*middleware.Stack) error{apiCalls.middleware()}))
+
+	// Throttled calls retry with exponential backoff instead of aborting the
+	// whole collection; clients built later from this config inherit the
+	// retryer too
+	rc := RetryConfig{}
+	if len(retryCfg) > 0 {
+		rc = retryCfg[0]
+	}
+	opts = append(opts, config.WithRetryer(func() aws.Retryer { return newRetryer(rc) }))
+
 	cfg, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
@@ -74,114 +115,296 @@ func New(ctx context.Context, region, profile string, debug bool, includeSCPs bo
 		debug:                debug,
 		includeSCPs:          includeSCPs,
 		baseCfg:              cfg,
+		apiCalls:             apiCalls,
+		managedPolicyCache:   make(map[string]*types.PolicyDocument),
 	}, nil
 }
 
-// Collect fetches all relevant AWS data
-func (c *Collector) Collect(ctx context.Context) (*types.CollectionResult, error) {
-	result := &types.CollectionResult{
-		Regions:     []string{c.region},
-		CollectedAt: time.Now(),
-	}
+// WithMaxAPICalls caps the total number of AWS API calls the collector may
+// make (0 = unlimited). When the budget runs out, collection stops
+// gracefully and returns whatever was gathered so far
+func (c *Collector) WithMaxAPICalls(max int) *Collector {
+	c.apiCalls.setMax(max)
+	return c
+}
 
-	// Get account ID
-	accountID, err := c.getAccountID(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get account ID: %w", err)
-	}
-	result.AccountID = accountID
+// WithResourceConcurrency sets how many per-service resource collectors may
+// run at once (0 = default)
+func (c *Collector) WithResourceConcurrency(n int) *Collector {
+	c.resourceConcurrency = n
+	return c
+}
 
-	// Collect IAM users
-	users, err := c.collectUsers(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect users: %w", err)
+// WithIAMOnly configures the collector to skip resource-policy collection,
+// collecting only IAM users, roles, and groups for fast identity-only analysis
+func (c *Collector) WithIAMOnly(iamOnly bool) *Collector {
+	c.iamOnly = iamOnly
+	return c
+}
+
+// WithAllPolicyVersions configures the collector to also fetch every stored
+// version of customer-managed policies (ListPolicyVersions + one
+// GetPolicyVersion per version), not just the default. Opt-in because it
+// multiplies IAM API calls; useful for forensic/audit analysis of how a
+// policy changed over time
+func (c *Collector) WithAllPolicyVersions(all bool) *Collector {
+	c.allPolicyVersions = all
+	return c
+}
+
+// WithResume configures organization collection to pick up from the last
+// checkpoint, skipping accounts that were already collected
+func (c *Collector) WithResume(resume bool) *Collector {
+	c.resume = resume
+	return c
+}
+
+// WithLegacyCollection switches IAM collection back to the original
+// per-principal List/Get walk instead of GetAccountAuthorizationDetails.
+// Useful when the caller's policy grants the granular IAM read actions but
+// not iam:GetAccountAuthorizationDetails
+func (c *Collector) WithLegacyCollection(legacy bool) *Collector {
+	c.legacyCollection = legacy
+	return c
+}
+
+// WithAssumeRole makes Collect assume the given role before collecting,
+// so a single-account run can audit another account through a cross-account
+// role. Pair with WithExternalID when the role's trust policy demands one
+func (c *Collector) WithAssumeRole(roleARN string) *Collector {
+	c.assumeRoleARN = roleARN
+	return c
+}
+
+// WithExternalID sets the external ID sent on every sts:AssumeRole the
+// collector performs (WithAssumeRole and the per-account assumptions in
+// CollectOrganization). Cross-account audit roles following AWS's
+// confused-deputy guidance reject assumptions without it
+func (c *Collector) WithExternalID(id string) *Collector {
+	c.externalID = id
+	return c
+}
+
+// assumeRoleOptions returns the option set for an AssumeRoleProvider,
+// attaching the collector's external ID when one is configured
+func (c *Collector) assumeRoleOptions() []func(*stscreds.AssumeRoleOptions) {
+	if c.externalID == "" {
+		return nil
 	}
-	result.Principals = append(result.Principals, users...)
+	return []func(*stscreds.AssumeRoleOptions){
+		func(o *stscreds.AssumeRoleOptions) {
+			o.ExternalID = aws.String(c.externalID)
+		},
+	}
+}
 
-	// Collect IAM roles
-	roles, err := c.collectRoles(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect roles: %w", err)
+// applyAssumeRole swaps the collector's credentials for the role configured
+// via WithAssumeRole, rebuilding every service client from the updated base
+// config. A no-op when no role is configured; idempotent across calls
+func (c *Collector) applyAssumeRole() {
+	if c.assumeRoleARN == "" {
+		return
 	}
-	result.Principals = append(result.Principals, roles...)
 
-	// Collect IAM groups
-	groups, groupMemberships, err := c.collectGroups(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect groups: %w", err)
+	if c.debug {
+		fmt.Fprintf(os.Stderr, "DEBUG: Assuming role before collection: %s\n", c.assumeRoleARN)
 	}
-	result.Principals = append(result.Principals, groups...)
 
-	// Resolve group memberships for users
-	c.resolveGroupMemberships(users, groupMemberships)
+	creds := stscreds.NewAssumeRoleProvider(c.stsClient, c.assumeRoleARN, c.assumeRoleOptions()...)
+	cfg := c.baseCfg.Copy()
+	cfg.Credentials = aws.NewCredentialsCache(creds)
+
+	c.baseCfg = cfg
+	c.iamClient = iam.NewFromConfig(cfg)
+	c.s3Client = s3.NewFromConfig(cfg)
+	c.kmsClient = kms.NewFromConfig(cfg)
+	c.sqsClient = sqs.NewFromConfig(cfg)
+	c.snsClient = sns.NewFromConfig(cfg)
+	c.secretsManagerClient = secretsmanager.NewFromConfig(cfg)
+	c.organizationsClient = organizations.NewFromConfig(cfg)
+	c.stsClient = sts.NewFromConfig(cfg)
+	c.assumeRoleARN = ""
+}
 
-	// Collect S3 resources
-	s3Resources, err := c.collectS3Resources(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect S3 resources: %w", err)
-	}
-	result.Resources = append(result.Resources, s3Resources...)
+// WithCachedOUHierarchy seeds the collector with a previously collected OU
+// hierarchy so Collect can skip the ListParents walk. The hierarchy is only
+// used when its account matches the collected account; staleness is the
+// caller's responsibility (see cache.IncrementalCollect)
+func (c *Collector) WithCachedOUHierarchy(hierarchy *types.OUHierarchy) *Collector {
+	c.cachedOUHierarchy = hierarchy
+	return c
+}
 
-	// Collect KMS resources
-	kmsResources, err := c.collectKMSResources(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect KMS resources: %w", err)
+// WithRegions adds regions to collect regional resources (SQS, SNS, Lambda,
+// ...) from, beyond the collector's primary region. Global services (IAM, the
+// S3 bucket listing) are still collected once. The primary region and
+// duplicates are skipped, so passing the full region list is safe
+func (c *Collector) WithRegions(regions []string) *Collector {
+	seen := map[string]bool{c.region: true}
+	for _, region := range regions {
+		region = strings.TrimSpace(region)
+		if region == "" || seen[region] {
+			continue
+		}
+		seen[region] = true
+		c.extraRegions = append(c.extraRegions, region)
 	}
-	result.Resources = append(result.Resources, kmsResources...)
+	return c
+}
 
-	// Collect SQS resources
-	sqsResources, err := c.collectSQSResources(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect SQS resources: %w", err)
+// forRegion returns a copy of the collector targeting another region:
+// regional service clients are rebuilt from the base config with the region
+// overridden, while shared state (API budget, debug, progress) carries over
+func (c *Collector) forRegion(region string) *Collector {
+	cfg := c.baseCfg.Copy()
+	cfg.Region = region
+
+	regional := *c
+	regional.region = region
+	regional.baseCfg = cfg
+	regional.kmsClient = kms.NewFromConfig(cfg)
+	regional.sqsClient = sqs.NewFromConfig(cfg)
+	regional.snsClient = sns.NewFromConfig(cfg)
+	regional.secretsManagerClient = secretsmanager.NewFromConfig(cfg)
+	return &regional
+}
+
+// WithReusableResources seeds the collector with previously collected
+// resources keyed by ARN. Collectors that can fingerprint a resource from its
+// cheap list entry (currently S3, which stores the bucket creation date in
+// ETag) reuse the cached entry instead of re-fetching its policy and data
+// flows when the fingerprint is unchanged. Staleness is bounded by the
+// caller's cache max-age (see cache.IncrementalCollect)
+func (c *Collector) WithReusableResources(resources map[string]*types.Resource) *Collector {
+	c.reusableResources = resources
+	return c
+}
+
+// ReusedResourceCount reports how many resources the last Collect served from
+// the reusable set instead of re-fetching from AWS
+func (c *Collector) ReusedResourceCount() int {
+	return c.reusedResources
+}
+
+// WithProgress registers a callback invoked as collection stages advance, so
+// callers can render a progress indicator during long collections. Purely
+// observational: it never changes what is collected. The callback may be
+// invoked from multiple goroutines during resource collection
+func (c *Collector) WithProgress(fn ProgressFunc) *Collector {
+	c.progress = fn
+	return c
+}
+
+// reportProgress invokes the progress callback if one is registered
+func (c *Collector) reportProgress(stage string, done, total int) {
+	if c.progress != nil {
+		c.progress(stage, done, total)
 	}
-	result.Resources = append(result.Resources, sqsResources...)
+}
 
-	// Collect SNS resources
-	snsResources, err := c.collectSNSResources(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect SNS resources: %w", err)
+// Collect fetches all relevant AWS data
+func (c *Collector) Collect(ctx context.Context) (*types.CollectionResult, error) {
+	// Swap to the cross-account audit role first, if one was configured
+	c.applyAssumeRole()
+
+	result := &types.CollectionResult{
+		Regions:     append([]string{c.region}, c.extraRegions...),
+		CollectedAt: time.Now(),
 	}
-	result.Resources = append(result.Resources, snsResources...)
 
-	// Collect Secrets Manager resources
-	secretsResources, err := c.collectSecretsManagerResources(ctx)
+	// Get account ID
+	accountID, err := c.GetAccountID(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect Secrets Manager resources: %w", err)
+		return nil, fmt.Errorf("failed to get account ID: %w", err)
 	}
-	result.Resources = append(result.Resources, secretsResources...)
+	result.AccountID = accountID
 
-	// Collect Lambda functions
-	lambdaResources, err := c.collectLambdaResources(ctx)
+	// Collect IAM users, roles and groups. The default path gets them all
+	// (with policy documents) from GetAccountAuthorizationDetails in a few
+	// paginated calls; --legacy-collection or a denied call falls back to
+	// the per-principal List/Get walk
+	users, roles, groups, groupMemberships, err := c.collectIAMPrincipals(ctx)
+	result.Principals = append(result.Principals, users...)
+	result.Principals = append(result.Principals, roles...)
+	result.Principals = append(result.Principals, groups...)
+	if c.stopForBudget(err) {
+		c.reportAPIUsage()
+		return result, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect Lambda resources: %w", err)
+		return nil, err
 	}
-	result.Resources = append(result.Resources, lambdaResources...)
 
-	// Collect API Gateway REST APIs
-	apiGatewayResources, err := c.collectAPIGatewayResources(ctx, accountID)
+	// Resolve group memberships for users
+	c.resolveGroupMemberships(users, groupMemberships)
+
+	// Inventory customer-managed policies; attachment counts power the
+	// unused-policy report (non-fatal - inventory is supplementary)
+	managedPolicies, err := c.collectManagedPolicies(ctx)
+	if c.stopForBudget(err) {
+		c.reportAPIUsage()
+		return result, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect API Gateway resources: %w", err)
+		if c.debug {
+			fmt.Printf("DEBUG: Failed to inventory managed policies: %v\n", err)
+		}
+	} else {
+		result.ManagedPolicies = managedPolicies
 	}
-	result.Resources = append(result.Resources, apiGatewayResources...)
 
-	// Collect ECR repositories
-	ecrResources, err := c.collectECRResources(ctx)
+	// Collect Identity Center assignments (non-fatal - not all accounts use SSO)
+	ssoAssignments, ssoPrincipals, err := c.collectSSOAssignments(ctx, accountID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect ECR resources: %w", err)
+		if c.debug {
+			fmt.Printf("DEBUG: Failed to collect Identity Center assignments: %v\n", err)
+		}
+	} else {
+		result.SSOAssignments = ssoAssignments
+		result.Principals = append(result.Principals, ssoPrincipals...)
 	}
-	result.Resources = append(result.Resources, ecrResources...)
 
-	// Collect EventBridge event buses
-	eventBridgeResources, err := c.collectEventBridgeResources(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect EventBridge resources: %w", err)
+	// Collect resource policies (skipped in IAM-only mode)
+	if c.iamOnly {
+		result.ResourcesSkipped = true
+		if c.debug {
+			fmt.Println("DEBUG: IAM-only mode, skipping resource collection")
+		}
+	} else {
+		// The per-service collectors are independent of each other, so run
+		// them concurrently; the shared API call counter stays accurate
+		// because its middleware is mutex-protected
+		resources, err := c.collectResourcesConcurrently(ctx, accountID)
+		if c.stopForBudget(err) {
+			result.Resources = append(result.Resources, resources...)
+			c.reportAPIUsage()
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Resources = append(result.Resources, resources...)
+
+		// Collect Lake Formation grants on Glue data resources
+		lfGrants, err := c.collectLakeFormationGrants(ctx, accountID)
+		if c.stopForBudget(err) {
+			c.reportAPIUsage()
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect Lake Formation grants: %w", err)
+		}
+		result.LFGrants = lfGrants
 	}
-	result.Resources = append(result.Resources, eventBridgeResources...)
 
 	// Collect Service Control Policies (if enabled)
 	if c.includeSCPs {
 		// Collect SCPs with target information (for hierarchy-aware filtering)
 		scpAttachments, err := c.collectSCPsWithTargets(ctx)
+		if c.stopForBudget(err) {
+			c.reportAPIUsage()
+			return result, nil
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to collect SCPs: %w", err)
 		}
@@ -194,36 +417,196 @@ func (c *Collector) Collect(ctx context.Context) (*types.CollectionResult, error
 		}
 		result.SCPs = scps
 
-		// Get OU hierarchy for this account (for SCP filtering)
-		ouHierarchy, err := c.getOUHierarchy(ctx, accountID)
-		if err != nil {
-			// Log warning but don't fail - we'll fall back to conservative filtering
+		// Collect Resource Control Policies (resource-side guardrails)
+		rcpAttachments, err := c.collectRCPsWithTargets(ctx)
+		if err == nil {
+			result.RCPAttachments = rcpAttachments
+		}
+
+		// Get OU hierarchy for this account (for SCP filtering); a fresh
+		// cached hierarchy skips the ListParents walk entirely
+		if c.cachedOUHierarchy != nil && c.cachedOUHierarchy.AccountID == accountID {
 			if c.debug {
-				fmt.Printf("DEBUG: Failed to get OU hierarchy for account %s: %v\n", accountID, err)
+				fmt.Printf("DEBUG: Reusing cached OU hierarchy for account %s\n", accountID)
 			}
+			result.OUHierarchy = c.cachedOUHierarchy
 		} else {
-			result.OUHierarchy = ouHierarchy
+			ouHierarchy, err := c.getOUHierarchy(ctx, accountID)
+			if err != nil {
+				// Log warning but don't fail - we'll fall back to conservative filtering
+				if c.debug {
+					fmt.Printf("DEBUG: Failed to get OU hierarchy for account %s: %v\n", accountID, err)
+				}
+			} else {
+				result.OUHierarchy = ouHierarchy
+			}
 		}
 	}
 
 	// TODO: Collect groups, permission boundaries, etc.
 
+	c.reportAPIUsage()
+
 	return result, nil
 }
 
-func (c *Collector) getAccountID(ctx context.Context) (string, error) {
-	output, err := c.iamClient.GetUser(ctx, &iam.GetUserInput{})
-	if err != nil {
-		return "", err
+// defaultResourceConcurrency bounds how many per-service resource collectors
+// run at once when no explicit limit is configured
+const defaultResourceConcurrency = 4
+
+// resourceCollectorStep names one independent per-service collection call
+type resourceCollectorStep struct {
+	name    string
+	collect func(context.Context) ([]*types.Resource, error)
+}
+
+// collectResourcesConcurrently runs the independent per-service resource
+// collectors in parallel under a concurrency limit. Each goroutine writes its
+// own slot so results merge in declared service order, keeping output
+// deterministic. The first failure cancels the remaining collectors and is
+// returned alongside whatever completed, so budget errors still yield a
+// partial collection
+func (c *Collector) collectResourcesConcurrently(ctx context.Context, accountID string) ([]*types.Resource, error) {
+	steps := []resourceCollectorStep{
+		{"S3", c.collectS3Resources},
+		{"S3 Access Points", func(ctx context.Context) ([]*types.Resource, error) {
+			return c.collectS3AccessPoints(ctx, accountID)
+		}},
+		{"KMS", c.collectKMSResources},
+		{"SQS", c.collectSQSResources},
+		{"SNS", c.collectSNSResources},
+		{"Secrets Manager", c.collectSecretsManagerResources},
+		{"Lambda", c.collectLambdaResources},
+		{"API Gateway", func(ctx context.Context) ([]*types.Resource, error) {
+			return c.collectAPIGatewayResources(ctx, accountID)
+		}},
+		{"ECR", c.collectECRResources},
+		{"EventBridge", c.collectEventBridgeResources},
+		{"Athena", func(ctx context.Context) ([]*types.Resource, error) {
+			return c.collectAthenaResources(ctx, accountID)
+		}},
+		{"EFS", c.collectEFSResources},
+		{"Glacier", c.collectGlacierResources},
+		{"ECS", c.collectECSResources},
+	}
+
+	// Regional services repeat once per extra region through a region-scoped
+	// collector copy; the S3 bucket listing is global and runs only once
+	for _, region := range c.extraRegions {
+		rc := c.forRegion(region)
+		steps = append(steps,
+			resourceCollectorStep{fmt.Sprintf("S3 Access Points (%s)", region), func(ctx context.Context) ([]*types.Resource, error) {
+				return rc.collectS3AccessPoints(ctx, accountID)
+			}},
+			resourceCollectorStep{fmt.Sprintf("KMS (%s)", region), rc.collectKMSResources},
+			resourceCollectorStep{fmt.Sprintf("SQS (%s)", region), rc.collectSQSResources},
+			resourceCollectorStep{fmt.Sprintf("SNS (%s)", region), rc.collectSNSResources},
+			resourceCollectorStep{fmt.Sprintf("Secrets Manager (%s)", region), rc.collectSecretsManagerResources},
+			resourceCollectorStep{fmt.Sprintf("Lambda (%s)", region), rc.collectLambdaResources},
+			resourceCollectorStep{fmt.Sprintf("API Gateway (%s)", region), func(ctx context.Context) ([]*types.Resource, error) {
+				return rc.collectAPIGatewayResources(ctx, accountID)
+			}},
+			resourceCollectorStep{fmt.Sprintf("ECR (%s)", region), rc.collectECRResources},
+			resourceCollectorStep{fmt.Sprintf("EventBridge (%s)", region), rc.collectEventBridgeResources},
+			resourceCollectorStep{fmt.Sprintf("Athena (%s)", region), func(ctx context.Context) ([]*types.Resource, error) {
+				return rc.collectAthenaResources(ctx, accountID)
+			}},
+			resourceCollectorStep{fmt.Sprintf("EFS (%s)", region), rc.collectEFSResources},
+			resourceCollectorStep{fmt.Sprintf("Glacier (%s)", region), rc.collectGlacierResources},
+			resourceCollectorStep{fmt.Sprintf("ECS (%s)", region), rc.collectECSResources},
+		)
+	}
+
+	limit := c.resourceConcurrency
+	if limit <= 0 {
+		limit = defaultResourceConcurrency
+	}
+
+	// Wrap each step to count completions so progress shows services done out
+	// of the total, regardless of which order the goroutines finish in
+	if c.progress != nil {
+		total := len(steps)
+		var mu sync.Mutex
+		completed := 0
+		for i := range steps {
+			collect := steps[i].collect
+			steps[i].collect = func(ctx context.Context) ([]*types.Resource, error) {
+				resources, err := collect(ctx)
+				mu.Lock()
+				completed++
+				done := completed
+				mu.Unlock()
+				c.reportProgress("Resource policies", done, total)
+				return resources, err
+			}
+		}
+	}
+
+	return runResourceSteps(ctx, limit, steps)
+}
+
+// runResourceSteps executes the collection steps concurrently and merges
+// their results in declared order
+func runResourceSteps(ctx context.Context, limit int, steps []resourceCollectorStep) ([]*types.Resource, error) {
+	grp, gctx := errgroup.WithContext(ctx)
+	grp.SetLimit(limit)
+
+	collected := make([][]*types.Resource, len(steps))
+	for i, step := range steps {
+		grp.Go(func() error {
+			resources, err := step.collect(gctx)
+			if err != nil {
+				return fmt.Errorf("failed to collect %s resources: %w", step.name, err)
+			}
+			collected[i] = resources
+			return nil
+		})
 	}
+	err := grp.Wait()
 
-	// Extract account ID from ARN
-	if output.User != nil && output.User.Arn != nil {
-		// ARN format: arn:aws:iam::123456789012:user/username
-		return extractAccountIDFromARN(*output.User.Arn), nil
+	var merged []*types.Resource
+	for _, resources := range collected {
+		merged = append(merged, resources...)
 	}
+	return merged, err
+}
 
-	return "", fmt.Errorf("unable to determine account ID")
+// stopForBudget reports whether err means the API call budget ran out. When
+// it does, a partial-collection warning is printed and the caller should
+// return what it has gathered so far instead of failing
+func (c *Collector) stopForBudget(err error) bool {
+	if err == nil || !errors.Is(err, ErrAPIBudgetExceeded) {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "Warning: API call budget reached (%d calls); stopping with a partial collection\n", c.apiCalls.limit())
+	return true
+}
+
+// reportAPIUsage prints per-service API call totals. Always shown when a
+// budget is configured (the user is watching consumption), otherwise only
+// under debug
+func (c *Collector) reportAPIUsage() {
+	if c.apiCalls.limit() > 0 || c.debug {
+		c.apiCalls.report(os.Stderr)
+	}
+}
+
+// GetAccountID resolves the caller's account ID via STS GetCallerIdentity,
+// which works for any credential type - iam:GetUser only succeeds for IAM
+// users. One cheap call, so callers can consult the cache before any
+// expensive IAM listing.
+func (c *Collector) GetAccountID(ctx context.Context) (string, error) {
+	// Report the audited account, not the source of a cross-account assume
+	c.applyAssumeRole()
+
+	output, err := c.stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	if output.Account == nil {
+		return "", fmt.Errorf("unable to determine account ID")
+	}
+	return *output.Account, nil
 }
 
 func (c *Collector) collectUsers(ctx context.Context) ([]*types.Principal, error) {
@@ -274,6 +657,8 @@ func (c *Collector) collectUsers(ctx context.Context) ([]*types.Principal, error
 
 			principals = append(principals, principal)
 		}
+
+		c.reportProgress("IAM users", len(principals), 0)
 	}
 
 	return principals, nil
@@ -308,6 +693,17 @@ func (c *Collector) collectRoles(ctx context.Context) ([]*types.Principal, error
 				principal.TrustPolicy = trustPolicy
 			}
 
+			// ListRoles omits RoleLastUsed, so fetch it via GetRole. Best
+			// effort: usage telemetry is not worth failing collection over
+			roleOutput, err := c.iamClient.GetRole(ctx, &iam.GetRoleInput{RoleName: role.RoleName})
+			if err != nil {
+				if c.debug {
+					fmt.Fprintf(os.Stderr, "DEBUG: Failed to get last-used data for role %s: %v\n", *role.RoleName, err)
+				}
+			} else if roleOutput.Role != nil && roleOutput.Role.RoleLastUsed != nil {
+				principal.RoleLastUsed = roleOutput.Role.RoleLastUsed.LastUsedDate
+			}
+
 			// Get inline and attached policies for this role
 			policies, err := c.getRolePolicies(ctx, *role.RoleName)
 			if err != nil {
@@ -336,6 +732,8 @@ func (c *Collector) collectRoles(ctx context.Context) ([]*types.Principal, error
 
 			principals = append(principals, principal)
 		}
+
+		c.reportProgress("IAM roles", len(principals), 0)
 	}
 
 	return principals, nil
@@ -442,6 +840,22 @@ func (c *Collector) getManagedPolicyDocument(ctx context.Context, policyArn stri
 		return nil, err
 	}
 
+	versionID := ""
+	if policyOutput.Policy.DefaultVersionId != nil {
+		versionID = *policyOutput.Policy.DefaultVersionId
+	}
+
+	// Managed policies (especially AWS-managed ones like AdministratorAccess)
+	// are attached to many principals; intern the parsed document so it's
+	// fetched and stored once per ARN+version
+	cacheKey := policyArn + ":" + versionID
+	if cached, ok := c.managedPolicyCache[cacheKey]; ok {
+		if c.debug {
+			fmt.Printf("DEBUG: Reusing cached managed policy %s\n", cacheKey)
+		}
+		return cached, nil
+	}
+
 	// Get the policy document for the default version
 	versionOutput, err := c.iamClient.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
 		PolicyArn: &policyArn,
@@ -451,7 +865,130 @@ func (c *Collector) getManagedPolicyDocument(ctx context.Context, policyArn stri
 		return nil, err
 	}
 
-	return c.parsePolicy(*versionOutput.PolicyVersion.Document)
+	doc, err := c.parsePolicy(*versionOutput.PolicyVersion.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record where the document came from so attachment queries can match on
+	// the managed policy ARN or name
+	doc.SourcePolicyARN = policyArn
+	if policyOutput.Policy.PolicyName != nil {
+		doc.SourcePolicyName = *policyOutput.Policy.PolicyName
+	}
+
+	// Optionally collect historical versions of customer-managed policies.
+	// AWS-managed policies have no useful version history for an account
+	// audit, so skip them
+	if c.allPolicyVersions && !strings.HasPrefix(policyArn, "arn:aws:iam::aws:policy/") {
+		versions, err := c.getPolicyVersions(ctx, policyArn, versionID)
+		if err != nil {
+			if c.debug {
+				fmt.Fprintf(os.Stderr, "DEBUG: Failed to list versions for policy %s: %v\n", policyArn, err)
+			}
+			// Continue with just the default version rather than failing
+		} else {
+			doc.PolicyVersions = versions
+		}
+	}
+
+	if c.managedPolicyCache == nil {
+		c.managedPolicyCache = make(map[string]*types.PolicyDocument)
+	}
+	c.managedPolicyCache[cacheKey] = doc
+
+	return doc, nil
+}
+
+// collectManagedPolicies inventories the account's customer-managed policies
+// with AWS's attachment counts. The documents themselves are only fetched
+// where a policy is attached (getManagedPolicyDocument); the inventory exists
+// so unattached policies are still visible to the unused-policy report
+func (c *Collector) collectManagedPolicies(ctx context.Context) ([]types.ManagedPolicyInfo, error) {
+	var policies []types.ManagedPolicyInfo
+
+	paginator := iam.NewListPoliciesPaginator(c.iamClient, &iam.ListPoliciesInput{
+		Scope: iamtypes.PolicyScopeTypeLocal,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list managed policies: %w", err)
+		}
+
+		for _, p := range output.Policies {
+			if p.Arn == nil {
+				continue
+			}
+			info := types.ManagedPolicyInfo{ARN: *p.Arn}
+			if p.PolicyName != nil {
+				info.Name = *p.PolicyName
+			}
+			if p.AttachmentCount != nil {
+				info.AttachmentCount = *p.AttachmentCount
+			}
+			policies = append(policies, info)
+		}
+	}
+
+	return policies, nil
+}
+
+// getPolicyVersions fetches every stored version of a customer-managed
+// policy. The default version's document is already held by the caller, so
+// only non-default documents are fetched; version metadata is kept for all
+func (c *Collector) getPolicyVersions(ctx context.Context, policyArn, defaultVersionID string) ([]types.PolicyVersion, error) {
+	var versions []types.PolicyVersion
+
+	paginator := iam.NewListPolicyVersionsPaginator(c.iamClient, &iam.ListPolicyVersionsInput{
+		PolicyArn: &policyArn,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policy versions: %w", err)
+		}
+
+		for _, version := range output.Versions {
+			if version.VersionId == nil {
+				continue
+			}
+
+			pv := types.PolicyVersion{
+				VersionID:  *version.VersionId,
+				IsDefault:  version.IsDefaultVersion,
+				CreateDate: version.CreateDate,
+			}
+
+			if *version.VersionId != defaultVersionID {
+				versionOutput, err := c.iamClient.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
+					PolicyArn: &policyArn,
+					VersionId: version.VersionId,
+				})
+				if err != nil {
+					if c.debug {
+						fmt.Fprintf(os.Stderr, "DEBUG: Failed to get policy version %s of %s: %v\n", *version.VersionId, policyArn, err)
+					}
+					// Keep the metadata even when the document fetch fails
+				} else if versionOutput.PolicyVersion.Document != nil {
+					versionDoc, err := c.parsePolicy(*versionOutput.PolicyVersion.Document)
+					if err != nil {
+						if c.debug {
+							fmt.Fprintf(os.Stderr, "DEBUG: Failed to parse policy version %s of %s: %v\n", *version.VersionId, policyArn, err)
+						}
+					} else {
+						pv.Document = versionDoc
+					}
+				}
+			}
+
+			versions = append(versions, pv)
+		}
+	}
+
+	return versions, nil
 }
 
 func (c *Collector) parsePolicy(policyDoc string) (*types.PolicyDocument, error) {
@@ -488,12 +1025,37 @@ func (c *Collector) collectSCPsWithTargets(ctx context.Context) ([]types.SCPAtta
 	if !c.includeSCPs {
 		return nil, nil // Skip if not enabled
 	}
+	return c.collectOrgPoliciesWithTargets(ctx, organizationstypes.PolicyTypeServiceControlPolicy, "SCP")
+}
 
+// collectRCPsWithTargets fetches Resource Control Policies with target
+// information. Organizations that haven't enabled the RCP policy type (or
+// lack Organizations access) simply get none - that is not an error.
+func (c *Collector) collectRCPsWithTargets(ctx context.Context) ([]types.SCPAttachment, error) {
+	if !c.includeSCPs {
+		return nil, nil // Skip if not enabled
+	}
+
+	attachments, err := c.collectOrgPoliciesWithTargets(ctx, organizationstypes.PolicyTypeResourceControlPolicy, "RCP")
+	if err != nil {
+		// ListPolicies fails with PolicyTypeNotEnabledException when RCPs
+		// aren't enabled for the organization - common, so don't fail
+		if c.debug {
+			fmt.Printf("DEBUG: Failed to collect RCPs (policy type may not be enabled): %v\n", err)
+		}
+		return nil, nil
+	}
+	return attachments, nil
+}
+
+// collectOrgPoliciesWithTargets fetches Organizations policies of the given
+// type along with their attachment targets
+func (c *Collector) collectOrgPoliciesWithTargets(ctx context.Context, filter organizationstypes.PolicyType, label string) ([]types.SCPAttachment, error) {
 	var attachments []types.SCPAttachment
 
-	// List all SCPs in the organization
+	// List all policies of this type in the organization
 	paginator := organizations.NewListPoliciesPaginator(c.organizationsClient, &organizations.ListPoliciesInput{
-		Filter: organizationstypes.PolicyTypeServiceControlPolicy,
+		Filter: filter,
 	})
 
 	for paginator.HasMorePages() {
@@ -502,39 +1064,39 @@ func (c *Collector) collectSCPsWithTargets(ctx context.Context) ([]types.SCPAtta
 			// Handle permission errors gracefully (not all accounts have Org access)
 			if isAccessDeniedError(err) {
 				if c.debug {
-					fmt.Printf("DEBUG: No Organizations access, skipping SCPs: %v\n", err)
+					fmt.Printf("DEBUG: No Organizations access, skipping %ss: %v\n", label, err)
 				}
 				return nil, nil // Return empty, not an error
 			}
-			return nil, fmt.Errorf("failed to list SCPs: %w", err)
+			return nil, fmt.Errorf("failed to list %ss: %w", label, err)
 		}
 
-		// For each SCP, get its policy document and targets
+		// For each policy, get its document and targets
 		for _, policySummary := range output.Policies {
 			policyDetail, err := c.organizationsClient.DescribePolicy(ctx, &organizations.DescribePolicyInput{
 				PolicyId: policySummary.Id,
 			})
 			if err != nil {
-				return nil, fmt.Errorf("failed to describe SCP %s: %w", *policySummary.Name, err)
+				return nil, fmt.Errorf("failed to describe %s %s: %w", label, *policySummary.Name, err)
 			}
 
 			// Parse the policy document
 			policyDoc, err := c.parsePolicy(*policyDetail.Policy.Content)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse SCP %s: %w", *policySummary.Name, err)
+				return nil, fmt.Errorf("failed to parse %s %s: %w", label, *policySummary.Name, err)
 			}
 
-			// Store SCP metadata (ID) in policy
+			// Store policy metadata (ID) in policy
 			policyDoc.ID = *policySummary.Id
 
-			// Get targets where this SCP is attached
+			// Get targets where this policy is attached
 			targets, err := c.getSCPTargets(ctx, *policySummary.Id)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get targets for SCP %s: %w", *policySummary.Name, err)
+				return nil, fmt.Errorf("failed to get targets for %s %s: %w", label, *policySummary.Name, err)
 			}
 
 			if c.debug {
-				fmt.Printf("DEBUG: Collected SCP: %s (ID: %s) with %d targets\n", *policySummary.Name, *policySummary.Id, len(targets))
+				fmt.Printf("DEBUG: Collected %s: %s (ID: %s) with %d targets\n", label, *policySummary.Name, *policySummary.Id, len(targets))
 			}
 
 			attachments = append(attachments, types.SCPAttachment{
@@ -663,6 +1225,31 @@ func (c *Collector) CollectOrganization(ctx context.Context, roleName string) (*
 		FailedAccounts: []string{},
 	}
 
+	// Resume from a previous interrupted collection if requested
+	if c.resume {
+		checkpoint, err := loadOrgCheckpoint()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+		if checkpoint != nil {
+			result = checkpoint
+			if result.Accounts == nil {
+				result.Accounts = make(map[string]*types.CollectionResult)
+			}
+			if result.OUHierarchy == nil {
+				result.OUHierarchy = make(map[string]*types.OUHierarchy)
+			}
+			// Retry accounts that failed in the previous run
+			result.FailureCount = 0
+			result.FailedAccounts = []string{}
+			if c.debug {
+				fmt.Fprintf(os.Stderr, "DEBUG: Resuming from checkpoint with %d account(s) already collected\n", len(result.Accounts))
+			}
+		} else if c.debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: No checkpoint found, starting fresh collection\n")
+		}
+	}
+
 	// Get organization ID
 	orgOutput, err := c.organizationsClient.DescribeOrganization(ctx, &organizations.DescribeOrganizationInput{})
 	if err != nil {
@@ -686,6 +1273,12 @@ func (c *Collector) CollectOrganization(ctx context.Context, roleName string) (*
 		result.SCPAttachments = scps
 	}
 
+	// Collect organization-wide RCPs (resource-side guardrails)
+	rcps, err := c.collectRCPsWithTargets(ctx)
+	if err == nil && len(rcps) > 0 {
+		result.RCPAttachments = rcps
+	}
+
 	// List all accounts in the organization
 	if c.debug {
 		fmt.Fprintf(os.Stderr, "DEBUG: Listing accounts in organization\n")
@@ -721,6 +1314,14 @@ func (c *Collector) CollectOrganization(ctx context.Context, roleName string) (*
 			accountName = *account.Name
 		}
 
+		// Skip accounts already collected in a resumed run
+		if _, done := result.Accounts[accountID]; done {
+			if c.debug {
+				fmt.Fprintf(os.Stderr, "DEBUG: Skipping account %s (already collected in checkpoint)\n", accountID)
+			}
+			continue
+		}
+
 		if c.debug {
 			fmt.Fprintf(os.Stderr, "DEBUG: Collecting from account %s (%s)\n", accountID, accountName)
 		}
@@ -743,7 +1344,7 @@ func (c *Collector) CollectOrganization(ctx context.Context, roleName string) (*
 		}
 
 		// Create credentials provider for the assumed role
-		creds := stscreds.NewAssumeRoleProvider(c.stsClient, roleARN)
+		creds := stscreds.NewAssumeRoleProvider(c.stsClient, roleARN, c.assumeRoleOptions()...)
 		accountCfg := c.baseCfg.Copy()
 		accountCfg.Credentials = aws.NewCredentialsCache(creds)
 
@@ -761,7 +1362,10 @@ func (c *Collector) CollectOrganization(ctx context.Context, roleName string) (*
 			profile:              c.profile,
 			debug:                c.debug,
 			includeSCPs:          false, // Don't collect SCPs per-account (already collected org-wide)
+			legacyCollection:     c.legacyCollection,
+			extraRegions:         c.extraRegions,
 			baseCfg:              accountCfg,
+			managedPolicyCache:   make(map[string]*types.PolicyDocument),
 		}
 
 		// Collect data from this account
@@ -779,12 +1383,22 @@ func (c *Collector) CollectOrganization(ctx context.Context, roleName string) (*
 		result.Accounts[accountID] = accountResult
 		result.SuccessCount++
 
+		// Persist progress so an interrupted run can resume from here
+		if err := saveOrgCheckpoint(result); err != nil && c.debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: Failed to save checkpoint: %v\n", err)
+		}
+
 		if c.debug {
 			fmt.Fprintf(os.Stderr, "DEBUG: Successfully collected from account %s: %d principals, %d resources\n",
 				accountID, len(accountResult.Principals), len(accountResult.Resources))
 		}
 	}
 
+	// Collection completed; the checkpoint is no longer needed
+	if err := clearOrgCheckpoint(); err != nil && c.debug {
+		fmt.Fprintf(os.Stderr, "DEBUG: Failed to clear checkpoint: %v\n", err)
+	}
+
 	if c.debug {
 		fmt.Fprintf(os.Stderr, "DEBUG: Organization collection complete: %d succeeded, %d failed\n",
 			result.SuccessCount, result.FailureCount)
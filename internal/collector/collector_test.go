@@ -81,3 +81,18 @@ func TestExtractAccountIDFromARN_EdgeCases(t *testing.T) {
 		t.Errorf("extractAccountIDFromARN(%q) = %q, want %q", arn, got, want)
 	}
 }
+
+func TestWithRegions(t *testing.T) {
+	c := &Collector{region: "us-east-1"}
+	c = c.WithRegions([]string{" eu-west-1 ", "us-east-1", "eu-west-1", "", "ap-southeast-2"})
+
+	want := []string{"eu-west-1", "ap-southeast-2"}
+	if len(c.extraRegions) != len(want) {
+		t.Fatalf("extraRegions = %v, want %v", c.extraRegions, want)
+	}
+	for i, region := range want {
+		if c.extraRegions[i] != region {
+			t.Errorf("extraRegions[%d] = %s, want %s", i, c.extraRegions[i], region)
+		}
+	}
+}
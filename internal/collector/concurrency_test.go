@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// fakeStep builds a collection step returning one resource of the given type
+func fakeStep(name string, resourceType types.ResourceType) resourceCollectorStep {
+	return resourceCollectorStep{
+		name: name,
+		collect: func(ctx context.Context) ([]*types.Resource, error) {
+			return []*types.Resource{
+				{
+					ARN:  fmt.Sprintf("arn:aws:%s:::example", name),
+					Type: resourceType,
+					Name: "example",
+				},
+			}, nil
+		},
+	}
+}
+
+func TestRunResourceSteps_AllTypesAppear(t *testing.T) {
+	steps := []resourceCollectorStep{
+		fakeStep("s3", types.ResourceTypeS3),
+		fakeStep("kms", types.ResourceTypeKMS),
+		fakeStep("sqs", types.ResourceTypeSQS),
+		fakeStep("sns", types.ResourceTypeSNS),
+		fakeStep("lambda", types.ResourceTypeLambda),
+	}
+
+	resources, err := runResourceSteps(context.Background(), 3, steps)
+	if err != nil {
+		t.Fatalf("runResourceSteps() failed: %v", err)
+	}
+
+	if len(resources) != len(steps) {
+		t.Fatalf("Expected %d resources, got %d", len(steps), len(resources))
+	}
+
+	// Merge order must follow declared step order despite concurrency
+	want := []types.ResourceType{
+		types.ResourceTypeS3,
+		types.ResourceTypeKMS,
+		types.ResourceTypeSQS,
+		types.ResourceTypeSNS,
+		types.ResourceTypeLambda,
+	}
+	for i, resourceType := range want {
+		if resources[i].Type != resourceType {
+			t.Errorf("Resource %d: expected type %s, got %s", i, resourceType, resources[i].Type)
+		}
+	}
+}
+
+func TestRunResourceSteps_FirstErrorAborts(t *testing.T) {
+	broken := errors.New("service unavailable")
+	steps := []resourceCollectorStep{
+		fakeStep("s3", types.ResourceTypeS3),
+		{
+			name: "kms",
+			collect: func(ctx context.Context) ([]*types.Resource, error) {
+				return nil, broken
+			},
+		},
+	}
+
+	_, err := runResourceSteps(context.Background(), 2, steps)
+	if err == nil {
+		t.Fatal("Expected an error from the failing step")
+	}
+	if !errors.Is(err, broken) {
+		t.Errorf("Expected wrapped step error, got: %v", err)
+	}
+}
+
+func TestRunResourceSteps_BudgetErrorKeepsPartialResults(t *testing.T) {
+	steps := []resourceCollectorStep{
+		fakeStep("s3", types.ResourceTypeS3),
+		{
+			name: "kms",
+			collect: func(ctx context.Context) ([]*types.Resource, error) {
+				return nil, fmt.Errorf("%w (limit: 10)", ErrAPIBudgetExceeded)
+			},
+		},
+	}
+
+	// Serial execution so the S3 step completes before the budget runs out
+	resources, err := runResourceSteps(context.Background(), 1, steps)
+	if !errors.Is(err, ErrAPIBudgetExceeded) {
+		t.Fatalf("Expected budget error to surface, got: %v", err)
+	}
+	if len(resources) != 1 || resources[0].Type != types.ResourceTypeS3 {
+		t.Errorf("Expected the completed S3 result to survive, got %v", resources)
+	}
+}
@@ -0,0 +1,158 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/athena"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/glacier"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// PermissionProbe records one dry probe of an API call the collector depends
+// on, and which collection stage breaks without it
+type PermissionProbe struct {
+	Permission string `json:"permission"` // IAM action probed, e.g. iam:ListUsers
+	Collector  string `json:"collector"`  // Collection stage that depends on it
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"` // Error returned when the probe failed
+}
+
+// Diagnostics summarizes a pre-flight credential and permission check: who
+// the current credentials resolve to and which probes passed
+type Diagnostics struct {
+	AccountID string            `json:"account_id"`
+	CallerARN string            `json:"caller_arn"`
+	Probes    []PermissionProbe `json:"probes"`
+}
+
+// FailingCollectors lists the collection stages whose probes failed, in
+// probe order
+func (d *Diagnostics) FailingCollectors() []string {
+	var failing []string
+	for _, probe := range d.Probes {
+		if !probe.OK {
+			failing = append(failing, probe.Collector)
+		}
+	}
+	return failing
+}
+
+// Diagnose resolves the caller identity via STS and dry-probes the key IAM
+// and per-service list calls collection depends on, requesting at most one
+// item from each so the probes stay cheap. A failed IAM probe means a full
+// run will fail outright; a failed resource probe means that service's
+// collector will be silently skipped
+func (c *Collector) Diagnose(ctx context.Context) (*Diagnostics, error) {
+	identity, err := c.stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+
+	diag := &Diagnostics{}
+	if identity.Account != nil {
+		diag.AccountID = *identity.Account
+	}
+	if identity.Arn != nil {
+		diag.CallerARN = *identity.Arn
+	}
+
+	one := aws.Int32(1)
+	probes := []struct {
+		permission string
+		collector  string
+		call       func() error
+	}{
+		{"iam:ListUsers", "IAM users", func() error {
+			_, err := c.iamClient.ListUsers(ctx, &iam.ListUsersInput{MaxItems: one})
+			return err
+		}},
+		{"iam:ListRoles", "IAM roles", func() error {
+			_, err := c.iamClient.ListRoles(ctx, &iam.ListRolesInput{MaxItems: one})
+			return err
+		}},
+		{"iam:GetAccountAuthorizationDetails", "IAM policies", func() error {
+			_, err := c.iamClient.GetAccountAuthorizationDetails(ctx, &iam.GetAccountAuthorizationDetailsInput{MaxItems: one})
+			return err
+		}},
+		{"s3:ListAllMyBuckets", "S3", func() error {
+			_, err := c.s3Client.ListBuckets(ctx, &s3.ListBucketsInput{})
+			return err
+		}},
+		{"kms:ListKeys", "KMS", func() error {
+			_, err := c.kmsClient.ListKeys(ctx, &kms.ListKeysInput{Limit: one})
+			return err
+		}},
+		{"sqs:ListQueues", "SQS", func() error {
+			_, err := c.sqsClient.ListQueues(ctx, &sqs.ListQueuesInput{MaxResults: one})
+			return err
+		}},
+		{"sns:ListTopics", "SNS", func() error {
+			_, err := c.snsClient.ListTopics(ctx, &sns.ListTopicsInput{})
+			return err
+		}},
+		{"secretsmanager:ListSecrets", "Secrets Manager", func() error {
+			_, err := c.secretsManagerClient.ListSecrets(ctx, &secretsmanager.ListSecretsInput{MaxResults: one})
+			return err
+		}},
+		{"lambda:ListFunctions", "Lambda", func() error {
+			_, err := lambda.NewFromConfig(c.baseCfg).ListFunctions(ctx, &lambda.ListFunctionsInput{MaxItems: one})
+			return err
+		}},
+		{"apigateway:GET", "API Gateway", func() error {
+			_, err := apigateway.NewFromConfig(c.baseCfg).GetRestApis(ctx, &apigateway.GetRestApisInput{Limit: one})
+			return err
+		}},
+		{"ecr:DescribeRepositories", "ECR", func() error {
+			_, err := ecr.NewFromConfig(c.baseCfg).DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{MaxResults: one})
+			return err
+		}},
+		{"events:ListEventBuses", "EventBridge", func() error {
+			_, err := eventbridge.NewFromConfig(c.baseCfg).ListEventBuses(ctx, &eventbridge.ListEventBusesInput{Limit: one})
+			return err
+		}},
+		{"athena:ListWorkGroups", "Athena", func() error {
+			_, err := athena.NewFromConfig(c.baseCfg).ListWorkGroups(ctx, &athena.ListWorkGroupsInput{MaxResults: one})
+			return err
+		}},
+		{"elasticfilesystem:DescribeFileSystems", "EFS", func() error {
+			_, err := efs.NewFromConfig(c.baseCfg).DescribeFileSystems(ctx, &efs.DescribeFileSystemsInput{MaxItems: one})
+			return err
+		}},
+		{"glacier:ListVaults", "Glacier", func() error {
+			_, err := glacier.NewFromConfig(c.baseCfg).ListVaults(ctx, &glacier.ListVaultsInput{
+				AccountId: aws.String("-"),
+				Limit:     one,
+			})
+			return err
+		}},
+		{"ecs:ListTaskDefinitions", "ECS", func() error {
+			_, err := ecs.NewFromConfig(c.baseCfg).ListTaskDefinitions(ctx, &ecs.ListTaskDefinitionsInput{MaxResults: one})
+			return err
+		}},
+	}
+
+	for _, probe := range probes {
+		result := PermissionProbe{Permission: probe.permission, Collector: probe.collector, OK: true}
+		if err := probe.call(); err != nil {
+			result.OK = false
+			result.Error = err.Error()
+		}
+		diag.Probes = append(diag.Probes, result)
+	}
+
+	return diag, nil
+}
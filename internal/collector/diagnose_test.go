@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"testing"
+)
+
+func TestFailingCollectors(t *testing.T) {
+	diag := &Diagnostics{
+		Probes: []PermissionProbe{
+			{Permission: "iam:ListUsers", Collector: "IAM users", OK: true},
+			{Permission: "s3:ListAllMyBuckets", Collector: "S3", OK: false, Error: "AccessDenied"},
+			{Permission: "kms:ListKeys", Collector: "KMS", OK: true},
+			{Permission: "glacier:ListVaults", Collector: "Glacier", OK: false, Error: "AccessDenied"},
+		},
+	}
+
+	failing := diag.FailingCollectors()
+	want := []string{"S3", "Glacier"}
+	if len(failing) != len(want) {
+		t.Fatalf("FailingCollectors() = %v, want %v", failing, want)
+	}
+	for i, name := range want {
+		if failing[i] != name {
+			t.Errorf("FailingCollectors()[%d] = %s, want %s", i, failing[i], name)
+		}
+	}
+
+	// All probes passing yields no failing collectors
+	diag = &Diagnostics{Probes: []PermissionProbe{{Collector: "IAM users", OK: true}}}
+	if failing := diag.FailingCollectors(); len(failing) != 0 {
+		t.Errorf("FailingCollectors() = %v, want empty", failing)
+	}
+}
@@ -91,5 +91,48 @@ func (c *Collector) collectECRResources(ctx context.Context) ([]*types.Resource,
 		fmt.Printf("DEBUG: Found %d ECR repositories\n", repoCount)
 	}
 
+	// Collect the registry-level policy (cross-account replication and
+	// pull-through cache permissions apply registry-wide, not per-repo)
+	if registry := c.collectECRRegistryPolicy(ctx, ecrClient); registry != nil {
+		resources = append(resources, registry)
+	}
+
 	return resources, nil
 }
+
+// collectECRRegistryPolicy fetches the ECR registry policy as a distinct
+// resource. Most registries have no policy - that returns nil, not an error.
+func (c *Collector) collectECRRegistryPolicy(ctx context.Context, ecrClient *ecr.Client) *types.Resource {
+	policyOutput, err := ecrClient.GetRegistryPolicy(ctx, &ecr.GetRegistryPolicyInput{})
+	if err != nil {
+		// RegistryPolicyNotFoundException is the common case
+		if !strings.Contains(err.Error(), "RegistryPolicyNotFoundException") && c.debug {
+			fmt.Printf("DEBUG: Failed to get ECR registry policy: %v\n", err)
+		}
+		return nil
+	}
+
+	if policyOutput.RegistryId == nil || policyOutput.PolicyText == nil {
+		return nil
+	}
+
+	registryID := *policyOutput.RegistryId
+	resource := &types.Resource{
+		ARN:       fmt.Sprintf("arn:aws:ecr:%s:%s:registry", c.region, registryID),
+		Type:      types.ResourceTypeECR,
+		Name:      "registry",
+		Region:    c.region,
+		AccountID: registryID,
+	}
+
+	policyDoc, err := c.parsePolicy(*policyOutput.PolicyText)
+	if err != nil {
+		if c.debug {
+			fmt.Printf("DEBUG: Failed to parse ECR registry policy: %v\n", err)
+		}
+		return resource
+	}
+	resource.ResourcePolicy = policyDoc
+
+	return resource
+}
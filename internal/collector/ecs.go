@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// collectECSResources collects ECS task definitions with their task and
+// execution role ARNs. Task definitions have no resource policy; the role
+// linkage is what matters - it lets path queries answer "can this ECS
+// workload reach this secret" by stepping through the task role
+func (c *Collector) collectECSResources(ctx context.Context) ([]*types.Resource, error) {
+	var resources []*types.Resource
+
+	// Create ECS client
+	ecsClient := ecs.NewFromConfig(c.baseCfg)
+
+	// List all active task definitions with pagination
+	paginator := ecs.NewListTaskDefinitionsPaginator(ecsClient, &ecs.ListTaskDefinitionsInput{})
+
+	taskDefCount := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			// If we don't have ECS permissions, that's OK - just return empty
+			if c.debug {
+				fmt.Printf("DEBUG: Failed to list ECS task definitions (may lack permissions): %v\n", err)
+			}
+			return resources, nil
+		}
+
+		taskDefCount += len(page.TaskDefinitionArns)
+
+		for _, taskDefARN := range page.TaskDefinitionArns {
+			// Name is the family:revision segment of the ARN
+			name := taskDefARN
+			if idx := strings.LastIndex(taskDefARN, "/"); idx != -1 {
+				name = taskDefARN[idx+1:]
+			}
+
+			resource := &types.Resource{
+				ARN:       taskDefARN,
+				Type:      types.ResourceTypeECS,
+				Name:      name,
+				Region:    c.region,
+				AccountID: extractAccountIDFromARN(taskDefARN),
+			}
+
+			// Describe to get the task and execution roles
+			describeOutput, err := ecsClient.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+				TaskDefinition: &taskDefARN,
+			})
+			if err != nil {
+				// Keep the bare resource rather than failing collection
+				if c.debug {
+					fmt.Printf("DEBUG: Failed to describe ECS task definition %s: %v\n", name, err)
+				}
+				resources = append(resources, resource)
+				continue
+			}
+
+			if td := describeOutput.TaskDefinition; td != nil {
+				if td.TaskRoleArn != nil {
+					resource.TaskRoleARN = *td.TaskRoleArn
+				}
+				if td.ExecutionRoleArn != nil {
+					resource.ExecutionRoleARN = *td.ExecutionRoleArn
+				}
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	if c.debug {
+		fmt.Printf("DEBUG: Found %d ECS task definitions\n", taskDefCount)
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// collectEFSResources collects EFS file systems and their resource policies
+func (c *Collector) collectEFSResources(ctx context.Context) ([]*types.Resource, error) {
+	var resources []*types.Resource
+
+	// Create EFS client
+	efsClient := efs.NewFromConfig(c.baseCfg)
+
+	// List all EFS file systems with pagination
+	paginator := efs.NewDescribeFileSystemsPaginator(efsClient, &efs.DescribeFileSystemsInput{})
+
+	fsCount := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			// If we don't have EFS permissions, that's OK - just return empty
+			if c.debug {
+				fmt.Printf("DEBUG: Failed to list EFS file systems (may lack permissions): %v\n", err)
+			}
+			return resources, nil
+		}
+
+		fsCount += len(page.FileSystems)
+
+		for _, fs := range page.FileSystems {
+			if fs.FileSystemArn == nil || fs.FileSystemId == nil {
+				continue
+			}
+
+			name := *fs.FileSystemId
+			if fs.Name != nil && *fs.Name != "" {
+				name = *fs.Name
+			}
+
+			resource := &types.Resource{
+				ARN:       *fs.FileSystemArn,
+				Type:      types.ResourceTypeEFS,
+				Name:      name,
+				Region:    c.region,
+				AccountID: extractAccountIDFromARN(*fs.FileSystemArn),
+			}
+
+			// Try to get the file system policy
+			policyOutput, err := efsClient.DescribeFileSystemPolicy(ctx, &efs.DescribeFileSystemPolicyInput{
+				FileSystemId: fs.FileSystemId,
+			})
+
+			// It's OK if the file system doesn't have a policy
+			if err != nil {
+				// PolicyNotFound is expected for file systems using the default policy
+				errStr := err.Error()
+				if strings.Contains(errStr, "PolicyNotFound") {
+					// No policy is fine, continue
+					resources = append(resources, resource)
+					continue
+				}
+
+				// For other errors, log but continue (may be access denied)
+				if c.debug {
+					fmt.Printf("DEBUG: Failed to get policy for EFS file system %s: %v\n", *fs.FileSystemId, err)
+				}
+				resources = append(resources, resource)
+				continue
+			}
+
+			// Parse the policy if it exists
+			if policyOutput.Policy != nil {
+				policyDoc, err := c.parsePolicy(*policyOutput.Policy)
+				if err != nil {
+					if c.debug {
+						fmt.Printf("DEBUG: Failed to parse policy for EFS file system %s: %v\n", *fs.FileSystemId, err)
+					}
+					// Add resource without policy rather than failing completely
+					resources = append(resources, resource)
+					continue
+				}
+				resource.ResourcePolicy = policyDoc
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	if c.debug {
+		fmt.Printf("DEBUG: Found %d EFS file systems\n", fsCount)
+	}
+
+	return resources, nil
+}
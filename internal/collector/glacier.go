@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/glacier"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// collectGlacierResources collects S3 Glacier vaults and their access policies
+func (c *Collector) collectGlacierResources(ctx context.Context) ([]*types.Resource, error) {
+	var resources []*types.Resource
+
+	// Create Glacier client
+	glacierClient := glacier.NewFromConfig(c.baseCfg)
+
+	// List all Glacier vaults with pagination ("-" means the current account)
+	paginator := glacier.NewListVaultsPaginator(glacierClient, &glacier.ListVaultsInput{
+		AccountId: aws.String("-"),
+	})
+
+	vaultCount := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			// If we don't have Glacier permissions, that's OK - just return empty
+			if c.debug {
+				fmt.Printf("DEBUG: Failed to list Glacier vaults (may lack permissions): %v\n", err)
+			}
+			return resources, nil
+		}
+
+		vaultCount += len(page.VaultList)
+
+		for _, vault := range page.VaultList {
+			if vault.VaultARN == nil || vault.VaultName == nil {
+				continue
+			}
+
+			resource := &types.Resource{
+				ARN:       *vault.VaultARN,
+				Type:      types.ResourceTypeGlacier,
+				Name:      *vault.VaultName,
+				Region:    c.region,
+				AccountID: extractAccountIDFromARN(*vault.VaultARN),
+			}
+
+			// Try to get the vault access policy
+			policyOutput, err := glacierClient.GetVaultAccessPolicy(ctx, &glacier.GetVaultAccessPolicyInput{
+				AccountId: aws.String("-"),
+				VaultName: vault.VaultName,
+			})
+
+			// It's OK if the vault doesn't have a policy
+			if err != nil {
+				// ResourceNotFoundException is expected for vaults without policies
+				errStr := err.Error()
+				if strings.Contains(errStr, "ResourceNotFoundException") {
+					// No policy is fine, continue
+					resources = append(resources, resource)
+					continue
+				}
+
+				// For other errors, log but continue (may be access denied)
+				if c.debug {
+					fmt.Printf("DEBUG: Failed to get policy for Glacier vault %s: %v\n", *vault.VaultName, err)
+				}
+				resources = append(resources, resource)
+				continue
+			}
+
+			// Parse the policy if it exists
+			if policyOutput.Policy != nil && policyOutput.Policy.Policy != nil {
+				policyDoc, err := c.parsePolicy(*policyOutput.Policy.Policy)
+				if err != nil {
+					if c.debug {
+						fmt.Printf("DEBUG: Failed to parse policy for Glacier vault %s: %v\n", *vault.VaultName, err)
+					}
+					// Add resource without policy rather than failing completely
+					resources = append(resources, resource)
+					continue
+				}
+				resource.ResourcePolicy = policyDoc
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	if c.debug {
+		fmt.Printf("DEBUG: Found %d Glacier vaults\n", vaultCount)
+	}
+
+	return resources, nil
+}
@@ -79,6 +79,8 @@ func (c *Collector) collectGroups(ctx context.Context) ([]*types.Principal, map[
 
 			groups = append(groups, principal)
 		}
+
+		c.reportProgress("IAM groups", len(groups), 0)
 	}
 
 	if c.debug {
@@ -0,0 +1,201 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/identitystore"
+	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
+	ssoadmintypes "github.com/aws/aws-sdk-go-v2/service/ssoadmin/types"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// SSOReservedRolePrefix is the name prefix Identity Center uses for roles it
+// provisions from permission sets
+const SSOReservedRolePrefix = "AWSReservedSSO_"
+
+// IsSSOReservedRole reports whether a role name belongs to an Identity Center
+// permission set
+func IsSSOReservedRole(roleName string) bool {
+	return strings.HasPrefix(roleName, SSOReservedRolePrefix)
+}
+
+// collectSSOAssignments collects Identity Center account assignments so SSO
+// users and groups can be mapped to the AWSReservedSSO_* roles they assume
+func (c *Collector) collectSSOAssignments(ctx context.Context, accountID string) ([]types.SSOAssignment, []*types.Principal, error) {
+	ssoClient := ssoadmin.NewFromConfig(c.baseCfg)
+
+	// List Identity Center instances - accounts not using Identity Center
+	// (or lacking permissions) simply produce no assignments
+	instancesOutput, err := ssoClient.ListInstances(ctx, &ssoadmin.ListInstancesInput{})
+	if err != nil {
+		if c.debug {
+			fmt.Printf("DEBUG: Failed to list Identity Center instances (may not be enabled): %v\n", err)
+		}
+		return nil, nil, nil
+	}
+	if len(instancesOutput.Instances) == 0 {
+		if c.debug {
+			fmt.Printf("DEBUG: No Identity Center instances found\n")
+		}
+		return nil, nil, nil
+	}
+
+	idClient := identitystore.NewFromConfig(c.baseCfg)
+
+	var assignments []types.SSOAssignment
+	principalsByARN := make(map[string]*types.Principal)
+
+	for _, instance := range instancesOutput.Instances {
+		if instance.InstanceArn == nil || instance.IdentityStoreId == nil {
+			continue
+		}
+		instanceArn := *instance.InstanceArn
+		storeID := *instance.IdentityStoreId
+
+		// List permission sets provisioned to this account
+		psPaginator := ssoadmin.NewListPermissionSetsProvisionedToAccountPaginator(ssoClient, &ssoadmin.ListPermissionSetsProvisionedToAccountInput{
+			AccountId:   aws.String(accountID),
+			InstanceArn: aws.String(instanceArn),
+		})
+
+		for psPaginator.HasMorePages() {
+			psPage, err := psPaginator.NextPage(ctx)
+			if err != nil {
+				if c.debug {
+					fmt.Printf("DEBUG: Failed to list permission sets for account %s: %v\n", accountID, err)
+				}
+				break
+			}
+
+			for _, psArn := range psPage.PermissionSets {
+				psName := c.getPermissionSetName(ctx, ssoClient, instanceArn, psArn)
+
+				// List assignments (users/groups) for this permission set
+				assignPaginator := ssoadmin.NewListAccountAssignmentsPaginator(ssoClient, &ssoadmin.ListAccountAssignmentsInput{
+					AccountId:        aws.String(accountID),
+					InstanceArn:      aws.String(instanceArn),
+					PermissionSetArn: aws.String(psArn),
+				})
+
+				for assignPaginator.HasMorePages() {
+					assignPage, err := assignPaginator.NextPage(ctx)
+					if err != nil {
+						if c.debug {
+							fmt.Printf("DEBUG: Failed to list assignments for permission set %s: %v\n", psName, err)
+						}
+						break
+					}
+
+					for _, assignment := range assignPage.AccountAssignments {
+						if assignment.PrincipalId == nil {
+							continue
+						}
+						principalID := *assignment.PrincipalId
+
+						var principalType types.PrincipalType
+						var principalARN string
+						var principalName string
+
+						switch assignment.PrincipalType {
+						case ssoadmintypes.PrincipalTypeUser:
+							principalType = types.PrincipalTypeSSOUser
+							principalARN = fmt.Sprintf("arn:aws:identitystore::%s:user/%s", storeID, principalID)
+							principalName = c.getIdentityStoreUserName(ctx, idClient, storeID, principalID)
+						case ssoadmintypes.PrincipalTypeGroup:
+							principalType = types.PrincipalTypeSSOGroup
+							principalARN = fmt.Sprintf("arn:aws:identitystore::%s:group/%s", storeID, principalID)
+							principalName = c.getIdentityStoreGroupName(ctx, idClient, storeID, principalID)
+						default:
+							continue
+						}
+
+						if principalName == "" {
+							principalName = principalID
+						}
+
+						assignments = append(assignments, types.SSOAssignment{
+							PermissionSetARN:  psArn,
+							PermissionSetName: psName,
+							AccountID:         accountID,
+							PrincipalARN:      principalARN,
+							PrincipalType:     principalType,
+							PrincipalName:     principalName,
+						})
+
+						// Synthesize a principal so SSO identities appear in
+						// query results alongside IAM principals
+						if _, exists := principalsByARN[principalARN]; !exists {
+							principalsByARN[principalARN] = &types.Principal{
+								ARN:       principalARN,
+								Type:      principalType,
+								Name:      principalName,
+								AccountID: accountID,
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var principals []*types.Principal
+	for _, p := range principalsByARN {
+		principals = append(principals, p)
+	}
+
+	if c.debug {
+		fmt.Printf("DEBUG: Found %d Identity Center assignments (%d SSO principals)\n", len(assignments), len(principals))
+	}
+
+	return assignments, principals, nil
+}
+
+// getPermissionSetName resolves a permission set ARN to its name, falling back
+// to the ARN suffix if the describe call fails
+func (c *Collector) getPermissionSetName(ctx context.Context, ssoClient *ssoadmin.Client, instanceArn, psArn string) string {
+	output, err := ssoClient.DescribePermissionSet(ctx, &ssoadmin.DescribePermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(psArn),
+	})
+	if err != nil || output.PermissionSet == nil || output.PermissionSet.Name == nil {
+		if c.debug && err != nil {
+			fmt.Printf("DEBUG: Failed to describe permission set %s: %v\n", psArn, err)
+		}
+		parts := strings.Split(psArn, "/")
+		return parts[len(parts)-1]
+	}
+	return *output.PermissionSet.Name
+}
+
+// getIdentityStoreUserName resolves an identity store user ID to a display name
+func (c *Collector) getIdentityStoreUserName(ctx context.Context, idClient *identitystore.Client, storeID, userID string) string {
+	output, err := idClient.DescribeUser(ctx, &identitystore.DescribeUserInput{
+		IdentityStoreId: aws.String(storeID),
+		UserId:          aws.String(userID),
+	})
+	if err != nil || output.UserName == nil {
+		if c.debug && err != nil {
+			fmt.Printf("DEBUG: Failed to describe identity store user %s: %v\n", userID, err)
+		}
+		return ""
+	}
+	return *output.UserName
+}
+
+// getIdentityStoreGroupName resolves an identity store group ID to a display name
+func (c *Collector) getIdentityStoreGroupName(ctx context.Context, idClient *identitystore.Client, storeID, groupID string) string {
+	output, err := idClient.DescribeGroup(ctx, &identitystore.DescribeGroupInput{
+		IdentityStoreId: aws.String(storeID),
+		GroupId:         aws.String(groupID),
+	})
+	if err != nil || output.DisplayName == nil {
+		if c.debug && err != nil {
+			fmt.Printf("DEBUG: Failed to describe identity store group %s: %v\n", groupID, err)
+		}
+		return ""
+	}
+	return *output.DisplayName
+}
@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/lakeformation"
+	lftypes "github.com/aws/aws-sdk-go-v2/service/lakeformation/types"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// collectLakeFormationGrants collects Lake Formation permissions so data-lake
+// access granted outside IAM/S3 policies shows up in the graph. Accounts that
+// don't use Lake Formation are expected - errors return an empty result.
+func (c *Collector) collectLakeFormationGrants(ctx context.Context, accountID string) ([]types.LakeFormationGrant, error) {
+	var grants []types.LakeFormationGrant
+
+	lfClient := lakeformation.NewFromConfig(c.baseCfg)
+
+	paginator := lakeformation.NewListPermissionsPaginator(lfClient, &lakeformation.ListPermissionsInput{})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			// Accounts without Lake Formation (or without permissions) are
+			// common - treat as no grants rather than failing collection
+			if c.debug {
+				fmt.Printf("DEBUG: Failed to list Lake Formation permissions (account may not use Lake Formation): %v\n", err)
+			}
+			return grants, nil
+		}
+
+		for _, perm := range page.PrincipalResourcePermissions {
+			if perm.Principal == nil || perm.Principal.DataLakePrincipalIdentifier == nil {
+				continue
+			}
+
+			resourceARN := lfResourceARN(perm.Resource, c.region, accountID)
+			if resourceARN == "" {
+				// Unsupported resource type (LF tags, data locations, etc.)
+				continue
+			}
+
+			permissions := make([]string, 0, len(perm.Permissions))
+			for _, p := range perm.Permissions {
+				permissions = append(permissions, string(p))
+			}
+
+			grants = append(grants, types.LakeFormationGrant{
+				PrincipalARN: *perm.Principal.DataLakePrincipalIdentifier,
+				ResourceARN:  resourceARN,
+				Permissions:  permissions,
+			})
+		}
+	}
+
+	if c.debug {
+		fmt.Printf("DEBUG: Found %d Lake Formation grants\n", len(grants))
+	}
+
+	return grants, nil
+}
+
+// lfResourceARN builds a Glue ARN for the data resource a Lake Formation
+// grant targets. Returns "" for resource types the graph doesn't model.
+func lfResourceARN(resource *lftypes.Resource, region, accountID string) string {
+	if resource == nil {
+		return ""
+	}
+
+	switch {
+	case resource.Table != nil:
+		table := resource.Table
+		if table.DatabaseName == nil {
+			return ""
+		}
+		tableName := "*"
+		if table.Name != nil {
+			tableName = *table.Name
+		}
+		return fmt.Sprintf("arn:aws:glue:%s:%s:table/%s/%s", region, accountID, *table.DatabaseName, tableName)
+
+	case resource.Database != nil:
+		if resource.Database.Name == nil {
+			return ""
+		}
+		return fmt.Sprintf("arn:aws:glue:%s:%s:database/%s", region, accountID, *resource.Database.Name)
+
+	case resource.Catalog != nil:
+		return fmt.Sprintf("arn:aws:glue:%s:%s:catalog", region, accountID)
+
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// RedactPolicies strips policy statements from a collection result in place,
+// replacing each document's contents with a SHA-256 digest of its JSON. The
+// graph-relevant metadata — ARNs, principal and resource types, group
+// memberships, source policy references — survives, so the artifact can be
+// shared for structural analysis or bug reports without exposing the exact
+// statements. Identical policies hash to the same digest, keeping them
+// correlatable across principals.
+func RedactPolicies(result *types.CollectionResult) {
+	if result == nil {
+		return
+	}
+
+	for _, principal := range result.Principals {
+		for i := range principal.Policies {
+			redactDocument(&principal.Policies[i])
+		}
+		redactDocument(principal.TrustPolicy)
+		redactDocument(principal.PermissionsBoundary)
+	}
+
+	for _, resource := range result.Resources {
+		redactDocument(resource.ResourcePolicy)
+	}
+
+	for i := range result.SCPs {
+		redactDocument(&result.SCPs[i])
+	}
+	for i := range result.SCPAttachments {
+		redactDocument(&result.SCPAttachments[i].Policy)
+	}
+}
+
+// RedactMultiAccountPolicies applies RedactPolicies to every account in an
+// org-wide collection result, plus the org-level SCP set.
+func RedactMultiAccountPolicies(result *types.MultiAccountCollectionResult) {
+	if result == nil {
+		return
+	}
+
+	for _, account := range result.Accounts {
+		RedactPolicies(account)
+	}
+	for i := range result.SCPAttachments {
+		redactDocument(&result.SCPAttachments[i].Policy)
+	}
+}
+
+// redactDocument replaces a policy document's statements (and any collected
+// historical versions) with a digest of the original JSON. Nil documents are
+// left alone so callers can pass optional fields directly.
+func redactDocument(doc *types.PolicyDocument) {
+	if doc == nil || len(doc.Statements) == 0 && len(doc.PolicyVersions) == 0 {
+		return
+	}
+
+	// Digest before stripping; marshal errors cannot occur for these types
+	data, err := json.Marshal(doc)
+	if err == nil {
+		sum := sha256.Sum256(data)
+		doc.RedactedSHA256 = hex.EncodeToString(sum[:])
+	}
+
+	doc.Statements = nil
+	for i := range doc.PolicyVersions {
+		redactDocument(doc.PolicyVersions[i].Document)
+	}
+}
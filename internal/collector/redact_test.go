@@ -0,0 +1,147 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestRedactPolicies(t *testing.T) {
+	sharedDoc := types.PolicyDocument{
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "arn:aws:s3:::data/*"},
+		},
+		SourcePolicyARN:  "arn:aws:iam::123456789012:policy/readers",
+		SourcePolicyName: "readers",
+	}
+
+	result := &types.CollectionResult{
+		AccountID: "123456789012",
+		Principals: []*types.Principal{
+			{
+				ARN:      "arn:aws:iam::123456789012:user/alice",
+				Type:     types.PrincipalTypeUser,
+				Name:     "alice",
+				Policies: []types.PolicyDocument{sharedDoc},
+			},
+			{
+				ARN:      "arn:aws:iam::123456789012:role/app",
+				Type:     types.PrincipalTypeRole,
+				Name:     "app",
+				Policies: []types.PolicyDocument{sharedDoc},
+				TrustPolicy: &types.PolicyDocument{
+					Version: "2012-10-17",
+					Statements: []types.Statement{
+						{Effect: types.EffectAllow, Action: "sts:AssumeRole"},
+					},
+				},
+			},
+		},
+		Resources: []*types.Resource{
+			{
+				ARN:  "arn:aws:s3:::data",
+				Type: types.ResourceTypeS3,
+				Name: "data",
+				ResourcePolicy: &types.PolicyDocument{
+					Version: "2012-10-17",
+					Statements: []types.Statement{
+						{Effect: types.EffectAllow, Principal: "*", Action: "s3:GetObject"},
+					},
+				},
+			},
+		},
+		SCPs: []types.PolicyDocument{
+			{
+				ID:      "p-guardrail",
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{Effect: types.EffectDeny, Action: "s3:DeleteBucket", Resource: "*"},
+				},
+			},
+		},
+	}
+
+	RedactPolicies(result)
+
+	alice := result.Principals[0]
+	if len(alice.Policies[0].Statements) != 0 {
+		t.Error("Expected statements to be stripped from user policy")
+	}
+	if alice.Policies[0].RedactedSHA256 == "" {
+		t.Error("Expected redacted policy to carry a digest")
+	}
+	// Metadata relevant to structural analysis survives
+	if alice.Policies[0].SourcePolicyARN != "arn:aws:iam::123456789012:policy/readers" {
+		t.Error("Expected source policy ARN to survive redaction")
+	}
+
+	app := result.Principals[1]
+	if len(app.TrustPolicy.Statements) != 0 {
+		t.Error("Expected trust policy statements to be stripped")
+	}
+
+	// Identical documents hash identically, keeping them correlatable
+	if alice.Policies[0].RedactedSHA256 != app.Policies[0].RedactedSHA256 {
+		t.Error("Expected identical policies to share a digest")
+	}
+	if app.TrustPolicy.RedactedSHA256 == alice.Policies[0].RedactedSHA256 {
+		t.Error("Expected different documents to have different digests")
+	}
+
+	if len(result.Resources[0].ResourcePolicy.Statements) != 0 {
+		t.Error("Expected resource policy statements to be stripped")
+	}
+	if len(result.SCPs[0].Statements) != 0 {
+		t.Error("Expected SCP statements to be stripped")
+	}
+	if result.SCPs[0].ID != "p-guardrail" {
+		t.Error("Expected SCP ID to survive redaction")
+	}
+}
+
+func TestRedactMultiAccountPolicies(t *testing.T) {
+	multi := &types.MultiAccountCollectionResult{
+		Accounts: map[string]*types.CollectionResult{
+			"111111111111": {
+				AccountID: "111111111111",
+				Principals: []*types.Principal{
+					{
+						ARN:  "arn:aws:iam::111111111111:user/bob",
+						Type: types.PrincipalTypeUser,
+						Name: "bob",
+						Policies: []types.PolicyDocument{
+							{
+								Version: "2012-10-17",
+								Statements: []types.Statement{
+									{Effect: types.EffectAllow, Action: "*", Resource: "*"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		SCPAttachments: []types.SCPAttachment{
+			{
+				Policy: types.PolicyDocument{
+					ID:      "p-org",
+					Version: "2012-10-17",
+					Statements: []types.Statement{
+						{Effect: types.EffectDeny, Action: "organizations:LeaveOrganization", Resource: "*"},
+					},
+				},
+			},
+		},
+	}
+
+	RedactMultiAccountPolicies(multi)
+
+	bob := multi.Accounts["111111111111"].Principals[0]
+	if len(bob.Policies[0].Statements) != 0 || bob.Policies[0].RedactedSHA256 == "" {
+		t.Error("Expected account principal policies to be redacted")
+	}
+	if len(multi.SCPAttachments[0].Policy.Statements) != 0 {
+		t.Error("Expected org SCP attachment statements to be stripped")
+	}
+}
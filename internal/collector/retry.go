@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// Defaults for retrying throttled AWS calls. IAM throttles aggressively on
+// large accounts, and the SDK default of 3 attempts gives up early enough to
+// abort a long collection over a transient rate limit
+const (
+	defaultMaxRetries   = 8
+	defaultRetryBackoff = 30 * time.Second
+)
+
+// RetryConfig tunes how SDK calls back off and retry when throttled. Zero
+// values fall back to the package defaults above
+type RetryConfig struct {
+	MaxRetries int           // Maximum attempts per API call
+	Backoff    time.Duration // Upper bound on the exponential backoff between attempts
+}
+
+// newRetryer builds the retryer installed on every SDK client: the SDK's
+// standard exponential backoff with jitter, with a larger attempt budget so
+// Throttling/RateExceeded responses are absorbed instead of failing Collect
+func newRetryer(rc RetryConfig) aws.Retryer {
+	maxAttempts := rc.MaxRetries
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxRetries
+	}
+	maxBackoff := rc.Backoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryBackoff
+	}
+
+	return retry.NewStandard(func(o *retry.StandardOptions) {
+		o.MaxAttempts = maxAttempts
+		o.MaxBackoff = maxBackoff
+	})
+}
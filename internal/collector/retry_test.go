@@ -0,0 +1,91 @@
+package collector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+const throttleResponseBody = `<ErrorResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error>
+  <RequestId>test</RequestId>
+</ErrorResponse>`
+
+const listUsersResponseBody = `<ListUsersResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <ListUsersResult><Users/><IsTruncated>false</IsTruncated></ListUsersResult>
+  <ResponseMetadata><RequestId>test</RequestId></ResponseMetadata>
+</ListUsersResponse>`
+
+// throttlingHTTPClient returns Throttling for the first `failures` requests,
+// then succeeds, counting every attempt it sees
+type throttlingHTTPClient struct {
+	failures int
+	calls    int
+}
+
+func (c *throttlingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	body := listUsersResponseBody
+	status := http.StatusOK
+	if c.calls <= c.failures {
+		body = throttleResponseBody
+		status = http.StatusBadRequest
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+// newStubIAMClient builds an IAM client that talks to the stub instead of AWS
+func newStubIAMClient(stub *throttlingHTTPClient, rc RetryConfig) *iam.Client {
+	return iam.New(iam.Options{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  stub,
+		Retryer:     newRetryer(rc),
+	})
+}
+
+func TestRetryerRecoversFromThrottling(t *testing.T) {
+	stub := &throttlingHTTPClient{failures: 3}
+	client := newStubIAMClient(stub, RetryConfig{MaxRetries: 5, Backoff: time.Millisecond})
+
+	if _, err := client.ListUsers(context.Background(), &iam.ListUsersInput{}); err != nil {
+		t.Fatalf("ListUsers should succeed after retries, got: %v", err)
+	}
+	if stub.calls != 4 {
+		t.Errorf("expected 4 attempts (3 throttled + 1 success), got %d", stub.calls)
+	}
+}
+
+func TestRetryerGivesUpWhenBudgetExhausted(t *testing.T) {
+	stub := &throttlingHTTPClient{failures: 10}
+	client := newStubIAMClient(stub, RetryConfig{MaxRetries: 3, Backoff: time.Millisecond})
+
+	if _, err := client.ListUsers(context.Background(), &iam.ListUsersInput{}); err == nil {
+		t.Fatal("expected error once the retry budget is exhausted")
+	}
+	if stub.calls != 3 {
+		t.Errorf("expected exactly 3 attempts, got %d", stub.calls)
+	}
+}
+
+func TestNewRetryerDefaults(t *testing.T) {
+	r := newRetryer(RetryConfig{})
+	if got := r.MaxAttempts(); got != defaultMaxRetries {
+		t.Errorf("MaxAttempts() = %d, want %d", got, defaultMaxRetries)
+	}
+
+	r = newRetryer(RetryConfig{MaxRetries: 12})
+	if got := r.MaxAttempts(); got != 12 {
+		t.Errorf("MaxAttempts() = %d, want 12", got)
+	}
+}
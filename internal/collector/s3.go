@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/pfrederiksen/aws-access-map/pkg/types"
@@ -32,14 +33,35 @@ func (c *Collector) collectS3Resources(ctx context.Context) ([]*types.Resource,
 			continue
 		}
 
+		arn := fmt.Sprintf("arn:aws:s3:::%s", *bucket.Name)
+
+		// Fingerprint the bucket from the list entry alone: the creation date
+		// changes when a bucket is deleted and recreated under the same name
+		etag := ""
+		if bucket.CreationDate != nil {
+			etag = bucket.CreationDate.UTC().Format(time.RFC3339)
+		}
+
+		// On incremental runs, reuse the cached entry when the fingerprint is
+		// unchanged and skip the per-bucket policy and data-flow calls
+		if cached, ok := c.reusableResources[arn]; ok && etag != "" && cached.ETag == etag {
+			resources = append(resources, cached)
+			c.reusedResources++
+			continue
+		}
+
 		resource := &types.Resource{
-			ARN:       fmt.Sprintf("arn:aws:s3:::%s", *bucket.Name),
+			ARN:       arn,
 			Type:      types.ResourceTypeS3,
 			Name:      *bucket.Name,
 			Region:    c.region, // S3 buckets are global but we use configured region
 			AccountID: "",       // S3 doesn't expose owner account ID directly
+			ETag:      etag,
 		}
 
+		// Collect replication/notification destinations (cross-account data flow)
+		resource.DataFlows = c.collectBucketDataFlows(ctx, *bucket.Name)
+
 		// Try to get bucket policy
 		policyOutput, err := c.s3Client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
 			Bucket: bucket.Name,
@@ -82,3 +104,76 @@ func (c *Collector) collectS3Resources(ctx context.Context) ([]*types.Resource,
 
 	return resources, nil
 }
+
+// collectBucketDataFlows collects where data and events from a bucket flow to:
+// replication destination buckets and notification targets (Lambda/SQS/SNS)
+func (c *Collector) collectBucketDataFlows(ctx context.Context, bucketName string) []types.DataFlow {
+	var flows []types.DataFlow
+
+	// Replication configuration (most buckets don't have one)
+	replOutput, err := c.s3Client.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		if !strings.Contains(err.Error(), "ReplicationConfigurationNotFound") && c.debug {
+			fmt.Printf("DEBUG: Failed to get replication config for bucket %s: %v\n", bucketName, err)
+		}
+	} else if replOutput.ReplicationConfiguration != nil {
+		roleARN := ""
+		if replOutput.ReplicationConfiguration.Role != nil {
+			roleARN = *replOutput.ReplicationConfiguration.Role
+		}
+		for _, rule := range replOutput.ReplicationConfiguration.Rules {
+			if rule.Destination == nil || rule.Destination.Bucket == nil {
+				continue
+			}
+			flows = append(flows, types.DataFlow{
+				Type:      types.DataFlowReplication,
+				TargetARN: *rule.Destination.Bucket,
+				RoleARN:   roleARN,
+			})
+		}
+	}
+
+	// Notification configuration (returns empty config rather than an error)
+	notifOutput, err := c.s3Client.GetBucketNotificationConfiguration(ctx, &s3.GetBucketNotificationConfigurationInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		if c.debug {
+			fmt.Printf("DEBUG: Failed to get notification config for bucket %s: %v\n", bucketName, err)
+		}
+		return flows
+	}
+
+	for _, lambdaConfig := range notifOutput.LambdaFunctionConfigurations {
+		if lambdaConfig.LambdaFunctionArn != nil {
+			flows = append(flows, types.DataFlow{
+				Type:      types.DataFlowNotification,
+				TargetARN: *lambdaConfig.LambdaFunctionArn,
+			})
+		}
+	}
+	for _, queueConfig := range notifOutput.QueueConfigurations {
+		if queueConfig.QueueArn != nil {
+			flows = append(flows, types.DataFlow{
+				Type:      types.DataFlowNotification,
+				TargetARN: *queueConfig.QueueArn,
+			})
+		}
+	}
+	for _, topicConfig := range notifOutput.TopicConfigurations {
+		if topicConfig.TopicArn != nil {
+			flows = append(flows, types.DataFlow{
+				Type:      types.DataFlowNotification,
+				TargetARN: *topicConfig.TopicArn,
+			})
+		}
+	}
+
+	if c.debug && len(flows) > 0 {
+		fmt.Printf("DEBUG: Found %d data flow(s) for bucket %s\n", len(flows), bucketName)
+	}
+
+	return flows
+}
@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// collectS3AccessPoints collects S3 access points and their policies. An
+// access point is a second policy surface in front of a bucket, so each one
+// is recorded as its own resource with BucketARN linking it to the bucket it
+// fronts - the graph uses that link so who-can on the bucket also surfaces
+// principals granted only through an access point
+func (c *Collector) collectS3AccessPoints(ctx context.Context, accountID string) ([]*types.Resource, error) {
+	var resources []*types.Resource
+
+	client := s3control.NewFromConfig(c.baseCfg)
+
+	paginator := s3control.NewListAccessPointsPaginator(client, &s3control.ListAccessPointsInput{
+		AccountId: aws.String(accountID),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			if c.debug {
+				fmt.Printf("DEBUG: Failed to list S3 access points (may lack permissions): %v\n", err)
+			}
+			return resources, nil
+		}
+
+		for _, ap := range page.AccessPointList {
+			if ap.AccessPointArn == nil || ap.Name == nil {
+				continue
+			}
+
+			resource := &types.Resource{
+				ARN:       *ap.AccessPointArn,
+				Type:      types.ResourceTypeS3AccessPoint,
+				Name:      *ap.Name,
+				Region:    c.region,
+				AccountID: accountID,
+			}
+			if ap.Bucket != nil {
+				resource.BucketARN = fmt.Sprintf("arn:aws:s3:::%s", *ap.Bucket)
+			}
+
+			policyOutput, err := client.GetAccessPointPolicy(ctx, &s3control.GetAccessPointPolicyInput{
+				AccountId: aws.String(accountID),
+				Name:      ap.Name,
+			})
+
+			// Most access points delegate entirely to the bucket policy and
+			// have no policy of their own
+			if err != nil {
+				if strings.Contains(err.Error(), "NoSuchAccessPointPolicy") {
+					resources = append(resources, resource)
+					continue
+				}
+
+				if c.debug {
+					fmt.Printf("DEBUG: Failed to get policy for access point %s: %v\n", *ap.Name, err)
+				}
+				resources = append(resources, resource)
+				continue
+			}
+
+			if policyOutput.Policy != nil {
+				policyDoc, err := c.parsePolicy(*policyOutput.Policy)
+				if err != nil {
+					if c.debug {
+						fmt.Printf("DEBUG: Failed to parse policy for access point %s: %v\n", *ap.Name, err)
+					}
+					// Add resource without policy rather than failing completely
+					resources = append(resources, resource)
+					continue
+				}
+				resource.ResourcePolicy = policyDoc
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	if c.debug {
+		fmt.Printf("DEBUG: Found %d S3 access point(s)\n", len(resources))
+	}
+
+	return resources, nil
+}
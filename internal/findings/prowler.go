@@ -0,0 +1,83 @@
+// Package findings loads reports from external security scanners so their
+// results can be correlated with the access graph.
+package findings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Finding is one normalized external scanner finding
+type Finding struct {
+	CheckID     string `json:"check_id"`
+	CheckTitle  string `json:"check_title"`
+	ServiceName string `json:"service"`
+	Status      string `json:"status"` // PASS or FAIL
+	Severity    string `json:"severity"`
+	ResourceARN string `json:"resource_arn"`
+	ResourceID  string `json:"resource_id"`
+	AccountID   string `json:"account_id"`
+	Region      string `json:"region"`
+	Description string `json:"description"`
+}
+
+// prowlerFinding mirrors the fields this tool consumes from Prowler's native
+// JSON output (v3 array format)
+type prowlerFinding struct {
+	CheckID        string `json:"CheckID"`
+	CheckTitle     string `json:"CheckTitle"`
+	ServiceName    string `json:"ServiceName"`
+	Status         string `json:"Status"`
+	StatusExtended string `json:"StatusExtended"`
+	Severity       string `json:"Severity"`
+	ResourceArn    string `json:"ResourceArn"`
+	ResourceID     string `json:"ResourceId"`
+	AccountID      string `json:"AccountId"`
+	Region         string `json:"Region"`
+	Description    string `json:"Description"`
+}
+
+// LoadProwler reads a Prowler native-JSON report (the array format written by
+// prowler -M json) and normalizes its findings. Entries without a check ID
+// are skipped rather than failing the whole import.
+func LoadProwler(path string) ([]Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read findings file: %w", err)
+	}
+
+	var raw []prowlerFinding
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse Prowler JSON (expected the array format from 'prowler -M json'): %w", err)
+	}
+
+	var result []Finding
+	for _, f := range raw {
+		if f.CheckID == "" {
+			continue
+		}
+		description := f.StatusExtended
+		if description == "" {
+			description = f.Description
+		}
+		result = append(result, Finding{
+			CheckID:     f.CheckID,
+			CheckTitle:  f.CheckTitle,
+			ServiceName: f.ServiceName,
+			Status:      f.Status,
+			Severity:    f.Severity,
+			ResourceARN: f.ResourceArn,
+			ResourceID:  f.ResourceID,
+			AccountID:   f.AccountID,
+			Region:      f.Region,
+			Description: description,
+		})
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("findings file %s contains no usable findings", path)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package findings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleProwlerReport = `[
+  {
+    "CheckID": "iam_role_administratoraccess_policy",
+    "CheckTitle": "Ensure IAM Roles do not have AdministratorAccess policy attached",
+    "ServiceName": "iam",
+    "Status": "FAIL",
+    "StatusExtended": "Role ci-deploy has AdministratorAccess attached.",
+    "Severity": "high",
+    "ResourceArn": "arn:aws:iam::123456789012:role/ci-deploy",
+    "ResourceId": "ci-deploy",
+    "AccountId": "123456789012",
+    "Region": "us-east-1"
+  },
+  {
+    "CheckID": "s3_bucket_public_access",
+    "CheckTitle": "Ensure S3 buckets are not publicly accessible",
+    "ServiceName": "s3",
+    "Status": "PASS",
+    "Severity": "critical",
+    "ResourceArn": "arn:aws:s3:::prod-data",
+    "ResourceId": "prod-data",
+    "AccountId": "123456789012",
+    "Region": "us-east-1",
+    "Description": "Bucket is private."
+  },
+  {
+    "CheckTitle": "Entry without a check ID is skipped"
+  }
+]`
+
+func writeTestReport(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "prowler.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test report: %v", err)
+	}
+	return path
+}
+
+func TestLoadProwler(t *testing.T) {
+	path := writeTestReport(t, sampleProwlerReport)
+
+	result, err := LoadProwler(path)
+	if err != nil {
+		t.Fatalf("LoadProwler() failed: %v", err)
+	}
+
+	// The entry without a CheckID is skipped
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 findings, got %d", len(result))
+	}
+
+	first := result[0]
+	if first.CheckID != "iam_role_administratoraccess_policy" {
+		t.Errorf("Unexpected check ID: %s", first.CheckID)
+	}
+	if first.Status != "FAIL" || first.Severity != "high" {
+		t.Errorf("Unexpected status/severity: %s/%s", first.Status, first.Severity)
+	}
+	if first.ResourceARN != "arn:aws:iam::123456789012:role/ci-deploy" {
+		t.Errorf("Unexpected resource ARN: %s", first.ResourceARN)
+	}
+
+	// StatusExtended is preferred, Description is the fallback
+	if first.Description != "Role ci-deploy has AdministratorAccess attached." {
+		t.Errorf("Expected StatusExtended as description, got %q", first.Description)
+	}
+	if result[1].Description != "Bucket is private." {
+		t.Errorf("Expected Description fallback, got %q", result[1].Description)
+	}
+}
+
+func TestLoadProwler_InvalidJSON(t *testing.T) {
+	path := writeTestReport(t, `{"not": "an array"}`)
+
+	if _, err := LoadProwler(path); err == nil {
+		t.Fatal("Expected error for non-array input")
+	}
+}
+
+func TestLoadProwler_NoUsableFindings(t *testing.T) {
+	path := writeTestReport(t, `[{"CheckTitle": "no id"}]`)
+
+	if _, err := LoadProwler(path); err == nil {
+		t.Fatal("Expected error for a report without usable findings")
+	}
+}
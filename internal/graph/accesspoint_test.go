@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// TestBuild_AccessPointGrantsSurfaceOnBucket verifies that a principal whose
+// only grant comes from an S3 access point policy shows up when querying the
+// underlying bucket, via the BucketARN linkage
+func TestBuild_AccessPointGrantsSurfaceOnBucket(t *testing.T) {
+	userARN := "arn:aws:iam::123456789012:user/ap-only"
+	bucketARN := "arn:aws:s3:::prod-data"
+	apARN := "arn:aws:s3:us-east-1:123456789012:accesspoint/prod-ap"
+
+	collection := &types.CollectionResult{
+		AccountID: "123456789012",
+		Principals: []*types.Principal{
+			{
+				ARN:  userARN,
+				Type: types.PrincipalTypeUser,
+				Name: "ap-only",
+			},
+		},
+		Resources: []*types.Resource{
+			{
+				ARN:       bucketARN,
+				Type:      types.ResourceTypeS3,
+				Name:      "prod-data",
+				AccountID: "123456789012",
+			},
+			{
+				ARN:       apARN,
+				Type:      types.ResourceTypeS3AccessPoint,
+				Name:      "prod-ap",
+				AccountID: "123456789012",
+				BucketARN: bucketARN,
+				ResourcePolicy: &types.PolicyDocument{
+					Version: "2012-10-17",
+					Statements: []types.Statement{
+						{
+							Effect:    types.EffectAllow,
+							Principal: map[string]interface{}{"AWS": userARN},
+							Action:    "s3:GetObject",
+							Resource:  apARN + "/object/*",
+						},
+						{
+							Effect:    types.EffectDeny,
+							Principal: map[string]interface{}{"AWS": userARN},
+							Action:    "s3:PutObject",
+							Resource:  apARN + "/object/*",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	// Direct grant through the access point itself
+	if !g.CanAccess(userARN, "s3:GetObject", apARN+"/object/secret.txt") {
+		t.Error("Should allow s3:GetObject through the access point")
+	}
+
+	// The same grant mirrored onto the underlying bucket
+	if !g.CanAccess(userARN, "s3:GetObject", bucketARN) {
+		t.Error("Access point grant should surface on the underlying bucket")
+	}
+
+	// Ungranted actions are still not allowed on the bucket
+	if g.CanAccess(userARN, "s3:DeleteObject", bucketARN) {
+		t.Error("Should NOT allow s3:DeleteObject on the bucket")
+	}
+}
+
+// TestBuild_AccessPointDenyNotMirrored verifies that a Deny in an access
+// point policy blocks only the access point path, not direct bucket access
+func TestBuild_AccessPointDenyNotMirrored(t *testing.T) {
+	userARN := "arn:aws:iam::123456789012:user/writer"
+	bucketARN := "arn:aws:s3:::prod-data"
+	apARN := "arn:aws:s3:us-east-1:123456789012:accesspoint/readonly-ap"
+
+	collection := &types.CollectionResult{
+		AccountID: "123456789012",
+		Principals: []*types.Principal{
+			{
+				ARN:  userARN,
+				Type: types.PrincipalTypeUser,
+				Name: "writer",
+			},
+		},
+		Resources: []*types.Resource{
+			{
+				ARN:       bucketARN,
+				Type:      types.ResourceTypeS3,
+				Name:      "prod-data",
+				AccountID: "123456789012",
+				ResourcePolicy: &types.PolicyDocument{
+					Version: "2012-10-17",
+					Statements: []types.Statement{
+						{
+							Effect:    types.EffectAllow,
+							Principal: map[string]interface{}{"AWS": userARN},
+							Action:    "s3:PutObject",
+							Resource:  bucketARN + "/*",
+						},
+					},
+				},
+			},
+			{
+				ARN:       apARN,
+				Type:      types.ResourceTypeS3AccessPoint,
+				Name:      "readonly-ap",
+				AccountID: "123456789012",
+				BucketARN: bucketARN,
+				ResourcePolicy: &types.PolicyDocument{
+					Version: "2012-10-17",
+					Statements: []types.Statement{
+						{
+							Effect:    types.EffectDeny,
+							Principal: map[string]interface{}{"AWS": userARN},
+							Action:    "s3:PutObject",
+							Resource:  apARN + "/object/*",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	// The deny applies going through the access point
+	if g.CanAccess(userARN, "s3:PutObject", apARN+"/object/report.csv") {
+		t.Error("Should NOT allow s3:PutObject through the read-only access point")
+	}
+
+	// But direct bucket access granted by the bucket policy is untouched
+	if !g.CanAccess(userARN, "s3:PutObject", bucketARN+"/report.csv") {
+		t.Error("Access point deny should not block direct bucket access")
+	}
+}
@@ -423,3 +423,102 @@ func TestCanAccess_BoundaryBlocksAdminAccess(t *testing.T) {
 		t.Error("Expected admin to have EC2 access with PowerUserAccess boundary")
 	}
 }
+
+// TestIsBlockedByBoundary_NotAction tests the NotAction form of the
+// PowerUserAccess boundary: allow everything except the listed actions
+func TestIsBlockedByBoundary_NotAction(t *testing.T) {
+	g := New()
+
+	principal := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/developer",
+		Type: types.PrincipalTypeUser,
+		Name: "developer",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "*",
+						Resource: "*",
+					},
+				},
+			},
+		},
+		PermissionsBoundary: &types.PolicyDocument{
+			ID:      "boundary-notaction",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					NotAction: []string{"iam:*", "organizations:*"},
+					Resource:  "*",
+				},
+			},
+		},
+	}
+
+	g.AddPrincipal(principal)
+	ctx := conditions.NewDefaultContext()
+
+	// Actions outside the NotAction list are allowed
+	if g.isBlockedByBoundary(principal.ARN, "s3:GetObject", "arn:aws:s3:::my-bucket", ctx) {
+		t.Error("Expected NotAction boundary to allow s3:GetObject")
+	}
+
+	// Actions in the NotAction list fall through to implicit deny
+	if !g.isBlockedByBoundary(principal.ARN, "iam:CreateUser", "*", ctx) {
+		t.Error("Expected NotAction boundary to block iam:CreateUser")
+	}
+	if !g.isBlockedByBoundary(principal.ARN, "organizations:LeaveOrganization", "*", ctx) {
+		t.Error("Expected NotAction boundary to block organizations:LeaveOrganization")
+	}
+}
+
+// TestIsBlockedByBoundary_NotResource tests a boundary that allows actions on
+// everything except specific resources
+func TestIsBlockedByBoundary_NotResource(t *testing.T) {
+	g := New()
+
+	principal := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/developer",
+		Type: types.PrincipalTypeUser,
+		Name: "developer",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "*",
+						Resource: "*",
+					},
+				},
+			},
+		},
+		PermissionsBoundary: &types.PolicyDocument{
+			ID:      "boundary-notresource",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:      types.EffectAllow,
+					Action:      "s3:*",
+					NotResource: []string{"arn:aws:s3:::production-*", "arn:aws:s3:::production-*/*"},
+				},
+			},
+		},
+	}
+
+	g.AddPrincipal(principal)
+	ctx := conditions.NewDefaultContext()
+
+	// Resources outside the NotResource list are allowed
+	if g.isBlockedByBoundary(principal.ARN, "s3:GetObject", "arn:aws:s3:::dev-bucket/file.txt", ctx) {
+		t.Error("Expected NotResource boundary to allow access to dev bucket")
+	}
+
+	// Resources in the NotResource list fall through to implicit deny
+	if !g.isBlockedByBoundary(principal.ARN, "s3:GetObject", "arn:aws:s3:::production-data/file.txt", ctx) {
+		t.Error("Expected NotResource boundary to block access to production bucket")
+	}
+}
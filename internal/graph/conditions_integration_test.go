@@ -416,3 +416,68 @@ func TestRealWorld_ARNPatternMatching(t *testing.T) {
 		t.Error("Should deny ops user not matching ARN pattern")
 	}
 }
+
+func TestRealWorld_SourceArnRestrictedLambda(t *testing.T) {
+	// Lambda resource policy: only a specific S3 bucket may invoke the
+	// function, the standard S3 -> Lambda notification wiring
+	lambda := &types.Resource{
+		ARN:  "arn:aws:lambda:us-east-1:123456789012:function:process-uploads",
+		Type: types.ResourceTypeLambda,
+		Name: "process-uploads",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Sid:       "AllowS3Invoke",
+					Effect:    types.EffectAllow,
+					Principal: map[string]interface{}{"Service": "s3.amazonaws.com"},
+					Action:    "lambda:InvokeFunction",
+					Resource:  "arn:aws:lambda:us-east-1:123456789012:function:process-uploads",
+					Condition: map[string]map[string]interface{}{
+						"ArnLike": {
+							"aws:SourceArn": "arn:aws:s3:::upload-bucket",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	user := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/alice",
+		Type: types.PrincipalTypeUser,
+		Name: "alice",
+	}
+
+	collection := &types.CollectionResult{
+		Principals: []*types.Principal{user},
+		Resources:  []*types.Resource{lambda},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	// Invocation originating from the allowed bucket passes the condition
+	allowedCtx := &conditions.EvaluationContext{
+		SourceArn: "arn:aws:s3:::upload-bucket",
+	}
+	if !g.CanAccess("s3.amazonaws.com", "lambda:InvokeFunction", lambda.ARN, allowedCtx) {
+		t.Error("Should allow invocation from the permitted source ARN")
+	}
+
+	// A different bucket fails the ArnLike condition
+	otherCtx := &conditions.EvaluationContext{
+		SourceArn: "arn:aws:s3:::other-bucket",
+	}
+	if g.CanAccess("s3.amazonaws.com", "lambda:InvokeFunction", lambda.ARN, otherCtx) {
+		t.Error("Should deny invocation from a different source ARN")
+	}
+
+	// No source ARN in context at all also fails
+	emptyCtx := &conditions.EvaluationContext{}
+	if g.CanAccess("s3.amazonaws.com", "lambda:InvokeFunction", lambda.ARN, emptyCtx) {
+		t.Error("Should deny invocation when no source ARN is set")
+	}
+}
@@ -74,6 +74,40 @@ func TestCanAccessWithConditions_MFARequired(t *testing.T) {
 	}
 }
 
+func TestCanAccessWithConditions_DenyUnlessMFA(t *testing.T) {
+	g := New()
+	principalARN := "arn:aws:iam::123456789012:user/operator"
+	resourceARN := "*"
+	action := "ec2:TerminateInstances"
+
+	// Broad allow plus the AWS-recommended deny-unless-MFA statement.
+	// BoolIfExists is what makes this catch long-term credentials, where
+	// aws:MultiFactorAuthPresent is absent entirely.
+	g.AddEdge(principalARN, "ec2:*", "*", false)
+	denyCondition := map[string]map[string]interface{}{
+		"BoolIfExists": {
+			"aws:MultiFactorAuthPresent": "false",
+		},
+	}
+	g.AddEdgeWithConditions(principalARN, "*", "*", true, denyCondition, "DenyWithoutMFA", nil, nil)
+
+	// Test 1: No MFA (key absent) - deny matches
+	ctxWithoutMFA := &conditions.EvaluationContext{
+		MFAAuthenticated: false,
+	}
+	if g.CanAccess(principalARN, action, resourceARN, ctxWithoutMFA) {
+		t.Error("Should deny access without MFA")
+	}
+
+	// Test 2: MFA-authenticated session - deny doesn't match
+	ctxWithMFA := &conditions.EvaluationContext{
+		MFAAuthenticated: true,
+	}
+	if !g.CanAccess(principalARN, action, resourceARN, ctxWithMFA) {
+		t.Error("Should allow access with MFA")
+	}
+}
+
 func TestCanAccessWithConditions_OrgIDRestriction(t *testing.T) {
 	g := New()
 	principalARN := "arn:aws:iam::123456789012:user/cross-account"
@@ -0,0 +1,224 @@
+package graph
+
+import (
+	"github.com/pfrederiksen/aws-access-map/internal/policy"
+	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
+)
+
+// AccessDecision is the tri-state outcome of a detailed access check
+type AccessDecision string
+
+const (
+	// DecisionDenied means the principal cannot perform the action
+	DecisionDenied AccessDecision = "DENIED"
+	// DecisionConditional means access depends on conditions the evaluation
+	// context did not pin down (the grant MAY apply)
+	DecisionConditional AccessDecision = "CONDITIONAL"
+	// DecisionAllowed means access holds under the supplied context alone
+	DecisionAllowed AccessDecision = "ALLOWED"
+)
+
+// AccessResult pairs an access decision with the conditions that gate it.
+// Conditions is populated only for CONDITIONAL results and lists the policy
+// conditions that were evaluated against assumed (default) context values.
+type AccessResult struct {
+	Decision   AccessDecision
+	Conditions []string
+}
+
+// CanAccessDetail evaluates access like CanAccess but distinguishes grants
+// that hold regardless of request context (ALLOWED) from grants that only
+// matched because an unspecified condition was evaluated against permissive
+// defaults (CONDITIONAL). Deny evaluation is identical to CanAccess.
+func (g *Graph) CanAccessDetail(principalARN, action, resourceARN string, ctx ...*conditions.EvaluationContext) AccessResult {
+	// Use default context if not provided (permissive behavior)
+	var evalCtx *conditions.EvaluationContext
+	if len(ctx) > 0 {
+		evalCtx = ctx[0]
+	} else {
+		evalCtx = conditions.NewDefaultContext()
+	}
+
+	// Mirror CanAccess: principal-dependent context keys resolve against the
+	// queried principal when no explicit principal ARN was supplied
+	if evalCtx.PrincipalARN == "" {
+		derived := *evalCtx
+		derived.PrincipalARN = principalARN
+		evalCtx = &derived
+	}
+
+	// All deny-side logic (SCPs, boundaries, session policies, explicit
+	// denies, implicit deny) is shared with the boolean path
+	if !g.CanAccess(principalARN, action, resourceARN, evalCtx) {
+		return AccessResult{Decision: DecisionDenied}
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	unconditional, gating := g.classifyAllows(principalARN, action, resourceARN, evalCtx, make(map[string]bool))
+	if unconditional || len(gating) == 0 {
+		return AccessResult{Decision: DecisionAllowed}
+	}
+
+	return AccessResult{Decision: DecisionConditional, Conditions: gating}
+}
+
+// AccessEvidence identifies a policy statement that grants an access result,
+// so who-can output can say why a principal appears instead of just that it does
+type AccessEvidence struct {
+	PolicyName      string // Statement Sid / policy name the granting edge came from
+	ActionPattern   string // Action pattern as written in the statement
+	ResourcePattern string // Resource pattern as written in the statement
+	ViaGroup        string // Group ARN when the grant is inherited from a group
+	Conditional     bool   // True when conditions gate the grant
+}
+
+// ExplainAccess returns the allow edges (including edges inherited via groups)
+// that grant the principal the given access under the supplied context. It
+// reports evidence only; callers should use CanAccess/CanAccessDetail for the
+// actual decision, since denies, SCPs and boundaries are not re-checked here.
+func (g *Graph) ExplainAccess(principalARN, action, resourceARN string, ctx ...*conditions.EvaluationContext) []AccessEvidence {
+	var evalCtx *conditions.EvaluationContext
+	if len(ctx) > 0 {
+		evalCtx = ctx[0]
+	} else {
+		evalCtx = conditions.NewDefaultContext()
+	}
+	if evalCtx.PrincipalARN == "" {
+		derived := *evalCtx
+		derived.PrincipalARN = principalARN
+		evalCtx = &derived
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.collectEvidence(principalARN, "", action, resourceARN, evalCtx, make(map[string]bool))
+}
+
+// collectEvidence gathers the allow edges matching the access, recursing into
+// group memberships. viaGroup carries the group ARN for inherited edges.
+// Caller must hold g.mu.
+func (g *Graph) collectEvidence(principalARN, viaGroup, action, resourceARN string, evalCtx *conditions.EvaluationContext, visited map[string]bool) []AccessEvidence {
+	if visited[principalARN] {
+		return nil
+	}
+	visited[principalARN] = true
+
+	var evidence []AccessEvidence
+
+	if actionMap, ok := g.principalActions[principalARN]; ok {
+		for actionPattern, allowEdges := range actionMap {
+			if !policy.MatchesAction(actionPattern, action) {
+				continue
+			}
+			for _, edge := range allowEdges {
+				if edge.NotActionPatterns != nil && !policy.MatchesNotAction(edge.NotActionPatterns, action) {
+					continue
+				}
+				if !matchesPattern(edge.ResourceARN, resourceARN) {
+					continue
+				}
+				if edge.NotResourcePatterns != nil && !policy.MatchesNotResource(edge.NotResourcePatterns, resourceARN) {
+					continue
+				}
+				matched, err := conditions.Evaluate(edge.Conditions, evalCtx)
+				if err != nil || !matched {
+					continue
+				}
+
+				evidence = append(evidence, AccessEvidence{
+					PolicyName:      edge.PolicyName,
+					ActionPattern:   actionPattern,
+					ResourcePattern: edge.ResourceARN,
+					ViaGroup:        viaGroup,
+					Conditional:     len(edge.Conditions) > 0 && !conditions.Pinned(edge.Conditions, evalCtx),
+				})
+			}
+		}
+	}
+
+	if principal, exists := g.principals[principalARN]; exists {
+		for _, groupARN := range principal.GroupMemberships {
+			evidence = append(evidence, g.collectEvidence(groupARN, groupARN, action, resourceARN, evalCtx, visited)...)
+		}
+	}
+
+	return evidence
+}
+
+// classifyAllows scans the allow edges that grant the access (including edges
+// inherited from groups) and reports whether any of them holds without relying
+// on assumed context, plus the gating conditions of those that do not.
+// Caller must hold g.mu.
+func (g *Graph) classifyAllows(principalARN, action, resourceARN string, evalCtx *conditions.EvaluationContext, visited map[string]bool) (bool, []string) {
+	if visited[principalARN] {
+		return false, nil
+	}
+	visited[principalARN] = true
+
+	var gating []string
+	seen := make(map[string]bool)
+
+	if actionMap, ok := g.principalActions[principalARN]; ok {
+		for actionPattern, allowEdges := range actionMap {
+			if !policy.MatchesAction(actionPattern, action) {
+				continue
+			}
+			for _, edge := range allowEdges {
+				// Apply the same edge filters as CanAccess STEP 4
+				if edge.NotActionPatterns != nil && !policy.MatchesNotAction(edge.NotActionPatterns, action) {
+					continue
+				}
+				if !matchesPattern(edge.ResourceARN, resourceARN) {
+					continue
+				}
+				if edge.NotResourcePatterns != nil && !policy.MatchesNotResource(edge.NotResourcePatterns, resourceARN) {
+					continue
+				}
+				matched, err := conditions.Evaluate(edge.Conditions, evalCtx)
+				if err != nil || !matched {
+					continue
+				}
+
+				if len(edge.Conditions) == 0 || conditions.Pinned(edge.Conditions, evalCtx) {
+					// The grant holds under the supplied context alone
+					return true, nil
+				}
+
+				// The grant only matched because unspecified condition keys
+				// were evaluated permissively - record what gates it
+				for _, cond := range conditions.Describe(edge.Conditions) {
+					if !seen[cond] {
+						seen[cond] = true
+						gating = append(gating, cond)
+					}
+				}
+			}
+		}
+	}
+
+	// Check group memberships (users inherit group permissions)
+	if principal, exists := g.principals[principalARN]; exists {
+		for _, groupARN := range principal.GroupMemberships {
+			// Only classify groups that actually grant the access, so a
+			// blocked group edge cannot upgrade the decision to ALLOWED
+			if !g.CanAccess(groupARN, action, resourceARN, evalCtx) {
+				continue
+			}
+			unconditional, groupGating := g.classifyAllows(groupARN, action, resourceARN, evalCtx, visited)
+			if unconditional {
+				return true, nil
+			}
+			for _, cond := range groupGating {
+				if !seen[cond] {
+					seen[cond] = true
+					gating = append(gating, cond)
+				}
+			}
+		}
+	}
+
+	return false, gating
+}
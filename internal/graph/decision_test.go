@@ -0,0 +1,234 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestCanAccessDetail_UnconditionalAllow(t *testing.T) {
+	g := New()
+	principalARN := "arn:aws:iam::123456789012:user/alice"
+	resourceARN := "arn:aws:s3:::bucket/*"
+	action := "s3:GetObject"
+
+	g.AddEdge(principalARN, action, resourceARN, false)
+
+	result := g.CanAccessDetail(principalARN, action, resourceARN)
+	if result.Decision != DecisionAllowed {
+		t.Errorf("Expected ALLOWED for unconditional grant, got %s", result.Decision)
+	}
+	if len(result.Conditions) != 0 {
+		t.Errorf("Expected no conditions for unconditional grant, got %v", result.Conditions)
+	}
+}
+
+func TestCanAccessDetail_ConditionalOnUnspecifiedIP(t *testing.T) {
+	g := New()
+	principalARN := "arn:aws:iam::123456789012:user/alice"
+	resourceARN := "arn:aws:s3:::bucket/*"
+	action := "s3:GetObject"
+
+	ipCondition := map[string]map[string]interface{}{
+		"IpAddress": {
+			"aws:SourceIp": "203.0.113.0/24",
+		},
+	}
+	g.AddEdgeWithConditions(principalARN, action, resourceARN, false, ipCondition, "AllowFromOfficeIP", nil, nil)
+
+	// No source IP specified - the grant passes only because of the
+	// permissive default, so it MAY apply, not WILL
+	result := g.CanAccessDetail(principalARN, action, resourceARN)
+	if result.Decision != DecisionConditional {
+		t.Errorf("Expected CONDITIONAL when source IP is unspecified, got %s", result.Decision)
+	}
+	if len(result.Conditions) != 1 {
+		t.Fatalf("Expected 1 gating condition, got %v", result.Conditions)
+	}
+	if result.Conditions[0] != "IpAddress aws:SourceIp = 203.0.113.0/24" {
+		t.Errorf("Unexpected condition description: %s", result.Conditions[0])
+	}
+}
+
+func TestCanAccessDetail_PinnedConditionResolves(t *testing.T) {
+	g := New()
+	principalARN := "arn:aws:iam::123456789012:user/alice"
+	resourceARN := "arn:aws:s3:::bucket/*"
+	action := "s3:GetObject"
+
+	ipCondition := map[string]map[string]interface{}{
+		"IpAddress": {
+			"aws:SourceIp": "203.0.113.0/24",
+		},
+	}
+	g.AddEdgeWithConditions(principalARN, action, resourceARN, false, ipCondition, "AllowFromOfficeIP", nil, nil)
+
+	// Matching source IP supplied - the grant definitely holds
+	ctxMatch := &conditions.EvaluationContext{SourceIP: "203.0.113.50"}
+	result := g.CanAccessDetail(principalARN, action, resourceARN, ctxMatch)
+	if result.Decision != DecisionAllowed {
+		t.Errorf("Expected ALLOWED with matching pinned IP, got %s", result.Decision)
+	}
+
+	// Non-matching source IP supplied - no grant applies
+	ctxMiss := &conditions.EvaluationContext{SourceIP: "192.0.2.1"}
+	result = g.CanAccessDetail(principalARN, action, resourceARN, ctxMiss)
+	if result.Decision != DecisionDenied {
+		t.Errorf("Expected DENIED with non-matching pinned IP, got %s", result.Decision)
+	}
+}
+
+func TestCanAccessDetail_UnconditionalEdgeWinsOverConditional(t *testing.T) {
+	g := New()
+	principalARN := "arn:aws:iam::123456789012:user/alice"
+	resourceARN := "arn:aws:s3:::bucket/*"
+	action := "s3:GetObject"
+
+	ipCondition := map[string]map[string]interface{}{
+		"IpAddress": {
+			"aws:SourceIp": "203.0.113.0/24",
+		},
+	}
+	g.AddEdgeWithConditions(principalARN, action, resourceARN, false, ipCondition, "AllowFromOfficeIP", nil, nil)
+	g.AddEdge(principalARN, action, resourceARN, false)
+
+	// Another statement grants the same access with no conditions, so the
+	// conditional grant must not downgrade the decision
+	result := g.CanAccessDetail(principalARN, action, resourceARN)
+	if result.Decision != DecisionAllowed {
+		t.Errorf("Expected ALLOWED when an unconditional grant also matches, got %s", result.Decision)
+	}
+}
+
+func TestCanAccessDetail_ConditionalViaGroup(t *testing.T) {
+	g := New()
+	userARN := "arn:aws:iam::123456789012:user/alice"
+	groupARN := "arn:aws:iam::123456789012:group/developers"
+	resourceARN := "arn:aws:s3:::bucket/*"
+	action := "s3:GetObject"
+
+	g.AddPrincipal(&types.Principal{
+		ARN:              userARN,
+		Type:             types.PrincipalTypeUser,
+		Name:             "alice",
+		GroupMemberships: []string{groupARN},
+	})
+	g.AddPrincipal(&types.Principal{
+		ARN:  groupARN,
+		Type: types.PrincipalTypeGroup,
+		Name: "developers",
+	})
+
+	ipCondition := map[string]map[string]interface{}{
+		"IpAddress": {
+			"aws:SourceIp": "203.0.113.0/24",
+		},
+	}
+	g.AddEdgeWithConditions(groupARN, action, resourceARN, false, ipCondition, "AllowFromOfficeIP", nil, nil)
+
+	// The user inherits a conditional grant from the group
+	result := g.CanAccessDetail(userARN, action, resourceARN)
+	if result.Decision != DecisionConditional {
+		t.Errorf("Expected CONDITIONAL for inherited conditional grant, got %s", result.Decision)
+	}
+	if len(result.Conditions) != 1 || result.Conditions[0] != "IpAddress aws:SourceIp = 203.0.113.0/24" {
+		t.Errorf("Unexpected gating conditions: %v", result.Conditions)
+	}
+
+	// Pinning the source IP resolves the grant to a definite allow
+	ctx := conditions.NewDefaultContext()
+	ctx.SourceIP = "203.0.113.50"
+	result = g.CanAccessDetail(userARN, action, resourceARN, ctx)
+	if result.Decision != DecisionAllowed {
+		t.Errorf("Expected ALLOWED with pinned source IP, got %s", result.Decision)
+	}
+}
+
+func TestExplainAccess(t *testing.T) {
+	g := New()
+	userARN := "arn:aws:iam::123456789012:user/alice"
+	groupARN := "arn:aws:iam::123456789012:group/developers"
+	resourceARN := "arn:aws:s3:::bucket/*"
+	action := "s3:GetObject"
+
+	g.AddPrincipal(&types.Principal{
+		ARN:              userARN,
+		Type:             types.PrincipalTypeUser,
+		Name:             "alice",
+		GroupMemberships: []string{groupARN},
+	})
+	g.AddPrincipal(&types.Principal{
+		ARN:  groupARN,
+		Type: types.PrincipalTypeGroup,
+		Name: "developers",
+	})
+
+	g.AddEdgeWithConditions(userARN, "s3:Get*", resourceARN, false, nil, "AllowDirectRead", nil, nil)
+	g.AddEdgeWithConditions(groupARN, "s3:*", "*", false, nil, "AllowGroupS3", nil, nil)
+	// An unrelated edge must not appear as evidence
+	g.AddEdgeWithConditions(userARN, "sqs:SendMessage", "*", false, nil, "AllowSQS", nil, nil)
+
+	evidence := g.ExplainAccess(userARN, action, resourceARN)
+	if len(evidence) != 2 {
+		t.Fatalf("Expected 2 evidence entries, got %d: %v", len(evidence), evidence)
+	}
+
+	byPolicy := make(map[string]AccessEvidence)
+	for _, ev := range evidence {
+		byPolicy[ev.PolicyName] = ev
+	}
+
+	direct, ok := byPolicy["AllowDirectRead"]
+	if !ok {
+		t.Fatal("Missing evidence for the direct grant")
+	}
+	if direct.ViaGroup != "" {
+		t.Errorf("Direct grant should not carry a group, got %s", direct.ViaGroup)
+	}
+	if direct.ActionPattern != "s3:Get*" || direct.ResourcePattern != resourceARN {
+		t.Errorf("Unexpected direct evidence patterns: %+v", direct)
+	}
+
+	inherited, ok := byPolicy["AllowGroupS3"]
+	if !ok {
+		t.Fatal("Missing evidence for the group grant")
+	}
+	if inherited.ViaGroup != groupARN {
+		t.Errorf("Group grant ViaGroup = %q, want %s", inherited.ViaGroup, groupARN)
+	}
+}
+
+func TestExplainAccess_Conditional(t *testing.T) {
+	g := New()
+	userARN := "arn:aws:iam::123456789012:user/alice"
+	resourceARN := "arn:aws:s3:::bucket/*"
+
+	g.AddPrincipal(&types.Principal{
+		ARN:  userARN,
+		Type: types.PrincipalTypeUser,
+		Name: "alice",
+	})
+
+	ipCondition := map[string]map[string]interface{}{
+		"IpAddress": {
+			"aws:SourceIp": "203.0.113.0/24",
+		},
+	}
+	g.AddEdgeWithConditions(userARN, "s3:GetObject", resourceARN, false, ipCondition, "AllowFromOfficeIP", nil, nil)
+
+	evidence := g.ExplainAccess(userARN, "s3:GetObject", resourceARN)
+	if len(evidence) != 1 {
+		t.Fatalf("Expected 1 evidence entry, got %d", len(evidence))
+	}
+	if !evidence[0].Conditional {
+		t.Error("Evidence for a condition-gated grant should be marked conditional")
+	}
+
+	// A pinned context that fails the condition yields no evidence
+	ctx := conditions.NewDefaultContext()
+	ctx.SourceIP = "198.51.100.7"
+	if evidence := g.ExplainAccess(userARN, "s3:GetObject", resourceARN, ctx); len(evidence) != 0 {
+		t.Errorf("Expected no evidence when the condition fails, got %v", evidence)
+	}
+}
@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/pfrederiksen/aws-access-map/internal/policy"
+	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
+)
+
+// Explain step outcomes
+const (
+	ExplainOutcomePass    = "pass"     // Stage does not block; evaluation continues
+	ExplainOutcomeDeny    = "deny"     // Stage denies; evaluation stops
+	ExplainOutcomeAllow   = "allow"    // Stage grants the access
+	ExplainOutcomeNoMatch = "no match" // Stage found nothing that applies
+)
+
+// ExplainStep is one stage of the ordered evaluation trace produced by Explain
+type ExplainStep struct {
+	Stage    string `json:"stage"`
+	Outcome  string `json:"outcome"`
+	Detail   string `json:"detail"`
+	Sid      string `json:"sid,omitempty"`       // Statement SID that drove the decision, when known
+	ViaGroup string `json:"via_group,omitempty"` // Group ARN for inherited deny/allow matches
+}
+
+// Explanation is the full evaluation trace for one access question. Steps are
+// in the order AWS applies them: SCPs, RCPs, permission boundary, session
+// policy, explicit denies (direct and group), explicit allows (direct and
+// group), implicit deny
+type Explanation struct {
+	PrincipalARN string        `json:"principal"`
+	Action       string        `json:"action"`
+	ResourceARN  string        `json:"resource"`
+	Allowed      bool          `json:"allowed"`
+	Steps        []ExplainStep `json:"steps"`
+}
+
+// Explain traces the same evaluation CanAccess performs, recording the
+// outcome of each stage instead of just the final verdict. A denying stage
+// ends the trace; an allow records which statement granted and where it was
+// inherited from. The trace makes fail-closed condition behavior visible:
+// a deny whose condition could not be evaluated reports that explicitly
+func (g *Graph) Explain(principalARN, action, resourceARN string, ctx ...*conditions.EvaluationContext) *Explanation {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// Same context derivation as CanAccess
+	var evalCtx *conditions.EvaluationContext
+	if len(ctx) > 0 {
+		evalCtx = ctx[0]
+	} else {
+		evalCtx = conditions.NewDefaultContext()
+	}
+	if evalCtx.PrincipalARN == "" {
+		derived := *evalCtx
+		derived.PrincipalARN = principalARN
+		evalCtx = &derived
+	}
+
+	ex := &Explanation{
+		PrincipalARN: principalARN,
+		Action:       action,
+		ResourceARN:  resourceARN,
+	}
+
+	// Stage 1: SCPs (organization-level allowlist; root users are exempt)
+	switch {
+	case isRootUser(principalARN):
+		ex.addStep("SCP", ExplainOutcomePass, "root user is not subject to SCPs")
+	case len(g.scpsForPrincipal(principalARN)) == 0:
+		ex.addStep("SCP", ExplainOutcomePass, "no SCPs apply to this principal")
+	case g.isBlockedBySCP(principalARN, action, resourceARN, evalCtx):
+		ex.addStep("SCP", ExplainOutcomeDeny, fmt.Sprintf("no applicable SCP allows %s (or an SCP explicitly denies it)", action))
+		return ex
+	default:
+		ex.addStep("SCP", ExplainOutcomePass, fmt.Sprintf("an applicable SCP allows %s and none denies it", action))
+	}
+
+	// Stage 2: RCPs (organization-level guardrail on the resource)
+	if g.isBlockedByRCP(action, resourceARN, evalCtx) {
+		ex.addStep("RCP", ExplainOutcomeDeny, fmt.Sprintf("no RCP allows %s on this resource (or an RCP explicitly denies it)", action))
+		return ex
+	}
+	ex.addStep("RCP", ExplainOutcomePass, "no RCP blocks the action on this resource")
+
+	// Stage 3: Permission boundary (principal-level filter)
+	if principal, ok := g.principals[principalARN]; ok && principal.PermissionsBoundary != nil {
+		if g.isBlockedByBoundary(principalARN, action, resourceARN, evalCtx) {
+			ex.addStep("Permission boundary", ExplainOutcomeDeny, fmt.Sprintf("the attached boundary does not allow %s on this resource", action))
+			return ex
+		}
+		ex.addStep("Permission boundary", ExplainOutcomePass, "the attached boundary allows the action")
+	} else {
+		ex.addStep("Permission boundary", ExplainOutcomePass, "no permission boundary attached")
+	}
+
+	// Stage 4: Session policy (temporary session constraints from the context)
+	if evalCtx.SessionPolicy != nil {
+		if g.isBlockedBySessionPolicy(action, resourceARN, evalCtx) {
+			ex.addStep("Session policy", ExplainOutcomeDeny, fmt.Sprintf("the session policy does not allow %s on this resource", action))
+			return ex
+		}
+		ex.addStep("Session policy", ExplainOutcomePass, "the session policy allows the action")
+	} else {
+		ex.addStep("Session policy", ExplainOutcomePass, "no session policy in effect")
+	}
+
+	// Stage 5: Explicit denies, direct then inherited through groups
+	if step, denied := g.explainDenies(principalARN, "", action, resourceARN, evalCtx); denied {
+		ex.Steps = append(ex.Steps, step)
+		return ex
+	}
+	for _, groupARN := range g.transitiveGroups(principalARN) {
+		if step, denied := g.explainDenies(groupARN, groupARN, action, resourceARN, evalCtx); denied {
+			ex.Steps = append(ex.Steps, step)
+			return ex
+		}
+	}
+	ex.addStep("Explicit deny", ExplainOutcomeNoMatch, "no deny statement matches (directly or via groups)")
+
+	// Stage 6: Explicit allows, direct then inherited through groups. Evidence
+	// collection applies the same edge filters as CanAccess STEP 4
+	evidence := g.collectEvidence(principalARN, "", action, resourceARN, evalCtx, make(map[string]bool))
+	if len(evidence) > 0 {
+		ev := evidence[0]
+		stage := "Explicit allow"
+		detail := fmt.Sprintf("statement grants %s on %s", ev.ActionPattern, ev.ResourcePattern)
+		if ev.ViaGroup != "" {
+			stage = "Group inheritance"
+			detail = fmt.Sprintf("group grants %s on %s", ev.ActionPattern, ev.ResourcePattern)
+		}
+		if ev.Conditional {
+			detail += " (gated by conditions evaluated against default context)"
+		}
+		ex.Steps = append(ex.Steps, ExplainStep{
+			Stage:    stage,
+			Outcome:  ExplainOutcomeAllow,
+			Detail:   detail,
+			Sid:      ev.PolicyName,
+			ViaGroup: ev.ViaGroup,
+		})
+		ex.Allowed = true
+		return ex
+	}
+
+	// Stage 7: Implicit deny
+	ex.addStep("Implicit deny", ExplainOutcomeDeny, "no allow statement matches (directly or via groups)")
+	return ex
+}
+
+func (ex *Explanation) addStep(stage, outcome, detail string) {
+	ex.Steps = append(ex.Steps, ExplainStep{Stage: stage, Outcome: outcome, Detail: detail})
+}
+
+// explainDenies checks one principal's deny edges the way CanAccess STEP 3
+// does and, on a match, returns a step naming the denying statement. A deny
+// condition that cannot be evaluated fails closed, same as CanAccess, and the
+// step says so
+func (g *Graph) explainDenies(principalARN, viaGroup, action, resourceARN string, evalCtx *conditions.EvaluationContext) (ExplainStep, bool) {
+	stage := "Explicit deny"
+	if viaGroup != "" {
+		stage = "Group deny"
+	}
+
+	actionMap, ok := g.denies[principalARN]
+	if !ok {
+		return ExplainStep{}, false
+	}
+
+	for actionPattern, denyEdges := range actionMap {
+		if !policy.MatchesAction(actionPattern, action) {
+			continue
+		}
+		for _, edge := range denyEdges {
+			if edge.NotActionPatterns != nil && !policy.MatchesNotAction(edge.NotActionPatterns, action) {
+				continue
+			}
+			if !matchesPattern(edge.ResourceARN, resourceARN) {
+				continue
+			}
+			if edge.NotResourcePatterns != nil && !policy.MatchesNotResource(edge.NotResourcePatterns, resourceARN) {
+				continue
+			}
+
+			matched, err := conditions.Evaluate(edge.Conditions, evalCtx)
+			if err != nil {
+				return ExplainStep{
+					Stage:    stage,
+					Outcome:  ExplainOutcomeDeny,
+					Detail:   fmt.Sprintf("deny condition could not be evaluated (%v) - failing closed", err),
+					Sid:      edge.PolicyName,
+					ViaGroup: viaGroup,
+				}, true
+			}
+			if matched {
+				return ExplainStep{
+					Stage:    stage,
+					Outcome:  ExplainOutcomeDeny,
+					Detail:   fmt.Sprintf("statement denies %s on %s", actionPattern, edge.ResourceARN),
+					Sid:      edge.PolicyName,
+					ViaGroup: viaGroup,
+				}, true
+			}
+		}
+	}
+
+	return ExplainStep{}, false
+}
@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestExplain_AllowAndImplicitDeny(t *testing.T) {
+	alice := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/alice",
+		Type:      types.PrincipalTypeUser,
+		Name:      "alice",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Sid:      "AllowBucketRead",
+						Effect:   types.EffectAllow,
+						Action:   "s3:GetObject",
+						Resource: "arn:aws:s3:::data-bucket/*",
+					},
+				},
+			},
+		},
+	}
+
+	g, err := Build(&types.CollectionResult{
+		Principals: []*types.Principal{alice},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	explanation := g.Explain(alice.ARN, "s3:GetObject", "arn:aws:s3:::data-bucket/file.txt")
+	if !explanation.Allowed {
+		t.Fatal("Explain() should report access allowed")
+	}
+	last := explanation.Steps[len(explanation.Steps)-1]
+	if last.Stage != "Explicit allow" || last.Outcome != ExplainOutcomeAllow {
+		t.Errorf("final step = %+v, want an Explicit allow step", last)
+	}
+	if last.Sid != "AllowBucketRead" {
+		t.Errorf("final step Sid = %q, want AllowBucketRead", last.Sid)
+	}
+	// Verdict must agree with CanAccess
+	if !g.CanAccess(alice.ARN, "s3:GetObject", "arn:aws:s3:::data-bucket/file.txt") {
+		t.Error("CanAccess() disagrees with Explain()")
+	}
+
+	// An action nothing grants ends in an implicit deny
+	explanation = g.Explain(alice.ARN, "s3:DeleteObject", "arn:aws:s3:::data-bucket/file.txt")
+	if explanation.Allowed {
+		t.Fatal("Explain() should report access denied")
+	}
+	last = explanation.Steps[len(explanation.Steps)-1]
+	if last.Stage != "Implicit deny" || last.Outcome != ExplainOutcomeDeny {
+		t.Errorf("final step = %+v, want an Implicit deny step", last)
+	}
+}
+
+func TestExplain_ExplicitDenyNamesStatement(t *testing.T) {
+	bob := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/bob",
+		Type:      types.PrincipalTypeUser,
+		Name:      "bob",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Sid:      "AllowAllS3",
+						Effect:   types.EffectAllow,
+						Action:   "s3:*",
+						Resource: "*",
+					},
+					{
+						Sid:      "DenyProdWrites",
+						Effect:   types.EffectDeny,
+						Action:   "s3:PutObject",
+						Resource: "arn:aws:s3:::prod-bucket/*",
+					},
+				},
+			},
+		},
+	}
+
+	g, err := Build(&types.CollectionResult{
+		Principals: []*types.Principal{bob},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	explanation := g.Explain(bob.ARN, "s3:PutObject", "arn:aws:s3:::prod-bucket/file.txt")
+	if explanation.Allowed {
+		t.Fatal("Explain() should report access denied (explicit deny wins)")
+	}
+	last := explanation.Steps[len(explanation.Steps)-1]
+	if last.Stage != "Explicit deny" || last.Sid != "DenyProdWrites" {
+		t.Errorf("final step = %+v, want Explicit deny naming DenyProdWrites", last)
+	}
+	// The read is still allowed and traces to the allow statement
+	explanation = g.Explain(bob.ARN, "s3:GetObject", "arn:aws:s3:::prod-bucket/file.txt")
+	if !explanation.Allowed {
+		t.Fatal("Explain() should report the read allowed")
+	}
+	if sid := explanation.Steps[len(explanation.Steps)-1].Sid; sid != "AllowAllS3" {
+		t.Errorf("allow step Sid = %q, want AllowAllS3", sid)
+	}
+}
+
+func TestExplain_GroupInheritance(t *testing.T) {
+	group := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:group/readers",
+		Type:      types.PrincipalTypeGroup,
+		Name:      "readers",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Sid:      "GroupRead",
+						Effect:   types.EffectAllow,
+						Action:   "s3:GetObject",
+						Resource: "arn:aws:s3:::shared/*",
+					},
+				},
+			},
+		},
+	}
+	member := &types.Principal{
+		ARN:              "arn:aws:iam::123456789012:user/carol",
+		Type:             types.PrincipalTypeUser,
+		Name:             "carol",
+		AccountID:        "123456789012",
+		GroupMemberships: []string{group.ARN},
+	}
+
+	g, err := Build(&types.CollectionResult{
+		Principals: []*types.Principal{group, member},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	explanation := g.Explain(member.ARN, "s3:GetObject", "arn:aws:s3:::shared/doc.txt")
+	if !explanation.Allowed {
+		t.Fatal("Explain() should report access allowed via the group")
+	}
+	last := explanation.Steps[len(explanation.Steps)-1]
+	if last.Stage != "Group inheritance" || last.ViaGroup != group.ARN {
+		t.Errorf("final step = %+v, want Group inheritance via %s", last, group.ARN)
+	}
+	if last.Sid != "GroupRead" {
+		t.Errorf("final step Sid = %q, want GroupRead", last.Sid)
+	}
+}
@@ -2,7 +2,11 @@ package graph
 
 import (
 	"fmt"
+	"hash/fnv"
 	"log"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/pfrederiksen/aws-access-map/internal/policy"
@@ -37,8 +41,34 @@ type Graph struct {
 	// denies[principalARN][action] = []PermissionEdge
 	denies map[string]map[string][]PermissionEdge
 
+	// dataFlows[resourceARN] = destinations data/events flow to (replication, notifications)
+	dataFlows map[string][]types.DataFlow
+
 	// Organization-level constraints
 	scps []types.PolicyDocument // Service Control Policies from AWS Organizations
+	rcps []types.PolicyDocument // Resource Control Policies (resource-side guardrails)
+
+	// scpsByAccount holds per-account SCP sets for combined multi-account
+	// graphs; when populated it takes precedence over scps so each
+	// principal is evaluated under its own account's guardrails
+	scpsByAccount map[string][]types.PolicyDocument
+
+	// Misconfigurations noticed while building (e.g. trust statements whose
+	// Action is not an STS assume action), surfaced as report findings
+	trustPolicyIssues []TrustPolicyIssue
+
+	// Customer-managed policy inventory carried through from collection,
+	// so unattached policies stay visible to the unused-policy report
+	managedPolicies []types.ManagedPolicyInfo
+}
+
+// TrustPolicyIssue records a trust policy statement that cannot grant role
+// assumption, typically because its Action is not an STS assume action
+type TrustPolicyIssue struct {
+	RoleARN string
+	Sid     string
+	Actions []string
+	Reason  string
 }
 
 // New creates a new empty graph
@@ -49,6 +79,7 @@ func New() *Graph {
 		principalActions: make(map[string]map[string][]PermissionEdge),
 		trustRelations:   make(map[string][]string),
 		denies:           make(map[string]map[string][]PermissionEdge),
+		dataFlows:        make(map[string][]types.DataFlow),
 	}
 }
 
@@ -59,12 +90,73 @@ func Build(collection *types.CollectionResult) (*Graph, error) {
 	// Store SCPs (evaluated at query time, not preprocessed into edges)
 	// If SCPAttachments are available, filter SCPs for this account
 	if len(collection.SCPAttachments) > 0 {
-		g.scps = filterSCPsForAccount(collection.AccountID, collection.SCPAttachments, collection.OUHierarchy)
+		g.scps = filterSCPsForAccountCached(collection.AccountID, collection.SCPAttachments, collection.OUHierarchy)
 	} else {
 		// Fall back to legacy SCPs field (no filtering)
 		g.scps = collection.SCPs
 	}
 
+	// RCPs reuse the SCP attachment/hierarchy filtering (same target model)
+	if len(collection.RCPAttachments) > 0 {
+		g.rcps = filterSCPsForAccountCached(collection.AccountID, collection.RCPAttachments, collection.OUHierarchy)
+	}
+
+	if err := g.ingestCollection(collection); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// BuildMultiAccount constructs one combined graph from an org-wide dataset,
+// so who-can and path queries can cross account boundaries. Every account's
+// principals and resources go into the same graph; SCPs stay account-scoped
+// (g.scpsByAccount) so a guardrail attached to one OU doesn't leak into
+// evaluation for principals of another account.
+func BuildMultiAccount(multi *types.MultiAccountCollectionResult) (*Graph, error) {
+	g := New()
+	g.scpsByAccount = make(map[string][]types.PolicyDocument)
+
+	// Deterministic ingestion order keeps edge/dedup behavior reproducible
+	accountIDs := make([]string, 0, len(multi.Accounts))
+	for accountID := range multi.Accounts {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	for _, accountID := range accountIDs {
+		account := multi.Accounts[accountID]
+		if account == nil {
+			continue
+		}
+
+		// Per-account SCP filtering: prefer the account's own attachments,
+		// fall back to the org-wide set with that account's OU hierarchy
+		attachments := account.SCPAttachments
+		hierarchy := account.OUHierarchy
+		if len(attachments) == 0 {
+			attachments = multi.SCPAttachments
+		}
+		if hierarchy == nil {
+			hierarchy = multi.OUHierarchy[accountID]
+		}
+		if len(attachments) > 0 {
+			g.scpsByAccount[accountID] = filterSCPsForAccountCached(accountID, attachments, hierarchy)
+		} else if len(account.SCPs) > 0 {
+			g.scpsByAccount[accountID] = account.SCPs
+		}
+
+		if err := g.ingestCollection(account); err != nil {
+			return nil, fmt.Errorf("account %s: %w", accountID, err)
+		}
+	}
+
+	return g, nil
+}
+
+// ingestCollection adds one account's principals, resources, and derived
+// edges to the graph. Shared by Build and BuildMultiAccount.
+func (g *Graph) ingestCollection(collection *types.CollectionResult) error {
 	// Add all principals
 	for _, principal := range collection.Principals {
 		g.AddPrincipal(principal)
@@ -72,18 +164,29 @@ func Build(collection *types.CollectionResult) (*Graph, error) {
 		// Process identity policies
 		for _, policy := range principal.Policies {
 			if err := g.addPolicyEdges(principal.ARN, policy); err != nil {
-				return nil, fmt.Errorf("failed to process policy for %s: %w", principal.ARN, err)
+				return fmt.Errorf("failed to process policy for %s: %w", principal.ARN, err)
 			}
 		}
 
 		// Process trust policies (for roles)
 		if principal.TrustPolicy != nil {
 			if err := g.addTrustEdges(principal.ARN, *principal.TrustPolicy); err != nil {
-				return nil, fmt.Errorf("failed to process trust policy for %s: %w", principal.ARN, err)
+				return fmt.Errorf("failed to process trust policy for %s: %w", principal.ARN, err)
 			}
 		}
 	}
 
+	// Link Identity Center assignments so SSO users/groups can reach the
+	// AWSReservedSSO_* roles provisioned from their permission sets
+	g.linkSSOAssignments(collection.SSOAssignments)
+
+	// Model Lake Formation grants as permission edges so who-can on Glue
+	// resources reflects LF-granted access
+	g.addLakeFormationEdges(collection.LFGrants)
+
+	// Carry the managed-policy inventory through for unused-policy analysis
+	g.managedPolicies = append(g.managedPolicies, collection.ManagedPolicies...)
+
 	// Add all resources
 	for _, resource := range collection.Resources {
 		g.AddResource(resource)
@@ -91,12 +194,35 @@ func Build(collection *types.CollectionResult) (*Graph, error) {
 		// Process resource policies
 		if resource.ResourcePolicy != nil {
 			if err := g.addResourcePolicyEdges(resource.ARN, *resource.ResourcePolicy); err != nil {
-				return nil, fmt.Errorf("failed to process resource policy for %s: %w", resource.ARN, err)
+				return fmt.Errorf("failed to process resource policy for %s: %w", resource.ARN, err)
 			}
 		}
+
+		// An S3 access point is a second policy surface in front of its
+		// bucket: mirror its allow grants onto the bucket so who-can on the
+		// bucket surfaces principals granted only through the access point
+		if resource.BucketARN != "" && resource.ResourcePolicy != nil {
+			if err := g.addDelegatedPolicyEdges(resource.BucketARN, *resource.ResourcePolicy); err != nil {
+				return fmt.Errorf("failed to process access point policy for %s: %w", resource.ARN, err)
+			}
+		}
+
+		// Record data-flow edges (replication destinations, notification targets)
+		for _, flow := range resource.DataFlows {
+			g.AddDataFlow(resource.ARN, flow)
+		}
+
+		// Link compute workloads to the roles they run as, so path queries
+		// can start from an ECS task definition and step through its roles
+		if resource.TaskRoleARN != "" {
+			g.AddTrustRelation(resource.TaskRoleARN, resource.ARN)
+		}
+		if resource.ExecutionRoleARN != "" {
+			g.AddTrustRelation(resource.ExecutionRoleARN, resource.ARN)
+		}
 	}
 
-	return g, nil
+	return nil
 }
 
 // AddPrincipal adds a principal to the graph
@@ -119,7 +245,9 @@ func (g *Graph) AddEdge(principalARN, action, resourceARN string, isDeny bool) {
 	g.AddEdgeWithConditions(principalARN, action, resourceARN, isDeny, nil, "", nil, nil)
 }
 
-// AddEdgeWithConditions adds a permission edge with optional conditions
+// AddEdgeWithConditions adds a permission edge with optional conditions.
+// Identical edges are added only once, so merged datasets that contain the
+// same resource (and its policy) more than once don't double-count grants.
 func (g *Graph) AddEdgeWithConditions(principalARN, action, resourceARN string, isDeny bool, conditions map[string]map[string]interface{}, policyName string, notActions, notResources []string) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
@@ -136,15 +264,35 @@ func (g *Graph) AddEdgeWithConditions(principalARN, action, resourceARN string,
 		if g.denies[principalARN] == nil {
 			g.denies[principalARN] = make(map[string][]PermissionEdge)
 		}
+		if containsEdge(g.denies[principalARN][action], edge) {
+			return
+		}
 		g.denies[principalARN][action] = append(g.denies[principalARN][action], edge)
 	} else {
 		if g.principalActions[principalARN] == nil {
 			g.principalActions[principalARN] = make(map[string][]PermissionEdge)
 		}
+		if containsEdge(g.principalActions[principalARN][action], edge) {
+			return
+		}
 		g.principalActions[principalARN][action] = append(g.principalActions[principalARN][action], edge)
 	}
 }
 
+// containsEdge reports whether an identical permission edge is already present
+func containsEdge(edges []PermissionEdge, edge PermissionEdge) bool {
+	for _, existing := range edges {
+		if existing.ResourceARN == edge.ResourceARN &&
+			existing.PolicyName == edge.PolicyName &&
+			reflect.DeepEqual(existing.Conditions, edge.Conditions) &&
+			reflect.DeepEqual(existing.NotActionPatterns, edge.NotActionPatterns) &&
+			reflect.DeepEqual(existing.NotResourcePatterns, edge.NotResourcePatterns) {
+			return true
+		}
+	}
+	return false
+}
+
 // AddTrustRelation adds a trust relationship (trustor can assume trustee role)
 func (g *Graph) AddTrustRelation(trusteeRoleARN, trustorARN string) {
 	g.mu.Lock()
@@ -153,6 +301,67 @@ func (g *Graph) AddTrustRelation(trusteeRoleARN, trustorARN string) {
 	g.trustRelations[trusteeRoleARN] = append(g.trustRelations[trusteeRoleARN], trustorARN)
 }
 
+// AddDataFlow records that data or events from a resource flow to a target
+// (e.g. S3 replication destination or notification target)
+func (g *Graph) AddDataFlow(resourceARN string, flow types.DataFlow) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.dataFlows[resourceARN] = append(g.dataFlows[resourceARN], flow)
+}
+
+// GetDataFlows returns the data-flow destinations recorded for a resource
+func (g *Graph) GetDataFlows(resourceARN string) []types.DataFlow {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.dataFlows[resourceARN]
+}
+
+// linkSSOAssignments connects SSO users/groups to the permission-set roles
+// Identity Center provisioned for them (named AWSReservedSSO_<PermissionSet>_<suffix>)
+func (g *Graph) linkSSOAssignments(assignments []types.SSOAssignment) {
+	if len(assignments) == 0 {
+		return
+	}
+
+	g.mu.RLock()
+	// Map permission set name -> role ARNs of the reserved roles it created
+	rolesByPermissionSet := make(map[string][]string)
+	for arn, principal := range g.principals {
+		if principal.Type != types.PrincipalTypeRole {
+			continue
+		}
+		name := strings.TrimPrefix(principal.Name, "AWSReservedSSO_")
+		if name == principal.Name {
+			continue
+		}
+		// Strip the random suffix Identity Center appends to the role name
+		if idx := strings.LastIndex(name, "_"); idx > 0 {
+			name = name[:idx]
+		}
+		rolesByPermissionSet[name] = append(rolesByPermissionSet[name], arn)
+	}
+	g.mu.RUnlock()
+
+	for _, assignment := range assignments {
+		for _, roleARN := range rolesByPermissionSet[assignment.PermissionSetName] {
+			g.AddTrustRelation(roleARN, assignment.PrincipalARN)
+		}
+	}
+}
+
+// addLakeFormationEdges converts Lake Formation grants into permission edges.
+// LF permissions are namespaced as lakeformation:<Permission> to keep them
+// distinct from IAM actions against the same Glue resources.
+func (g *Graph) addLakeFormationEdges(grants []types.LakeFormationGrant) {
+	for _, grant := range grants {
+		for _, permission := range grant.Permissions {
+			g.AddEdgeWithConditions(grant.PrincipalARN, "lakeformation:"+permission, grant.ResourceARN, false, nil, "LakeFormationGrant", nil, nil)
+		}
+	}
+}
+
 // GetPrincipal retrieves a principal by ARN
 func (g *Graph) GetPrincipal(arn string) (*types.Principal, bool) {
 	g.mu.RLock()
@@ -193,6 +402,132 @@ func (g *Graph) GetAllResources() []*types.Resource {
 	return resources
 }
 
+// ExportedEdge is a flattened permission edge for export formats
+type ExportedEdge struct {
+	PrincipalARN string
+	Action       string
+	ResourceARN  string
+	PolicyName   string
+	Conditional  bool                                // Edge carries policy conditions
+	Conditions   map[string]map[string]interface{} // The conditions themselves (AWS format)
+	IsDeny       bool
+}
+
+// TrustEdge records that a principal can appear in a role's trust policy
+type TrustEdge struct {
+	RoleARN      string
+	PrincipalARN string
+}
+
+// GetManagedPolicies returns the customer-managed policy inventory carried
+// through from collection
+func (g *Graph) GetManagedPolicies() []types.ManagedPolicyInfo {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	policies := make([]types.ManagedPolicyInfo, len(g.managedPolicies))
+	copy(policies, g.managedPolicies)
+	return policies
+}
+
+// GetPermissionEdges returns every permission edge (allows and denies)
+// flattened for export and visualization
+func (g *Graph) GetPermissionEdges() []ExportedEdge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var edges []ExportedEdge
+	for principalARN, actionMap := range g.principalActions {
+		for action, list := range actionMap {
+			for _, edge := range list {
+				edges = append(edges, ExportedEdge{
+					PrincipalARN: principalARN,
+					Action:       action,
+					ResourceARN:  edge.ResourceARN,
+					PolicyName:   edge.PolicyName,
+					Conditional:  len(edge.Conditions) > 0,
+					Conditions:   edge.Conditions,
+				})
+			}
+		}
+	}
+	for principalARN, actionMap := range g.denies {
+		for action, list := range actionMap {
+			for _, edge := range list {
+				edges = append(edges, ExportedEdge{
+					PrincipalARN: principalARN,
+					Action:       action,
+					ResourceARN:  edge.ResourceARN,
+					PolicyName:   edge.PolicyName,
+					Conditional:  len(edge.Conditions) > 0,
+					Conditions:   edge.Conditions,
+					IsDeny:       true,
+				})
+			}
+		}
+	}
+	return edges
+}
+
+// GetTrustEdges returns every trust relation as (role, trusted principal)
+// pairs for export and visualization
+func (g *Graph) GetTrustEdges() []TrustEdge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var edges []TrustEdge
+	for roleARN, principals := range g.trustRelations {
+		for _, principalARN := range principals {
+			edges = append(edges, TrustEdge{RoleARN: roleARN, PrincipalARN: principalARN})
+		}
+	}
+	return edges
+}
+
+// GetPrincipalActionNames returns the distinct actions appearing on a
+// principal's allow edges, including edges inherited through group
+// memberships. Actions are returned as written in the policies, so wildcard
+// patterns like "s3:*" come back verbatim. Used to enumerate candidate
+// actions for effective-permission checks.
+func (g *Graph) GetPrincipalActionNames(principalARN string) []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	g.collectActionNames(principalARN, seen, make(map[string]bool))
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectActionNames gathers edge action names for a principal and its
+// groups. visited guards against membership cycles.
+func (g *Graph) collectActionNames(principalARN string, seen, visited map[string]bool) {
+	if visited[principalARN] {
+		return
+	}
+	visited[principalARN] = true
+
+	for action := range g.principalActions[principalARN] {
+		seen[action] = true
+	}
+
+	if principal, ok := g.principals[principalARN]; ok {
+		for _, groupARN := range principal.GroupMemberships {
+			g.collectActionNames(groupARN, seen, visited)
+		}
+	}
+}
+
+// maxGroupDepth bounds the group-inheritance traversal in CanAccess. Pure IAM
+// groups cannot nest, but Identity Center and external IdP syncs produce
+// effective nested group structures in collected datasets
+const maxGroupDepth = 5
+
 // CanAccess checks if a principal can perform an action on a resource
 // Optional context parameter for condition evaluation (backward compatible)
 func (g *Graph) CanAccess(principalARN, action, resourceARN string, ctx ...*conditions.EvaluationContext) bool {
@@ -207,12 +542,160 @@ func (g *Graph) CanAccess(principalARN, action, resourceARN string, ctx ...*cond
 		evalCtx = conditions.NewDefaultContext()
 	}
 
+	// Derive principal-dependent context (aws:PrincipalArn, aws:PrincipalType)
+	// from the queried principal unless the caller pinned a principal ARN.
+	// Copied so the caller's context is not mutated across principals
+	if evalCtx.PrincipalARN == "" {
+		derived := *evalCtx
+		derived.PrincipalARN = principalARN
+		evalCtx = &derived
+	}
+
+	visited := map[string]bool{principalARN: true}
+	return g.canAccessLocked(principalARN, action, resourceARN, evalCtx, nil, visited, 0)
+}
+
+// SCPDecisionCache memoizes the per-account SCP verdict for one fixed
+// (action, resource, context) combination. The verdict is identical for every
+// principal in an account unless an SCP condition references the querying
+// principal; such accounts are detected and evaluated per principal. Not safe
+// for concurrent use
+type SCPDecisionCache struct {
+	blocked   map[string]bool // accountID -> SCP verdict
+	dependent map[string]bool // accountID -> conditions reference the principal
+}
+
+// NewSCPDecisionCache creates an empty cache for one (action, resource,
+// context) combination. Callers evaluating many principals against the same
+// query pass it to CanAccessCached
+func (g *Graph) NewSCPDecisionCache() *SCPDecisionCache {
+	return &SCPDecisionCache{
+		blocked:   make(map[string]bool),
+		dependent: make(map[string]bool),
+	}
+}
+
+// CanAccessCached is CanAccess with a memoized per-account SCP decision, for
+// callers that evaluate many principals against the same action and resource
+func (g *Graph) CanAccessCached(principalARN, action, resourceARN string, scpCache *SCPDecisionCache, ctx ...*conditions.EvaluationContext) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// Use default context if not provided (permissive behavior)
+	var evalCtx *conditions.EvaluationContext
+	if len(ctx) > 0 {
+		evalCtx = ctx[0]
+	} else {
+		evalCtx = conditions.NewDefaultContext()
+	}
+
+	if evalCtx.PrincipalARN == "" {
+		derived := *evalCtx
+		derived.PrincipalARN = principalARN
+		evalCtx = &derived
+	}
+
+	visited := map[string]bool{principalARN: true}
+	return g.canAccessLocked(principalARN, action, resourceARN, evalCtx, scpCache, visited, 0)
+}
+
+// isBlockedBySCPCached consults the memoized per-account SCP verdict when a
+// cache is supplied, falling back to full evaluation for root users and for
+// accounts whose SCP conditions depend on the querying principal
+func (g *Graph) isBlockedBySCPCached(principalARN, action, resourceARN string, ctx *conditions.EvaluationContext, cache *SCPDecisionCache) bool {
+	if cache == nil || isRootUser(principalARN) {
+		return g.isBlockedBySCP(principalARN, action, resourceARN, ctx)
+	}
+
+	accountID := accountIDFromARN(principalARN)
+	dependent, ok := cache.dependent[accountID]
+	if !ok {
+		dependent = scpConditionsDependOnPrincipal(g.scpsForPrincipal(principalARN))
+		cache.dependent[accountID] = dependent
+	}
+	if dependent {
+		return g.isBlockedBySCP(principalARN, action, resourceARN, ctx)
+	}
+
+	blocked, ok := cache.blocked[accountID]
+	if !ok {
+		blocked = g.isBlockedBySCP(principalARN, action, resourceARN, ctx)
+		cache.blocked[accountID] = blocked
+	}
+	return blocked
+}
+
+// scpConditionsDependOnPrincipal reports whether any SCP condition references
+// the querying principal - identity condition keys or policy variables - which
+// makes the verdict vary across principals in the same account. Conservative:
+// a match only disables caching for the account, never changes results
+func scpConditionsDependOnPrincipal(scps []types.PolicyDocument) bool {
+	for _, scp := range scps {
+		for _, stmt := range scp.Statements {
+			for _, operands := range stmt.Condition {
+				for key, value := range operands {
+					lower := strings.ToLower(key)
+					if strings.Contains(lower, "principal") || strings.Contains(lower, "username") || strings.Contains(lower, "userid") {
+						return true
+					}
+					for _, v := range normalizeToSlice(value) {
+						if strings.Contains(v, "${") {
+							return true
+						}
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// transitiveGroups returns the principal's group memberships expanded through
+// nested group structures, bounded by maxGroupDepth. The caller must hold
+// the read lock
+func (g *Graph) transitiveGroups(principalARN string) []string {
+	var groups []string
+	visited := map[string]bool{principalARN: true}
+
+	frontier := []string{principalARN}
+	for depth := 0; depth < maxGroupDepth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, arn := range frontier {
+			principal, ok := g.principals[arn]
+			if !ok {
+				continue
+			}
+			for _, groupARN := range principal.GroupMemberships {
+				if visited[groupARN] {
+					continue // Membership cycle - already expanded
+				}
+				visited[groupARN] = true
+				groups = append(groups, groupARN)
+				next = append(next, groupARN)
+			}
+		}
+		frontier = next
+	}
+
+	return groups
+}
+
+// canAccessLocked is the recursive core of CanAccess. The caller must hold
+// the read lock. visited and depth protect the group-inheritance traversal
+// against membership cycles and runaway nesting
+func (g *Graph) canAccessLocked(principalARN, action, resourceARN string, evalCtx *conditions.EvaluationContext, scpCache *SCPDecisionCache, visited map[string]bool, depth int) bool {
 	// STEP 0: Check SCPs (organization-level deny)
 	// SCPs are checked FIRST before any other policies
-	if g.isBlockedBySCP(principalARN, action, resourceARN, evalCtx) {
+	if g.isBlockedBySCPCached(principalARN, action, resourceARN, evalCtx, scpCache) {
 		return false // SCP denies this action organization-wide
 	}
 
+	// STEP 0.5: Check RCPs (organization-level guardrail on the resource)
+	// RCPs restrict what any principal may do with a resource
+	if g.isBlockedByRCP(action, resourceARN, evalCtx) {
+		return false // RCP denies this action on the resource
+	}
+
 	// STEP 1: Check permission boundaries (principal-level filter)
 	// Boundaries act as permission filters - action must be explicitly allowed
 	if g.isBlockedByBoundary(principalARN, action, resourceARN, evalCtx) {
@@ -267,10 +750,11 @@ func (g *Graph) CanAccess(principalARN, action, resourceARN string, ctx ...*cond
 	}
 
 	// STEP 3.5: Check for explicit deny from group policies (deny always wins)
-	// Users inherit deny rules from their groups
+	// Principals inherit deny rules from their groups, including groups
+	// reached through nested membership
 	if principal, exists := g.principals[principalARN]; exists {
 		if len(principal.GroupMemberships) > 0 {
-			for _, groupARN := range principal.GroupMemberships {
+			for _, groupARN := range g.transitiveGroups(principalARN) {
 				// Check if this group has any deny rules
 				if actionMap, ok := g.denies[groupARN]; ok {
 					for actionPattern, denyEdges := range actionMap {
@@ -352,15 +836,21 @@ func (g *Graph) CanAccess(principalARN, action, resourceARN string, ctx ...*cond
 		}
 	}
 
-	// STEP 4.5: Check group memberships (users inherit group permissions)
-	// If the principal is a user, check if any of their groups grant access
+	// STEP 4.5: Check group memberships (principals inherit group permissions)
+	// Traversal is multi-level for nested group structures, with cycle
+	// protection and a depth bound
 	if principal, exists := g.principals[principalARN]; exists {
-		if len(principal.GroupMemberships) > 0 {
+		if len(principal.GroupMemberships) > 0 && depth < maxGroupDepth {
 			for _, groupARN := range principal.GroupMemberships {
+				if visited[groupARN] {
+					continue // Membership cycle - already checked this group
+				}
+				visited[groupARN] = true
+
 				// Recursively check if the group has access
 				// Pass the evaluation context through to maintain condition checks
-				if g.CanAccess(groupARN, action, resourceARN, evalCtx) {
-					return true // User inherits permission from group
+				if g.canAccessLocked(groupARN, action, resourceARN, evalCtx, scpCache, visited, depth+1) {
+					return true // Principal inherits permission from group
 				}
 			}
 		}
@@ -391,8 +881,7 @@ func (g *Graph) GetRolesCanAssume(principalARN string) []*types.Principal {
 	// Iterate through all trust relationships to find roles that trust this principal
 	for roleARN, trustedPrincipals := range g.trustRelations {
 		for _, trusted := range trustedPrincipals {
-			// Check if this principal is explicitly trusted or if wildcard trust exists
-			if trusted == principalARN || trusted == "*" {
+			if trustMatchesPrincipal(trusted, principalARN) {
 				if role, ok := g.principals[roleARN]; ok {
 					roles = append(roles, role)
 				}
@@ -414,13 +903,71 @@ func (g *Graph) CanAssume(principalARN, roleARN string) bool {
 		return false
 	}
 
-	// Check if principal is explicitly trusted or if wildcard trust exists
 	for _, trusted := range trustedPrincipals {
-		if trusted == principalARN || trusted == "*" {
+		if trustMatchesPrincipal(trusted, principalARN) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AssumeRoleBlocked reports whether an sts:AssumeRole hop that the role's
+// trust policy admits would still be blocked in practice by an SCP, the
+// principal's permission boundary, a session policy, or an explicit identity
+// deny. FindPaths uses it so assume-role hops face the same gates CanAccess
+// applies to the final resource hop
+func (g *Graph) AssumeRoleBlocked(principalARN, roleARN string, ctx ...*conditions.EvaluationContext) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var evalCtx *conditions.EvaluationContext
+	if len(ctx) > 0 && ctx[0] != nil {
+		evalCtx = ctx[0]
+	} else {
+		evalCtx = conditions.NewDefaultContext()
+	}
+	if evalCtx.PrincipalARN == "" {
+		derived := *evalCtx
+		derived.PrincipalARN = principalARN
+		evalCtx = &derived
+	}
+
+	const action = "sts:AssumeRole"
+	if g.isBlockedBySCP(principalARN, action, roleARN, evalCtx) {
+		return true
+	}
+	if g.isBlockedByBoundary(principalARN, action, roleARN, evalCtx) {
+		return true
+	}
+	if g.isBlockedBySessionPolicy(action, roleARN, evalCtx) {
+		return true
+	}
+	if _, denied := g.explainDenies(principalARN, "", action, roleARN, evalCtx); denied {
+		return true
+	}
+	for _, groupARN := range g.transitiveGroups(principalARN) {
+		if _, denied := g.explainDenies(groupARN, groupARN, action, roleARN, evalCtx); denied {
 			return true
 		}
 	}
+	return false
+}
 
+// trustMatchesPrincipal reports whether a trusted-principal entry from a
+// trust policy covers the given principal. Besides exact ARNs and "*", an
+// account-root entry (arn:aws:iam::ACCOUNT:root) delegates to the whole
+// account: any principal whose ARN carries that account ID can assume the
+// role, which is how cross-account trust is normally expressed
+func trustMatchesPrincipal(trusted, principalARN string) bool {
+	if trusted == principalARN || trusted == "*" {
+		return true
+	}
+	if strings.HasPrefix(trusted, "arn:aws:iam::") && strings.HasSuffix(trusted, ":root") {
+		if accountID := accountIDFromARN(trusted); accountID != "" {
+			return accountIDFromARN(principalARN) == accountID
+		}
+	}
 	return false
 }
 
@@ -442,6 +989,11 @@ func (g *Graph) addPolicyEdges(principalARN string, policy types.PolicyDocument)
 			// NotResource without Resource: applies to all resources except NotResource
 			resources = []string{"*"}
 		}
+		if len(resources) == 0 {
+			// Statement with no Resource at all (legal in SCPs and for actions
+			// that take no resource): treat as "*" instead of dropping the grant
+			resources = []string{"*"}
+		}
 
 		isDeny := stmt.Effect == types.EffectDeny
 
@@ -455,13 +1007,42 @@ func (g *Graph) addPolicyEdges(principalARN string, policy types.PolicyDocument)
 	return nil
 }
 
-// addTrustEdges processes a trust policy and adds trust relationships
+// assumeActions are the STS actions a trust policy statement can meaningfully
+// grant. A statement whose Action matches none of these can never be used to
+// assume the role
+var assumeActions = []string{
+	"sts:AssumeRole",
+	"sts:AssumeRoleWithWebIdentity",
+	"sts:AssumeRoleWithSAML",
+	"sts:TagSession",
+}
+
+// addTrustEdges processes a trust policy and adds trust relationships.
+// Statements whose Action is missing or not an STS assume action are recorded
+// as trust policy issues instead of producing trust edges.
 func (g *Graph) addTrustEdges(roleARN string, trustPolicy types.PolicyDocument) error {
 	for _, stmt := range trustPolicy.Statements {
 		if stmt.Effect != types.EffectAllow {
 			continue
 		}
 
+		actions := normalizeToSlice(stmt.Action)
+		notActions := normalizeToSlice(stmt.NotAction)
+
+		if !grantsAssumeAction(actions, notActions) {
+			reason := "Action does not include an STS assume action"
+			if len(actions) == 0 {
+				reason = "statement has no Action"
+			}
+			g.trustPolicyIssues = append(g.trustPolicyIssues, TrustPolicyIssue{
+				RoleARN: roleARN,
+				Sid:     stmt.Sid,
+				Actions: actions,
+				Reason:  reason,
+			})
+			continue
+		}
+
 		// Extract principals from the statement
 		principals := extractPrincipals(stmt.Principal)
 		for _, principal := range principals {
@@ -471,6 +1052,33 @@ func (g *Graph) addTrustEdges(roleARN string, trustPolicy types.PolicyDocument)
 	return nil
 }
 
+// grantsAssumeAction reports whether a trust statement's action patterns cover
+// at least one STS assume action
+func grantsAssumeAction(actions, notActions []string) bool {
+	// NotAction without Action subtracts from "*", which still covers the
+	// assume actions unless they are all excluded
+	if len(actions) == 0 && len(notActions) > 0 {
+		actions = []string{"*"}
+	}
+	for _, pattern := range actions {
+		for _, assume := range assumeActions {
+			if policy.MatchesAction(pattern, assume) &&
+				(notActions == nil || policy.MatchesNotAction(notActions, assume)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetTrustPolicyIssues returns trust policy misconfigurations recorded during
+// graph construction
+func (g *Graph) GetTrustPolicyIssues() []TrustPolicyIssue {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.trustPolicyIssues
+}
+
 // addResourcePolicyEdges processes a resource policy and adds edges
 func (g *Graph) addResourcePolicyEdges(resourceARN string, policy types.PolicyDocument) error {
 	for _, stmt := range policy.Statements {
@@ -507,6 +1115,19 @@ func (g *Graph) addResourcePolicyEdges(resourceARN string, policy types.PolicyDo
 				principalARN = "*"
 			}
 
+			// Service principals (e.g. cloudtrail.amazonaws.com) have no IAM
+			// node of their own, so synthesize one - mirroring the public
+			// principal above - so who-can reports service access
+			if isServicePrincipalID(principalARN) {
+				if _, ok := g.GetPrincipal(principalARN); !ok {
+					g.AddPrincipal(&types.Principal{
+						ARN:  principalARN,
+						Type: types.PrincipalTypeService,
+						Name: principalARN,
+					})
+				}
+			}
+
 			// Add edge from principal to resource for each action
 			// Preserve conditions from resource policy
 			// Note: NotResource doesn't make sense for resource policies (the resource is already fixed)
@@ -520,6 +1141,32 @@ func (g *Graph) addResourcePolicyEdges(resourceARN string, policy types.PolicyDo
 	return nil
 }
 
+// addDelegatedPolicyEdges mirrors the Allow statements of a delegating
+// policy (an S3 access point policy) onto the resource it delegates to (the
+// underlying bucket), so who-can on the bucket surfaces principals whose only
+// grant is through the access point. Resource fields are dropped so every
+// mirrored edge targets the delegate ARN - object prefixes restricted in the
+// access point policy are over-approximated to the whole bucket. Deny
+// statements are not mirrored: a deny on the access point blocks that path
+// only, not direct bucket access
+func (g *Graph) addDelegatedPolicyEdges(delegateARN string, policy types.PolicyDocument) error {
+	mirrored := types.PolicyDocument{Version: policy.Version}
+	for _, stmt := range policy.Statements {
+		if stmt.Effect == types.EffectDeny {
+			continue
+		}
+		stmt.Resource = nil
+		stmt.NotResource = nil
+		mirrored.Statements = append(mirrored.Statements, stmt)
+	}
+
+	if len(mirrored.Statements) == 0 {
+		return nil
+	}
+
+	return g.addResourcePolicyEdges(delegateARN, mirrored)
+}
+
 // normalizeToSlice converts interface{} (string or []string) to []string
 func normalizeToSlice(v interface{}) []string {
 	switch val := v.(type) {
@@ -540,6 +1187,12 @@ func normalizeToSlice(v interface{}) []string {
 	}
 }
 
+// isServicePrincipalID reports whether an identifier is an AWS service
+// principal (e.g. cloudtrail.amazonaws.com) rather than an ARN
+func isServicePrincipalID(s string) bool {
+	return !strings.HasPrefix(s, "arn:") && strings.HasSuffix(s, ".amazonaws.com")
+}
+
 // extractPrincipals extracts principal ARNs from a policy statement
 func extractPrincipals(principal interface{}) []string {
 	var result []string
@@ -573,14 +1226,16 @@ func (g *Graph) isBlockedBySCP(principalARN, action, resourceARN string, ctx *co
 		return false
 	}
 
+	scps := g.scpsForPrincipal(principalARN)
+
 	// If no SCPs exist, nothing is blocked (from SCP perspective)
-	if len(g.scps) == 0 {
+	if len(scps) == 0 {
 		return false
 	}
 
 	// Step 1: Check if action is explicitly allowed by at least one SCP
 	hasExplicitAllow := false
-	for _, scp := range g.scps {
+	for _, scp := range scps {
 		for _, stmt := range scp.Statements {
 			if stmt.Effect != types.EffectAllow {
 				continue
@@ -599,6 +1254,10 @@ func (g *Graph) isBlockedBySCP(principalARN, action, resourceARN string, ctx *co
 			if len(notResources) > 0 && len(resources) == 0 {
 				resources = []string{"*"}
 			}
+			if len(resources) == 0 {
+				// SCP statements routinely omit Resource, which means "*"
+				resources = []string{"*"}
+			}
 
 			// Check if action matches
 			actionMatches := false
@@ -670,7 +1329,7 @@ func (g *Graph) isBlockedBySCP(principalARN, action, resourceARN string, ctx *co
 	}
 
 	// Step 3: Check for explicit deny (deny overrides allow)
-	for _, scp := range g.scps {
+	for _, scp := range scps {
 		for _, stmt := range scp.Statements {
 			if stmt.Effect != types.EffectDeny {
 				continue
@@ -689,6 +1348,10 @@ func (g *Graph) isBlockedBySCP(principalARN, action, resourceARN string, ctx *co
 			if len(notResources) > 0 && len(resources) == 0 {
 				resources = []string{"*"}
 			}
+			if len(resources) == 0 {
+				// SCP statements routinely omit Resource, which means "*"
+				resources = []string{"*"}
+			}
 
 			// Check if action matches
 			actionMatches := false
@@ -797,6 +1460,10 @@ func (g *Graph) isBlockedByBoundary(principalARN, action, resourceARN string, ct
 		if len(notResources) > 0 && len(resources) == 0 {
 			resources = []string{"*"}
 		}
+		if len(resources) == 0 {
+			// A statement with no Resource applies to "*"
+			resources = []string{"*"}
+		}
 
 		// Check if action matches
 		actionMatches := false
@@ -880,6 +1547,10 @@ func (g *Graph) isBlockedByBoundary(principalARN, action, resourceARN string, ct
 		if len(notResources) > 0 && len(resources) == 0 {
 			resources = []string{"*"}
 		}
+		if len(resources) == 0 {
+			// A statement with no Resource applies to "*"
+			resources = []string{"*"}
+		}
 
 		// Check if action matches
 		actionMatches := false
@@ -974,6 +1645,10 @@ func (g *Graph) isBlockedBySessionPolicy(action, resourceARN string, ctx *condit
 		if len(notResources) > 0 && len(resources) == 0 {
 			resources = []string{"*"}
 		}
+		if len(resources) == 0 {
+			// A statement with no Resource applies to "*"
+			resources = []string{"*"}
+		}
 
 		// Check if action matches
 		actionMatches := false
@@ -1057,6 +1732,10 @@ func (g *Graph) isBlockedBySessionPolicy(action, resourceARN string, ctx *condit
 		if len(notResources) > 0 && len(resources) == 0 {
 			resources = []string{"*"}
 		}
+		if len(resources) == 0 {
+			// A statement with no Resource applies to "*"
+			resources = []string{"*"}
+		}
 
 		// Check if action matches
 		actionMatches := false
@@ -1137,6 +1816,120 @@ func isRootUser(arn string) bool {
 	return false
 }
 
+// scpsForPrincipal returns the SCP set that governs a principal. Single-
+// account graphs share one list; combined multi-account graphs pick the set
+// for the principal's own account. An account with no entry (e.g. a
+// principal merged in from outside the collected org) gets the shared list.
+func (g *Graph) scpsForPrincipal(principalARN string) []types.PolicyDocument {
+	if len(g.scpsByAccount) > 0 {
+		if accountID := accountIDFromARN(principalARN); accountID != "" {
+			if scps, ok := g.scpsByAccount[accountID]; ok {
+				return scps
+			}
+		}
+	}
+	return g.scps
+}
+
+// HasSCPs reports whether any SCPs are in force on this graph. Callers use
+// it to decide whether a candidate guardrail must carry its own allow
+// statement: with no SCPs attached nothing is allow-listed yet, so a
+// deny-only document would implicitly deny everything else.
+func (g *Graph) HasSCPs() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.scps) > 0 || len(g.scpsByAccount) > 0
+}
+
+// WithAdditionalSCP returns a graph that evaluates with one more SCP in
+// force, sharing all nodes and edges with the receiver. This is how
+// candidate guardrails are tested without rebuilding the graph; the
+// receiver is left untouched.
+func (g *Graph) WithAdditionalSCP(doc types.PolicyDocument) *Graph {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	copied := &Graph{
+		principals:        g.principals,
+		resources:         g.resources,
+		principalActions:  g.principalActions,
+		trustRelations:    g.trustRelations,
+		denies:            g.denies,
+		dataFlows:         g.dataFlows,
+		rcps:              g.rcps,
+		trustPolicyIssues: g.trustPolicyIssues,
+	}
+
+	copied.scps = append(append([]types.PolicyDocument{}, g.scps...), doc)
+	if len(g.scpsByAccount) > 0 {
+		copied.scpsByAccount = make(map[string][]types.PolicyDocument, len(g.scpsByAccount))
+		for accountID, scps := range g.scpsByAccount {
+			copied.scpsByAccount[accountID] = append(append([]types.PolicyDocument{}, scps...), doc)
+		}
+	}
+
+	return copied
+}
+
+// accountIDFromARN extracts the account field of an ARN
+// (arn:aws:iam::123456789012:user/alice -> "123456789012")
+func accountIDFromARN(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) >= 5 {
+		return parts[4]
+	}
+	return ""
+}
+
+// scpFilterMemo caches filterSCPsForAccount results. When N account graphs
+// are built from one shared org-wide attachment set, the OU-hierarchy
+// filtering otherwise reruns from scratch for every account; the memo is
+// keyed by account plus a content hash of the attachments and hierarchy so a
+// changed dataset never serves stale results. Safe for concurrent builds.
+var scpFilterMemo = struct {
+	mu      sync.Mutex
+	results map[string][]types.PolicyDocument
+}{results: make(map[string][]types.PolicyDocument)}
+
+// scpFilterMemoLimit bounds the memo so long-running embedders that build
+// graphs from many distinct datasets don't grow it without end
+const scpFilterMemoLimit = 1024
+
+// scpFilterKey derives the memo key from the account and a content hash of
+// the attachment set and OU hierarchy
+func scpFilterKey(accountID string, attachments []types.SCPAttachment, ouHierarchy *types.OUHierarchy) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%v", attachments)
+	if ouHierarchy != nil {
+		fmt.Fprintf(h, "|%v", ouHierarchy.ParentOUs)
+	}
+	return fmt.Sprintf("%s:%x", accountID, h.Sum64())
+}
+
+// filterSCPsForAccountCached is the memoized front door to
+// filterSCPsForAccount; callers must treat the returned slice as read-only
+// since it is shared across graphs built from the same dataset
+func filterSCPsForAccountCached(accountID string, attachments []types.SCPAttachment, ouHierarchy *types.OUHierarchy) []types.PolicyDocument {
+	key := scpFilterKey(accountID, attachments, ouHierarchy)
+
+	scpFilterMemo.mu.Lock()
+	cached, ok := scpFilterMemo.results[key]
+	scpFilterMemo.mu.Unlock()
+	if ok {
+		return cached
+	}
+
+	filtered := filterSCPsForAccount(accountID, attachments, ouHierarchy)
+
+	scpFilterMemo.mu.Lock()
+	if len(scpFilterMemo.results) >= scpFilterMemoLimit {
+		scpFilterMemo.results = make(map[string][]types.PolicyDocument)
+	}
+	scpFilterMemo.results[key] = filtered
+	scpFilterMemo.mu.Unlock()
+	return filtered
+}
+
 // filterSCPsForAccount filters SCPs to only those that apply to the given account
 // SCPs can be attached to:
 // 1. The account directly (ACCOUNT target)
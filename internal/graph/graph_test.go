@@ -282,6 +282,192 @@ func TestBuildWithTrustPolicy(t *testing.T) {
 	}
 }
 
+func TestBuildDuplicateResourceEntries(t *testing.T) {
+	bucketARN := "arn:aws:s3:::shared-bucket"
+	readPolicy := &types.PolicyDocument{
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{
+				Effect: types.EffectAllow,
+				Principal: map[string]interface{}{
+					"AWS": "arn:aws:iam::123456789012:user/alice",
+				},
+				Action:   "s3:GetObject",
+				Resource: bucketARN,
+			},
+		},
+	}
+	writePolicy := &types.PolicyDocument{
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{
+				Effect: types.EffectAllow,
+				Principal: map[string]interface{}{
+					"AWS": "arn:aws:iam::123456789012:user/bob",
+				},
+				Action:   "s3:PutObject",
+				Resource: bucketARN,
+			},
+		},
+	}
+
+	collection := &types.CollectionResult{
+		Principals: []*types.Principal{
+			{ARN: "arn:aws:iam::123456789012:user/alice", Type: types.PrincipalTypeUser, Name: "alice"},
+			{ARN: "arn:aws:iam::123456789012:user/bob", Type: types.PrincipalTypeUser, Name: "bob"},
+		},
+		Resources: []*types.Resource{
+			// Duplicate entries for the same ARN, as merged datasets can contain
+			{ARN: bucketARN, Type: types.ResourceTypeS3, Name: "shared-bucket", ResourcePolicy: readPolicy},
+			{ARN: bucketARN, Type: types.ResourceTypeS3, Name: "shared-bucket", ResourcePolicy: writePolicy},
+		},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// Grants from both duplicate entries must be considered
+	if !g.CanAccess("arn:aws:iam::123456789012:user/alice", "s3:GetObject", bucketARN) {
+		t.Error("Grant from the first duplicate entry was lost")
+	}
+	if !g.CanAccess("arn:aws:iam::123456789012:user/bob", "s3:PutObject", bucketARN) {
+		t.Error("Grant from the second duplicate entry was lost")
+	}
+}
+
+func TestAddEdgeWithConditions_DeduplicatesIdenticalEdges(t *testing.T) {
+	g := New()
+	userARN := "arn:aws:iam::123456789012:user/alice"
+	g.AddPrincipal(&types.Principal{ARN: userARN, Type: types.PrincipalTypeUser, Name: "alice"})
+
+	// The same grant added twice (e.g. a duplicated dataset entry)
+	g.AddEdge(userARN, "s3:GetObject", "arn:aws:s3:::bucket/*", false)
+	g.AddEdge(userARN, "s3:GetObject", "arn:aws:s3:::bucket/*", false)
+	// A different resource pattern is a distinct edge
+	g.AddEdge(userARN, "s3:GetObject", "arn:aws:s3:::other/*", false)
+
+	count := 0
+	for _, edge := range g.GetPermissionEdges() {
+		if edge.PrincipalARN == userARN && edge.Action == "s3:GetObject" {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 distinct edges after deduplication, got %d", count)
+	}
+}
+
+func TestBuildStatementWithoutResource(t *testing.T) {
+	collection := &types.CollectionResult{
+		Principals: []*types.Principal{
+			{
+				ARN:  "arn:aws:iam::123456789012:user/auditor",
+				Type: types.PrincipalTypeUser,
+				Name: "auditor",
+				Policies: []types.PolicyDocument{
+					{
+						Version: "2012-10-17",
+						Statements: []types.Statement{
+							{
+								Effect: types.EffectAllow,
+								// iam:ListUsers takes no resource; the statement
+								// legally omits Resource and must not be dropped
+								Action: "iam:ListUsers",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if !g.CanAccess("arn:aws:iam::123456789012:user/auditor", "iam:ListUsers", "*") {
+		t.Error("Statement without Resource should grant the action on *")
+	}
+	if g.CanAccess("arn:aws:iam::123456789012:user/auditor", "iam:DeleteUser", "*") {
+		t.Error("Statement without Resource must not grant other actions")
+	}
+}
+
+func TestBuildWithMismatchedTrustAction(t *testing.T) {
+	collection := &types.CollectionResult{
+		Principals: []*types.Principal{
+			{
+				ARN:  "arn:aws:iam::123456789012:role/BrokenRole",
+				Type: types.PrincipalTypeRole,
+				Name: "BrokenRole",
+				TrustPolicy: &types.PolicyDocument{
+					Version: "2012-10-17",
+					Statements: []types.Statement{
+						{
+							Sid:    "WrongAction",
+							Effect: types.EffectAllow,
+							Principal: map[string]interface{}{
+								"AWS": "arn:aws:iam::123456789012:user/alice",
+							},
+							// Not an STS assume action - the role cannot be assumed
+							Action: "s3:GetObject",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if trusted := g.GetTrustedPrincipals("arn:aws:iam::123456789012:role/BrokenRole"); len(trusted) != 0 {
+		t.Errorf("Expected no trust edges for non-assume action, got %v", trusted)
+	}
+
+	issues := g.GetTrustPolicyIssues()
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 trust policy issue, got %d", len(issues))
+	}
+	if issues[0].RoleARN != "arn:aws:iam::123456789012:role/BrokenRole" {
+		t.Errorf("Issue recorded for wrong role: %s", issues[0].RoleARN)
+	}
+	if issues[0].Sid != "WrongAction" {
+		t.Errorf("Issue Sid = %q, want WrongAction", issues[0].Sid)
+	}
+}
+
+func TestGrantsAssumeAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		actions    []string
+		notActions []string
+		want       bool
+	}{
+		{"Exact assume action", []string{"sts:AssumeRole"}, nil, true},
+		{"SAML variant", []string{"sts:AssumeRoleWithSAML"}, nil, true},
+		{"Wildcard action", []string{"*"}, nil, true},
+		{"Service wildcard", []string{"sts:*"}, nil, true},
+		{"Wrong service", []string{"s3:GetObject"}, nil, false},
+		{"No actions at all", nil, nil, false},
+		{"NotAction only", nil, []string{"iam:*"}, true},
+		{"NotAction excludes all assume actions", nil, []string{"sts:*"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grantsAssumeAction(tt.actions, tt.notActions); got != tt.want {
+				t.Errorf("grantsAssumeAction(%v, %v) = %v, want %v", tt.actions, tt.notActions, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNormalizeToSlice(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -508,6 +694,55 @@ func TestGetRolesCanAssume_Wildcard(t *testing.T) {
 	}
 }
 
+func TestGetRolesCanAssume_AccountRoot(t *testing.T) {
+	g := New()
+
+	// Principal in account 111122223333, role in another account that trusts
+	// the whole 111122223333 account via its root ARN
+	user := &types.Principal{
+		ARN:  "arn:aws:iam::111122223333:user/alice",
+		Type: types.PrincipalTypeUser,
+		Name: "alice",
+	}
+	role := &types.Principal{
+		ARN:  "arn:aws:iam::999988887777:role/CrossAccountRole",
+		Type: types.PrincipalTypeRole,
+		Name: "CrossAccountRole",
+	}
+	outsider := &types.Principal{
+		ARN:  "arn:aws:iam::444455556666:user/mallory",
+		Type: types.PrincipalTypeUser,
+		Name: "mallory",
+	}
+
+	g.AddPrincipal(user)
+	g.AddPrincipal(role)
+	g.AddPrincipal(outsider)
+
+	// Account-root trust delegates to every principal in 111122223333
+	g.AddTrustRelation(role.ARN, "arn:aws:iam::111122223333:root")
+
+	roles := g.GetRolesCanAssume(user.ARN)
+	if len(roles) != 1 {
+		t.Fatalf("GetRolesCanAssume() returned %d roles, want 1 (account-root trust)", len(roles))
+	}
+	if roles[0].ARN != role.ARN {
+		t.Errorf("GetRolesCanAssume() returned %s, want %s", roles[0].ARN, role.ARN)
+	}
+
+	// Principals in other accounts are not covered
+	if len(g.GetRolesCanAssume(outsider.ARN)) != 0 {
+		t.Error("GetRolesCanAssume() should not match principals outside the trusted account")
+	}
+
+	if !g.CanAssume(user.ARN, role.ARN) {
+		t.Error("CanAssume() should honor account-root trust")
+	}
+	if g.CanAssume(outsider.ARN, role.ARN) {
+		t.Error("CanAssume() should reject principals outside the trusted account")
+	}
+}
+
 func TestGetRolesCanAssume_Multiple(t *testing.T) {
 	g := New()
 
@@ -624,3 +859,168 @@ func TestCanAssume_Wildcard(t *testing.T) {
 		t.Error("CanAssume() returned false, want true for wildcard trust with non-existent principal")
 	}
 }
+
+func TestBuild_LinksSSOAssignments(t *testing.T) {
+	ssoRole := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:role/aws-reserved/sso.amazonaws.com/AWSReservedSSO_AdminAccess_abc123def456",
+		Type:      types.PrincipalTypeRole,
+		Name:      "AWSReservedSSO_AdminAccess_abc123def456",
+		AccountID: "123456789012",
+	}
+
+	collection := &types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{ssoRole},
+		SSOAssignments: []types.SSOAssignment{
+			{
+				PermissionSetName: "AdminAccess",
+				AccountID:         "123456789012",
+				PrincipalARN:      "arn:aws:identitystore::d-1234567890:user/user-id-1",
+				PrincipalType:     types.PrincipalTypeSSOUser,
+				PrincipalName:     "alice@example.com",
+			},
+		},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	trusted := g.GetTrustedPrincipals(ssoRole.ARN)
+	if len(trusted) != 1 {
+		t.Fatalf("GetTrustedPrincipals() returned %d principals, want 1", len(trusted))
+	}
+	if trusted[0] != "arn:aws:identitystore::d-1234567890:user/user-id-1" {
+		t.Errorf("GetTrustedPrincipals() returned %s, want SSO user ARN", trusted[0])
+	}
+}
+
+func TestBuild_SSOAssignmentNoMatchingRole(t *testing.T) {
+	// Assignment for a permission set whose role wasn't collected - must not panic
+	collection := &types.CollectionResult{
+		AccountID: "123456789012",
+		SSOAssignments: []types.SSOAssignment{
+			{
+				PermissionSetName: "ReadOnly",
+				AccountID:         "123456789012",
+				PrincipalARN:      "arn:aws:identitystore::d-1234567890:group/group-id-1",
+				PrincipalType:     types.PrincipalTypeSSOGroup,
+			},
+		},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	if len(g.trustRelations) != 0 {
+		t.Errorf("expected no trust relations, got %d", len(g.trustRelations))
+	}
+}
+
+func TestBuild_RecordsDataFlows(t *testing.T) {
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::source-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "source-bucket",
+		DataFlows: []types.DataFlow{
+			{
+				Type:      types.DataFlowReplication,
+				TargetARN: "arn:aws:s3:::dest-bucket",
+				RoleARN:   "arn:aws:iam::123456789012:role/ReplicationRole",
+			},
+			{
+				Type:      types.DataFlowNotification,
+				TargetARN: "arn:aws:sqs:us-east-1:123456789012:events-queue",
+			},
+		},
+	}
+
+	collection := &types.CollectionResult{
+		AccountID: "123456789012",
+		Resources: []*types.Resource{bucket},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	flows := g.GetDataFlows(bucket.ARN)
+	if len(flows) != 2 {
+		t.Fatalf("GetDataFlows() returned %d flows, want 2", len(flows))
+	}
+	if flows[0].Type != types.DataFlowReplication || flows[0].TargetARN != "arn:aws:s3:::dest-bucket" {
+		t.Errorf("unexpected replication flow: %+v", flows[0])
+	}
+	if flows[1].Type != types.DataFlowNotification {
+		t.Errorf("unexpected notification flow: %+v", flows[1])
+	}
+
+	// Resources without flows return nothing
+	if got := g.GetDataFlows("arn:aws:s3:::other"); len(got) != 0 {
+		t.Errorf("GetDataFlows(other) returned %d flows, want 0", len(got))
+	}
+}
+
+func TestBuild_LakeFormationGrants(t *testing.T) {
+	analystARN := "arn:aws:iam::123456789012:role/data-analyst"
+	tableARN := "arn:aws:glue:us-east-1:123456789012:table/sales/orders"
+
+	collection := &types.CollectionResult{
+		AccountID: "123456789012",
+		Principals: []*types.Principal{
+			{ARN: analystARN, Type: types.PrincipalTypeRole, Name: "data-analyst"},
+		},
+		LFGrants: []types.LakeFormationGrant{
+			{
+				PrincipalARN: analystARN,
+				ResourceARN:  tableARN,
+				Permissions:  []string{"SELECT", "DESCRIBE"},
+			},
+		},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	if !g.CanAccess(analystARN, "lakeformation:SELECT", tableARN) {
+		t.Error("expected LF SELECT grant to be reachable via CanAccess")
+	}
+	if !g.CanAccess(analystARN, "lakeformation:DESCRIBE", tableARN) {
+		t.Error("expected LF DESCRIBE grant to be reachable via CanAccess")
+	}
+	if g.CanAccess(analystARN, "lakeformation:DROP", tableARN) {
+		t.Error("ungranted LF permission should not be reachable")
+	}
+}
+
+// TestCanAccess_PrincipalTypeCondition checks that aws:PrincipalType
+// restrictions resolve against each queried principal, so a grant limited to
+// assumed-role principals does not over-report access for IAM users
+func TestCanAccess_PrincipalTypeCondition(t *testing.T) {
+	g := New()
+	condition := map[string]map[string]interface{}{
+		"StringEquals": {
+			"aws:PrincipalType": "AssumedRole",
+		},
+	}
+
+	userARN := "arn:aws:iam::123456789012:user/alice"
+	roleARN := "arn:aws:iam::123456789012:role/app"
+	resourceARN := "arn:aws:s3:::data-bucket/file.txt"
+
+	g.AddEdgeWithConditions(userARN, "s3:GetObject", "arn:aws:s3:::data-bucket/*", false, condition, "RestrictedPolicy", nil, nil)
+	g.AddEdgeWithConditions(roleARN, "s3:GetObject", "arn:aws:s3:::data-bucket/*", false, condition, "RestrictedPolicy", nil, nil)
+
+	if g.CanAccess(userARN, "s3:GetObject", resourceARN) {
+		t.Error("Expected AssumedRole-only grant to NOT apply to an IAM user")
+	}
+	if !g.CanAccess(roleARN, "s3:GetObject", resourceARN) {
+		t.Error("Expected AssumedRole-only grant to apply to a role")
+	}
+}
@@ -386,3 +386,193 @@ func TestCanAccess_NonExistentGroupMembership(t *testing.T) {
 		t.Error("User should NOT have access to actions not in their direct policy")
 	}
 }
+
+// TestCanAccess_NestedGroups tests multi-level group inheritance. Pure IAM
+// groups cannot nest, but Identity Center and IdP syncs produce effective
+// nested group structures in collected datasets
+func TestCanAccess_NestedGroups(t *testing.T) {
+	g := New()
+
+	// Top-level group holds the actual grant
+	parent := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:group/AllEngineering",
+		Type: types.PrincipalTypeGroup,
+		Name: "AllEngineering",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   []string{"s3:GetObject"},
+						Resource: []string{"arn:aws:s3:::eng-bucket/*"},
+					},
+				},
+			},
+		},
+	}
+
+	// Intermediate group is itself a member of the top-level group
+	child := &types.Principal{
+		ARN:              "arn:aws:iam::123456789012:group/Backend",
+		Type:             types.PrincipalTypeGroup,
+		Name:             "Backend",
+		GroupMemberships: []string{"arn:aws:iam::123456789012:group/AllEngineering"},
+	}
+
+	user := &types.Principal{
+		ARN:              "arn:aws:iam::123456789012:user/heidi",
+		Type:             types.PrincipalTypeUser,
+		Name:             "heidi",
+		GroupMemberships: []string{"arn:aws:iam::123456789012:group/Backend"},
+	}
+
+	g.AddPrincipal(parent)
+	for _, policy := range parent.Policies {
+		if err := g.addPolicyEdges(parent.ARN, policy); err != nil {
+			t.Fatalf("Failed to add policy edges for parent group: %v", err)
+		}
+	}
+	g.AddPrincipal(child)
+	g.AddPrincipal(user)
+
+	// User inherits through two levels of membership
+	if !g.CanAccess(user.ARN, "s3:GetObject", "arn:aws:s3:::eng-bucket/file.txt") {
+		t.Error("User should inherit access through nested group membership")
+	}
+
+	// No grant anywhere in the chain for other actions
+	if g.CanAccess(user.ARN, "s3:PutObject", "arn:aws:s3:::eng-bucket/file.txt") {
+		t.Error("User should NOT have access to actions no group grants")
+	}
+}
+
+// TestCanAccess_NestedGroupDeny tests that an explicit deny in a nested group
+// blocks a permission granted directly to the user
+func TestCanAccess_NestedGroupDeny(t *testing.T) {
+	g := New()
+
+	// Nested group carries an explicit deny
+	restricted := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:group/Restricted",
+		Type: types.PrincipalTypeGroup,
+		Name: "Restricted",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectDeny,
+						Action:   []string{"s3:DeleteObject"},
+						Resource: []string{"arn:aws:s3:::prod-data/*"},
+					},
+				},
+			},
+		},
+	}
+
+	contractors := &types.Principal{
+		ARN:              "arn:aws:iam::123456789012:group/Contractors",
+		Type:             types.PrincipalTypeGroup,
+		Name:             "Contractors",
+		GroupMemberships: []string{"arn:aws:iam::123456789012:group/Restricted"},
+	}
+
+	// User has a direct allow that the nested deny must override
+	user := &types.Principal{
+		ARN:              "arn:aws:iam::123456789012:user/ivan",
+		Type:             types.PrincipalTypeUser,
+		Name:             "ivan",
+		GroupMemberships: []string{"arn:aws:iam::123456789012:group/Contractors"},
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   []string{"s3:*"},
+						Resource: []string{"arn:aws:s3:::prod-data/*"},
+					},
+				},
+			},
+		},
+	}
+
+	g.AddPrincipal(restricted)
+	for _, policy := range restricted.Policies {
+		if err := g.addPolicyEdges(restricted.ARN, policy); err != nil {
+			t.Fatalf("Failed to add policy edges for restricted group: %v", err)
+		}
+	}
+	g.AddPrincipal(contractors)
+	g.AddPrincipal(user)
+	for _, policy := range user.Policies {
+		if err := g.addPolicyEdges(user.ARN, policy); err != nil {
+			t.Fatalf("Failed to add policy edges for user: %v", err)
+		}
+	}
+
+	// Deny inherited through two levels wins over the direct allow
+	if g.CanAccess(user.ARN, "s3:DeleteObject", "arn:aws:s3:::prod-data/file.txt") {
+		t.Error("Deny from nested group should override the user's direct allow")
+	}
+
+	// Actions outside the deny still work through the direct allow
+	if !g.CanAccess(user.ARN, "s3:GetObject", "arn:aws:s3:::prod-data/file.txt") {
+		t.Error("User should keep direct access to actions the nested deny does not cover")
+	}
+}
+
+// TestCanAccess_GroupMembershipCycle tests that a membership cycle between
+// groups terminates instead of recursing forever
+func TestCanAccess_GroupMembershipCycle(t *testing.T) {
+	g := New()
+
+	groupA := &types.Principal{
+		ARN:              "arn:aws:iam::123456789012:group/A",
+		Type:             types.PrincipalTypeGroup,
+		Name:             "A",
+		GroupMemberships: []string{"arn:aws:iam::123456789012:group/B"},
+	}
+	groupB := &types.Principal{
+		ARN:              "arn:aws:iam::123456789012:group/B",
+		Type:             types.PrincipalTypeGroup,
+		Name:             "B",
+		GroupMemberships: []string{"arn:aws:iam::123456789012:group/A"},
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   []string{"s3:GetObject"},
+						Resource: []string{"arn:aws:s3:::bucket/*"},
+					},
+				},
+			},
+		},
+	}
+	user := &types.Principal{
+		ARN:              "arn:aws:iam::123456789012:user/judy",
+		Type:             types.PrincipalTypeUser,
+		Name:             "judy",
+		GroupMemberships: []string{"arn:aws:iam::123456789012:group/A"},
+	}
+
+	g.AddPrincipal(groupA)
+	g.AddPrincipal(groupB)
+	for _, policy := range groupB.Policies {
+		if err := g.addPolicyEdges(groupB.ARN, policy); err != nil {
+			t.Fatalf("Failed to add policy edges for group B: %v", err)
+		}
+	}
+	g.AddPrincipal(user)
+
+	// Must terminate and still find the grant held by group B
+	if !g.CanAccess(user.ARN, "s3:GetObject", "arn:aws:s3:::bucket/file.txt") {
+		t.Error("User should inherit access through the cyclic group chain")
+	}
+	if g.CanAccess(user.ARN, "s3:DeleteObject", "arn:aws:s3:::bucket/file.txt") {
+		t.Error("User should NOT gain ungranted actions from the cyclic chain")
+	}
+}
@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"log"
+
+	"github.com/pfrederiksen/aws-access-map/internal/policy"
+	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// isBlockedByRCP checks if a Resource Control Policy blocks the action.
+// RCPs are the resource-side analogue of SCPs and act the same way:
+// 1. Actions must be explicitly allowed by at least one applicable RCP
+// 2. If no RCP allows an action, it is implicitly denied
+// 3. Explicit denies override any allows
+// Unlike SCPs there is no root-user exemption - RCPs constrain every
+// principal that touches the resource, including external ones.
+func (g *Graph) isBlockedByRCP(action, resourceARN string, ctx *conditions.EvaluationContext) bool {
+	// If no RCPs exist, nothing is blocked (from RCP perspective)
+	if len(g.rcps) == 0 {
+		return false
+	}
+
+	// Step 1: Check if action is explicitly allowed by at least one RCP
+	hasExplicitAllow := false
+	for _, rcp := range g.rcps {
+		for _, stmt := range rcp.Statements {
+			if stmt.Effect != types.EffectAllow {
+				continue
+			}
+			if rcpStatementApplies(stmt, action, resourceARN, ctx, rcp.ID, false) {
+				hasExplicitAllow = true
+				break
+			}
+		}
+		if hasExplicitAllow {
+			break
+		}
+	}
+
+	// Step 2: If no explicit allow found, action is implicitly denied
+	if !hasExplicitAllow {
+		return true
+	}
+
+	// Step 3: Check for explicit deny (deny overrides allow)
+	for _, rcp := range g.rcps {
+		for _, stmt := range rcp.Statements {
+			if stmt.Effect != types.EffectDeny {
+				continue
+			}
+			if rcpStatementApplies(stmt, action, resourceARN, ctx, rcp.ID, true) {
+				// RCP explicitly denies this action
+				return true
+			}
+		}
+	}
+
+	// Has explicit allow and no explicit deny
+	return false
+}
+
+// rcpStatementApplies checks whether an RCP statement matches the action and
+// resource, including NotAction/NotResource exclusions and conditions.
+// isDeny controls how condition evaluation errors are handled: denies fail
+// closed (treated as applying), allows fail open (treated as not applying).
+func rcpStatementApplies(stmt types.Statement, action, resourceARN string, ctx *conditions.EvaluationContext, policyID string, isDeny bool) bool {
+	actions := normalizeToSlice(stmt.Action)
+	notActions := normalizeToSlice(stmt.NotAction)
+	resources := normalizeToSlice(stmt.Resource)
+	notResources := normalizeToSlice(stmt.NotResource)
+
+	// Handle precedence: NotAction without Action means apply to all actions except NotAction
+	if len(notActions) > 0 && len(actions) == 0 {
+		actions = []string{"*"}
+	}
+	if len(notResources) > 0 && len(resources) == 0 {
+		resources = []string{"*"}
+	}
+
+	// Check if action matches
+	actionMatches := false
+	for _, rcpAction := range actions {
+		if policy.MatchesAction(rcpAction, action) {
+			actionMatches = true
+			break
+		}
+	}
+	if !actionMatches {
+		return false
+	}
+
+	// Check NotAction exclusion
+	if len(notActions) > 0 {
+		if !policy.MatchesNotAction(notActions, action) {
+			return false // Action is excluded by NotAction
+		}
+	}
+
+	// Check if resource matches
+	resourceMatches := false
+	for _, rcpResource := range resources {
+		if matchesPattern(rcpResource, resourceARN) {
+			resourceMatches = true
+			break
+		}
+	}
+	if !resourceMatches {
+		return false
+	}
+
+	// Check NotResource exclusion
+	if len(notResources) > 0 {
+		if !policy.MatchesNotResource(notResources, resourceARN) {
+			return false // Resource is excluded by NotResource
+		}
+	}
+
+	// Check conditions if present
+	if len(stmt.Condition) > 0 {
+		matched, err := conditions.Evaluate(stmt.Condition, ctx)
+		if err != nil {
+			if isDeny {
+				// Fail closed for RCP deny conditions (security-first)
+				log.Printf("Warning: Failed to evaluate RCP deny condition (policy %s): %v (assuming deny applies)", policyID, err)
+				return true
+			}
+			// Fail closed for allow conditions - if we can't evaluate, skip this allow
+			log.Printf("Warning: Failed to evaluate RCP allow condition (policy %s): %v (skipping this allow)", policyID, err)
+			return false
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// TestIsBlockedByRCP_ExplicitDeny tests an RCP explicitly denying an action
+func TestIsBlockedByRCP_ExplicitDeny(t *testing.T) {
+	g := New()
+	g.rcps = []types.PolicyDocument{
+		{
+			ID:      "rcp-allow-and-deny",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:   types.EffectAllow,
+					Action:   "*",
+					Resource: "*",
+				},
+				{
+					Effect:   types.EffectDeny,
+					Action:   "s3:PutObject",
+					Resource: "arn:aws:s3:::restricted-bucket/*",
+				},
+			},
+		},
+	}
+
+	ctx := conditions.NewDefaultContext()
+
+	if !g.isBlockedByRCP("s3:PutObject", "arn:aws:s3:::restricted-bucket/file.txt", ctx) {
+		t.Error("Expected RCP to block s3:PutObject on the restricted bucket")
+	}
+
+	if g.isBlockedByRCP("s3:PutObject", "arn:aws:s3:::other-bucket/file.txt", ctx) {
+		t.Error("Expected RCP to NOT block s3:PutObject on an unrestricted bucket")
+	}
+}
+
+// TestIsBlockedByRCP_ImplicitDeny tests that actions not allowed by any RCP are blocked
+func TestIsBlockedByRCP_ImplicitDeny(t *testing.T) {
+	g := New()
+	g.rcps = []types.PolicyDocument{
+		{
+			ID:      "rcp-allow-s3-only",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:   types.EffectAllow,
+					Action:   "s3:*",
+					Resource: "*",
+				},
+			},
+		},
+	}
+
+	ctx := conditions.NewDefaultContext()
+
+	if g.isBlockedByRCP("s3:GetObject", "arn:aws:s3:::my-bucket/file.txt", ctx) {
+		t.Error("Expected RCP to NOT block an explicitly allowed action")
+	}
+
+	if !g.isBlockedByRCP("sqs:SendMessage", "arn:aws:sqs:us-east-1:123456789012:queue", ctx) {
+		t.Error("Expected RCP to implicitly deny an action no RCP allows")
+	}
+}
+
+// TestIsBlockedByRCP_NoRCPs tests that accounts without RCPs are unaffected
+func TestIsBlockedByRCP_NoRCPs(t *testing.T) {
+	g := New()
+	ctx := conditions.NewDefaultContext()
+
+	if g.isBlockedByRCP("s3:GetObject", "arn:aws:s3:::my-bucket/file.txt", ctx) {
+		t.Error("Expected no blocking when no RCPs are present")
+	}
+}
+
+// TestCanAccess_RCPDeny tests that an RCP deny overrides an identity allow
+func TestCanAccess_RCPDeny(t *testing.T) {
+	g := New()
+	principalARN := "arn:aws:iam::123456789012:user/alice"
+	resourceARN := "arn:aws:s3:::restricted-bucket/file.txt"
+
+	g.AddEdge(principalARN, "s3:GetObject", "arn:aws:s3:::restricted-bucket/*", false)
+	g.rcps = []types.PolicyDocument{
+		{
+			ID:      "rcp-deny-restricted",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:   types.EffectAllow,
+					Action:   "*",
+					Resource: "*",
+				},
+				{
+					Effect:   types.EffectDeny,
+					Action:   "s3:GetObject",
+					Resource: "arn:aws:s3:::restricted-bucket/*",
+				},
+			},
+		},
+	}
+
+	if g.CanAccess(principalARN, "s3:GetObject", resourceARN) {
+		t.Error("Expected RCP deny to override identity policy allow")
+	}
+}
+
+// TestBuild_FiltersRCPsForAccount tests hierarchy-aware RCP filtering in Build
+func TestBuild_FiltersRCPsForAccount(t *testing.T) {
+	rcpPolicy := types.PolicyDocument{
+		ID:      "rcp-ou-attached",
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{Effect: types.EffectAllow, Action: "*", Resource: "*"},
+		},
+	}
+
+	collection := &types.CollectionResult{
+		AccountID: "123456789012",
+		RCPAttachments: []types.SCPAttachment{
+			{
+				Policy: rcpPolicy,
+				Targets: []types.SCPTarget{
+					{Type: types.SCPTargetTypeOrganizationalUnit, ID: "ou-prod"},
+				},
+			},
+		},
+		OUHierarchy: &types.OUHierarchy{
+			AccountID: "123456789012",
+			ParentOUs: []string{"ou-prod"},
+		},
+	}
+
+	g, err := Build(collection)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	if len(g.rcps) != 1 {
+		t.Errorf("Expected 1 applicable RCP, got %d", len(g.rcps))
+	}
+
+	// An account outside the OU gets no RCPs
+	collection.AccountID = "999999999999"
+	collection.OUHierarchy = &types.OUHierarchy{
+		AccountID: "999999999999",
+		ParentOUs: []string{"ou-dev"},
+	}
+	g, err = Build(collection)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	if len(g.rcps) != 0 {
+		t.Errorf("Expected no applicable RCPs, got %d", len(g.rcps))
+	}
+}
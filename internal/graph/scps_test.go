@@ -1,6 +1,9 @@
 package graph
 
 import (
+	"fmt"
+	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
@@ -81,6 +84,95 @@ func TestIsBlockedBySCP_WildcardDeny(t *testing.T) {
 	}
 }
 
+// TestIsBlockedBySCP_MissingResource tests that SCP statements omitting
+// Resource (a common FullAWSAccess-style shape) are treated as Resource "*"
+func TestIsBlockedBySCP_MissingResource(t *testing.T) {
+	g := New()
+	g.scps = []types.PolicyDocument{
+		{
+			ID:      "scp-no-resource",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect: types.EffectAllow,
+					Action: "*",
+					// No Resource field
+				},
+				{
+					Effect: types.EffectDeny,
+					Action: "organizations:LeaveOrganization",
+					// No Resource field
+				},
+			},
+		},
+	}
+
+	ctx := conditions.NewDefaultContext()
+
+	if g.isBlockedBySCP("arn:aws:iam::123456789012:user/alice", "s3:GetObject", "arn:aws:s3:::my-bucket", ctx) {
+		t.Error("Allow without Resource should permit the action (Resource defaults to *)")
+	}
+
+	if !g.isBlockedBySCP("arn:aws:iam::123456789012:user/alice", "organizations:LeaveOrganization", "*", ctx) {
+		t.Error("Deny without Resource should still block the action (Resource defaults to *)")
+	}
+}
+
+// TestIsBlockedBySCP_RegionGuardrail tests the common region-restriction SCP
+// shape: FullAWSAccess plus a deny that omits Resource, uses NotAction for
+// global services, and is condition-scoped to the requested region
+func TestIsBlockedBySCP_RegionGuardrail(t *testing.T) {
+	g := New()
+	g.scps = []types.PolicyDocument{
+		{
+			ID:      "p-FullAWSAccess",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect: types.EffectAllow,
+					Action: "*",
+					// FullAWSAccess as distributed by AWS has Resource "*",
+					// but hand-written allows frequently omit it
+				},
+			},
+		},
+		{
+			ID:      "p-DenyOutsideEU",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectDeny,
+					NotAction: []interface{}{"iam:*", "sts:*", "organizations:*"},
+					// No Resource: the deny is action/condition scoped
+					Condition: map[string]map[string]interface{}{
+						"StringNotEquals": {
+							"aws:RequestedRegion": "eu-west-1",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outsideEU := conditions.NewDefaultContext()
+	outsideEU.RequestedRegion = "us-east-1"
+
+	if !g.isBlockedBySCP("arn:aws:iam::123456789012:user/alice", "ec2:RunInstances", "*", outsideEU) {
+		t.Error("Region guardrail should block ec2:RunInstances outside eu-west-1")
+	}
+	// Global services are carved out via NotAction
+	if g.isBlockedBySCP("arn:aws:iam::123456789012:user/alice", "iam:CreateUser", "*", outsideEU) {
+		t.Error("Region guardrail should not block iam:CreateUser (NotAction carve-out)")
+	}
+
+	insideEU := conditions.NewDefaultContext()
+	insideEU.RequestedRegion = "eu-west-1"
+
+	if g.isBlockedBySCP("arn:aws:iam::123456789012:user/alice", "ec2:RunInstances", "*", insideEU) {
+		t.Error("Region guardrail should not block ec2:RunInstances in eu-west-1")
+	}
+}
+
 // TestIsBlockedBySCP_NoSCPs tests that empty SCP list doesn't block anything
 func TestIsBlockedBySCP_NoSCPs(t *testing.T) {
 	g := New()
@@ -351,6 +443,75 @@ func TestCanAccess_MultipleSCPs(t *testing.T) {
 	}
 }
 
+// TestBuildMultiAccount_AccountScopedSCPs verifies that in a combined
+// org-wide graph each principal is evaluated under its own account's SCPs
+func TestBuildMultiAccount_AccountScopedSCPs(t *testing.T) {
+	s3Policy := types.PolicyDocument{
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{
+				Effect:   types.EffectAllow,
+				Action:   "s3:GetObject",
+				Resource: "*",
+			},
+		},
+	}
+	denyS3SCP := types.SCPAttachment{
+		Policy: types.PolicyDocument{
+			ID:      "p-deny-s3",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{Effect: types.EffectAllow, Action: "*", Resource: "*"},
+				{Effect: types.EffectDeny, Action: "s3:*", Resource: "*"},
+			},
+		},
+		Targets: []types.SCPTarget{
+			{Type: types.SCPTargetTypeAccount, ID: "111111111111"},
+		},
+	}
+
+	multi := &types.MultiAccountCollectionResult{
+		SCPAttachments: []types.SCPAttachment{denyS3SCP},
+		Accounts: map[string]*types.CollectionResult{
+			"111111111111": {
+				AccountID: "111111111111",
+				Principals: []*types.Principal{
+					{
+						ARN:      "arn:aws:iam::111111111111:user/locked",
+						Type:     types.PrincipalTypeUser,
+						Name:     "locked",
+						Policies: []types.PolicyDocument{s3Policy},
+					},
+				},
+			},
+			"222222222222": {
+				AccountID: "222222222222",
+				Principals: []*types.Principal{
+					{
+						ARN:      "arn:aws:iam::222222222222:user/free",
+						Type:     types.PrincipalTypeUser,
+						Name:     "free",
+						Policies: []types.PolicyDocument{s3Policy},
+					},
+				},
+			},
+		},
+	}
+
+	g, err := BuildMultiAccount(multi)
+	if err != nil {
+		t.Fatalf("BuildMultiAccount() error = %v", err)
+	}
+
+	// The SCP is attached only to account 111111111111
+	if g.CanAccess("arn:aws:iam::111111111111:user/locked", "s3:GetObject", "arn:aws:s3:::data") {
+		t.Error("SCP attached to account 111111111111 should block its principal")
+	}
+	if !g.CanAccess("arn:aws:iam::222222222222:user/free", "s3:GetObject", "arn:aws:s3:::data") {
+		t.Error("SCP for another account should not block account 222222222222")
+	}
+}
+
 // TestIsRootUser tests the root user detection
 func TestIsRootUser(t *testing.T) {
 	tests := []struct {
@@ -725,3 +886,65 @@ func TestFilterSCPsForAccount(t *testing.T) {
 		})
 	}
 }
+
+// TestFilterSCPsForAccountCached verifies the memoized filter returns the
+// same results as the direct filter, distinguishes accounts and changed
+// attachment sets, and tolerates concurrent builds
+func TestFilterSCPsForAccountCached(t *testing.T) {
+	attachments := []types.SCPAttachment{
+		{
+			Policy: types.PolicyDocument{ID: "scp-root", Version: "2012-10-17"},
+			Targets: []types.SCPTarget{
+				{Type: types.SCPTargetTypeRoot, ID: "r-1111"},
+			},
+		},
+		{
+			Policy: types.PolicyDocument{ID: "scp-account", Version: "2012-10-17"},
+			Targets: []types.SCPTarget{
+				{Type: types.SCPTargetTypeAccount, ID: "111111111111"},
+			},
+		},
+	}
+
+	direct := filterSCPsForAccount("111111111111", attachments, nil)
+	cached := filterSCPsForAccountCached("111111111111", attachments, nil)
+	if !reflect.DeepEqual(direct, cached) {
+		t.Errorf("Cached filter diverged from direct filter: %v vs %v", cached, direct)
+	}
+
+	// A second call with identical inputs serves the memoized slice
+	again := filterSCPsForAccountCached("111111111111", attachments, nil)
+	if len(again) != 2 {
+		t.Errorf("Expected 2 SCPs on the cached path, got %d", len(again))
+	}
+
+	// A different account must not reuse the first account's entry
+	other := filterSCPsForAccountCached("222222222222", attachments, nil)
+	if len(other) != 1 || other[0].ID != "scp-root" {
+		t.Errorf("Expected only the root SCP for the other account, got %v", other)
+	}
+
+	// Changing the attachment set changes the key, so no stale results
+	grown := append(attachments, types.SCPAttachment{
+		Policy: types.PolicyDocument{ID: "scp-extra", Version: "2012-10-17"},
+		Targets: []types.SCPTarget{
+			{Type: types.SCPTargetTypeRoot, ID: "r-1111"},
+		},
+	})
+	refreshed := filterSCPsForAccountCached("111111111111", grown, nil)
+	if len(refreshed) != 3 {
+		t.Errorf("Expected 3 SCPs after the attachment set grew, got %d", len(refreshed))
+	}
+
+	// Concurrent lookups for many accounts must be safe
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			accountID := fmt.Sprintf("%012d", n)
+			filterSCPsForAccountCached(accountID, attachments, nil)
+		}(i)
+	}
+	wg.Wait()
+}
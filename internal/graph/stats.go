@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// Stats summarizes the structure of a built access graph: node counts by
+// type, edge totals, and a few signals (public resources, boundary coverage,
+// the most-privileged principal) that indicate whether a collection looks
+// complete. Computed on demand; cheap relative to building the graph.
+type Stats struct {
+	Principals             int            `json:"principals"`
+	PrincipalsByType       map[string]int `json:"principals_by_type"`
+	Resources              int            `json:"resources"`
+	ResourcesByType        map[string]int `json:"resources_by_type"`
+	TrustRelationships     int            `json:"trust_relationships"`
+	PrincipalsWithBoundary int            `json:"principals_with_boundary"`
+	PublicResources        int            `json:"public_resources"`
+	AllowEdges             int            `json:"allow_edges"`
+	DenyEdges              int            `json:"deny_edges"`
+	AvgAllowEdges          float64        `json:"avg_allow_edges_per_principal"`
+	MostPrivilegedARN      string         `json:"most_privileged_principal,omitempty"`
+	MostPrivilegedEdges    int            `json:"most_privileged_allow_edges,omitempty"`
+}
+
+// Stats computes structural metrics for the graph. Public resources are
+// those the anonymous "*" principal holds an allow edge to. The
+// most-privileged principal is the one with the most allow edges, with ties
+// broken by ARN for deterministic output; synthetic public and service
+// principals are excluded from that ranking.
+func (g *Graph) Stats() Stats {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	stats := Stats{
+		Principals:       len(g.principals),
+		PrincipalsByType: make(map[string]int),
+		Resources:        len(g.resources),
+		ResourcesByType:  make(map[string]int),
+	}
+
+	for _, p := range g.principals {
+		stats.PrincipalsByType[string(p.Type)]++
+		if p.PermissionsBoundary != nil {
+			stats.PrincipalsWithBoundary++
+		}
+	}
+
+	for _, r := range g.resources {
+		stats.ResourcesByType[string(r.Type)]++
+	}
+
+	for _, assumers := range g.trustRelations {
+		stats.TrustRelationships += len(assumers)
+	}
+
+	publicTargets := make(map[string]bool)
+	for _, edges := range g.principalActions["*"] {
+		for _, edge := range edges {
+			publicTargets[edge.ResourceARN] = true
+		}
+	}
+	stats.PublicResources = len(publicTargets)
+
+	for principalARN, actions := range g.principalActions {
+		edgeCount := 0
+		for _, edges := range actions {
+			edgeCount += len(edges)
+		}
+		stats.AllowEdges += edgeCount
+
+		p, ok := g.principals[principalARN]
+		if ok && (p.Type == types.PrincipalTypePublic || p.Type == types.PrincipalTypeService) {
+			continue
+		}
+		if edgeCount > stats.MostPrivilegedEdges ||
+			(edgeCount == stats.MostPrivilegedEdges && edgeCount > 0 && principalARN < stats.MostPrivilegedARN) {
+			stats.MostPrivilegedARN = principalARN
+			stats.MostPrivilegedEdges = edgeCount
+		}
+	}
+
+	for _, actions := range g.denies {
+		for _, edges := range actions {
+			stats.DenyEdges += len(edges)
+		}
+	}
+
+	if stats.Principals > 0 {
+		stats.AvgAllowEdges = float64(stats.AllowEdges) / float64(stats.Principals)
+	}
+
+	return stats
+}
@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestStats(t *testing.T) {
+	admin := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/admin",
+		Type:      types.PrincipalTypeUser,
+		Name:      "admin",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "arn:aws:s3:::data/*"},
+					{Effect: types.EffectAllow, Action: "s3:PutObject", Resource: "arn:aws:s3:::data/*"},
+				},
+			},
+		},
+	}
+
+	reader := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/reader",
+		Type:      types.PrincipalTypeUser,
+		Name:      "reader",
+		AccountID: "123456789012",
+		PermissionsBoundary: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{Effect: types.EffectAllow, Action: "s3:*", Resource: "*"},
+			},
+		},
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "arn:aws:s3:::data/*"},
+				},
+			},
+		},
+	}
+
+	role := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:role/app",
+		Type:      types.PrincipalTypeRole,
+		Name:      "app",
+		AccountID: "123456789012",
+		TrustPolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: map[string]interface{}{"AWS": admin.ARN},
+					Action:    "sts:AssumeRole",
+				},
+			},
+		},
+	}
+
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::data/*",
+		Type: types.ResourceTypeS3,
+		Name: "data",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{Effect: types.EffectAllow, Principal: "*", Action: "s3:GetObject", Resource: "arn:aws:s3:::data/*"},
+			},
+		},
+	}
+
+	g, err := Build(&types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{admin, reader, role},
+		Resources:  []*types.Resource{bucket},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	stats := g.Stats()
+
+	// 3 declared principals plus the synthesized public "*" node
+	if stats.Principals != 4 {
+		t.Errorf("Principals = %d, want 4", stats.Principals)
+	}
+	if stats.PrincipalsByType["user"] != 2 || stats.PrincipalsByType["role"] != 1 || stats.PrincipalsByType["public"] != 1 {
+		t.Errorf("PrincipalsByType = %v, want 2 users, 1 role, 1 public", stats.PrincipalsByType)
+	}
+	if stats.Resources != 1 || stats.ResourcesByType["s3"] != 1 {
+		t.Errorf("Resources = %d (%v), want 1 s3 resource", stats.Resources, stats.ResourcesByType)
+	}
+	if stats.TrustRelationships != 1 {
+		t.Errorf("TrustRelationships = %d, want 1", stats.TrustRelationships)
+	}
+	if stats.PrincipalsWithBoundary != 1 {
+		t.Errorf("PrincipalsWithBoundary = %d, want 1", stats.PrincipalsWithBoundary)
+	}
+	if stats.PublicResources != 1 {
+		t.Errorf("PublicResources = %d, want 1", stats.PublicResources)
+	}
+	if stats.AllowEdges == 0 {
+		t.Error("AllowEdges = 0, want > 0")
+	}
+	if stats.AvgAllowEdges <= 0 {
+		t.Errorf("AvgAllowEdges = %f, want > 0", stats.AvgAllowEdges)
+	}
+
+	// admin holds the most allow edges; the public principal is excluded
+	if stats.MostPrivilegedARN != admin.ARN {
+		t.Errorf("MostPrivilegedARN = %q, want %q", stats.MostPrivilegedARN, admin.ARN)
+	}
+	if stats.MostPrivilegedEdges < 2 {
+		t.Errorf("MostPrivilegedEdges = %d, want >= 2", stats.MostPrivilegedEdges)
+	}
+}
+
+func TestStats_EmptyGraph(t *testing.T) {
+	stats := New().Stats()
+
+	if stats.Principals != 0 || stats.Resources != 0 {
+		t.Errorf("empty graph reported %d principals, %d resources", stats.Principals, stats.Resources)
+	}
+	if stats.AvgAllowEdges != 0 {
+		t.Errorf("AvgAllowEdges = %f, want 0", stats.AvgAllowEdges)
+	}
+	if stats.MostPrivilegedARN != "" {
+		t.Errorf("MostPrivilegedARN = %q, want empty", stats.MostPrivilegedARN)
+	}
+}
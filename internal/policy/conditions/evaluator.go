@@ -18,6 +18,8 @@ type EvaluationContext struct {
 	MFAAuthenticated bool   // Whether MFA was used
 	PrincipalARN     string // ARN of the principal making the request
 	PrincipalOrgID   string // Organization ID of the principal
+	PrincipalOrgPath string // Full OU path of the principal's account (aws:PrincipalOrgPaths, e.g. o-abc/r-root/ou-prod/)
+	SourceArn        string // ARN of the calling resource for cross-service requests (aws:SourceArn)
 
 	// Request context
 	SecureTransport bool   // Whether request uses HTTPS
@@ -26,10 +28,20 @@ type EvaluationContext struct {
 	// Tag context
 	PrincipalTags map[string]string // Tags on the principal
 	ResourceTags  map[string]string // Tags on the resource
+	RequestTags   map[string]string // Tags passed in the request (aws:RequestTag/<key>, aws:TagKeys)
 
 	// Numeric context
 	NumericContext map[string]float64 // Numeric values for comparison (e.g., s3:max-keys, ec2:InstanceCount)
 
+	// String context for service-specific keys with no dedicated field
+	// (e.g. s3:prefix, sns:Endpoint)
+	StringContext map[string]string
+
+	// Multi-valued context keys for the ForAllValues:/ForAnyValue: set
+	// operators (e.g. aws:CalledVia, multi-valued service keys). aws:TagKeys
+	// is derived from RequestTags and needs no entry here
+	MultiValueContext map[string][]string
+
 	// Date context
 	CurrentTime time.Time             // Current request time (for date comparisons)
 	DateContext map[string]time.Time // Date values for comparison (e.g., aws:CurrentTime, custom dates)
@@ -47,7 +59,10 @@ func NewDefaultContext() *EvaluationContext {
 		SecureTransport:  true,      // Assume HTTPS
 		PrincipalTags:    make(map[string]string),
 		ResourceTags:     make(map[string]string),
+		RequestTags:      make(map[string]string),
 		NumericContext:   make(map[string]float64),
+		StringContext:    make(map[string]string),
+		MultiValueContext: make(map[string][]string),
 		CurrentTime:      time.Now(), // Default to current time
 		DateContext:      make(map[string]time.Time),
 	}
@@ -83,6 +98,26 @@ func Evaluate(condition map[string]map[string]interface{}, ctx *EvaluationContex
 
 // evaluateOperator evaluates a single condition operator
 func evaluateOperator(operator string, operands map[string]interface{}, ctx *EvaluationContext) (bool, error) {
+	// Any operator can carry an IfExists suffix (StringEqualsIfExists,
+	// ArnLikeIfExists, ...) meaning a key absent from the request context
+	// passes instead of failing closed. BoolIfExists keeps its dedicated
+	// case below since Bool's key-presence rules are modeled separately
+	if base, ok := strings.CutSuffix(operator, "IfExists"); ok && base != "" && operator != "BoolIfExists" {
+		return evaluateIfExists(base, operands, ctx)
+	}
+
+	// ForAllValues:/ForAnyValue: qualifiers apply the base operator across
+	// every value of a multi-valued key (aws:TagKeys, aws:CalledVia, ...)
+	// with set semantics: ForAllValues passes when every context value
+	// matches (vacuously for an absent key), ForAnyValue when at least one
+	// does (failing for an absent key)
+	if base, ok := strings.CutPrefix(operator, "ForAllValues:"); ok {
+		return evaluateSetOperator(base, operands, ctx, true)
+	}
+	if base, ok := strings.CutPrefix(operator, "ForAnyValue:"); ok {
+		return evaluateSetOperator(base, operands, ctx, false)
+	}
+
 	switch operator {
 	// String conditions
 	case "StringEquals":
@@ -91,10 +126,18 @@ func evaluateOperator(operator string, operands map[string]interface{}, ctx *Eva
 		return evaluateStringNotEquals(operands, ctx)
 	case "StringLike":
 		return evaluateStringLike(operands, ctx)
+	case "StringNotLike":
+		return evaluateStringNotLike(operands, ctx)
 
 	// Boolean conditions
 	case "Bool":
 		return evaluateBool(operands, ctx)
+	case "BoolIfExists":
+		return evaluateBoolIfExists(operands, ctx)
+
+	// Key presence conditions
+	case "Null":
+		return evaluateNull(operands, ctx)
 
 	// IP address conditions
 	case "IpAddress":
@@ -146,6 +189,23 @@ func evaluateOperator(operator string, operands map[string]interface{}, ctx *Eva
 	}
 }
 
+// evaluateIfExists implements the IfExists suffix: operands whose key is
+// absent from the context pass outright, and whatever remains must satisfy
+// the base operator
+func evaluateIfExists(base string, operands map[string]interface{}, ctx *EvaluationContext) (bool, error) {
+	present := make(map[string]interface{})
+	for key, value := range operands {
+		if contextKeyPresent(key, ctx) {
+			present[key] = value
+		}
+	}
+
+	if len(present) == 0 {
+		return true, nil
+	}
+	return evaluateOperator(base, present, ctx)
+}
+
 // evaluateStringEquals checks if string values match
 func evaluateStringEquals(operands map[string]interface{}, ctx *EvaluationContext) (bool, error) {
 	for key, expectedValue := range operands {
@@ -160,6 +220,7 @@ func evaluateStringEquals(operands map[string]interface{}, ctx *EvaluationContex
 		if !ok {
 			return false, fmt.Errorf("expected string value for StringEquals, got %T", expectedValue)
 		}
+		expectedStr = expandPolicyVariables(expectedStr, ctx)
 
 		// Case-sensitive comparison
 		if actualValue != expectedStr {
@@ -184,6 +245,7 @@ func evaluateStringNotEquals(operands map[string]interface{}, ctx *EvaluationCon
 		if !ok {
 			return false, fmt.Errorf("expected string value for StringNotEquals, got %T", expectedValue)
 		}
+		expectedStr = expandPolicyVariables(expectedStr, ctx)
 
 		// Case-sensitive comparison - all values must NOT equal
 		if actualValue == expectedStr {
@@ -206,6 +268,7 @@ func evaluateStringLike(operands map[string]interface{}, ctx *EvaluationContext)
 		if !ok {
 			return false, fmt.Errorf("expected string value for StringLike, got %T", expectedValue)
 		}
+		expectedStr = expandPolicyVariables(expectedStr, ctx)
 
 		// Simple wildcard matching (* matches any sequence)
 		if !wildcardMatch(expectedStr, actualValue) {
@@ -216,15 +279,45 @@ func evaluateStringLike(operands map[string]interface{}, ctx *EvaluationContext)
 	return true, nil
 }
 
-// evaluateBool checks boolean conditions
-func evaluateBool(operands map[string]interface{}, ctx *EvaluationContext) (bool, error) {
+// evaluateStringNotLike checks that string does NOT match a pattern
+func evaluateStringNotLike(operands map[string]interface{}, ctx *EvaluationContext) (bool, error) {
 	for key, expectedValue := range operands {
-		actualValue, found := getBoolContextValue(key, ctx)
-		if !found {
-			// Key not found in context - condition fails
+		actualValue := getContextValue(key, ctx)
+		if actualValue == "" {
+			return false, nil
+		}
+
+		expectedStr, ok := expectedValue.(string)
+		if !ok {
+			return false, fmt.Errorf("expected string value for StringNotLike, got %T", expectedValue)
+		}
+		expectedStr = expandPolicyVariables(expectedStr, ctx)
+
+		if wildcardMatch(expectedStr, actualValue) {
 			return false, nil
 		}
+	}
 
+	return true, nil
+}
+
+// evaluateBool checks boolean conditions. An absent key fails the condition,
+// which is why AWS's own docs warn that "Bool": {"aws:MultiFactorAuthPresent":
+// "false"} does NOT match long-term credentials (the key doesn't exist there);
+// BoolIfExists is the operator that catches the absent case.
+func evaluateBool(operands map[string]interface{}, ctx *EvaluationContext) (bool, error) {
+	return evaluateBoolOperands(operands, ctx, false)
+}
+
+// evaluateBoolIfExists is Bool with absent keys passing instead of failing
+func evaluateBoolIfExists(operands map[string]interface{}, ctx *EvaluationContext) (bool, error) {
+	return evaluateBoolOperands(operands, ctx, true)
+}
+
+// evaluateBoolOperands implements Bool/BoolIfExists; missingOK controls how an
+// absent context key is treated
+func evaluateBoolOperands(operands map[string]interface{}, ctx *EvaluationContext, missingOK bool) (bool, error) {
+	for key, expectedValue := range operands {
 		// Handle expected value as string "true"/"false" or bool
 		var expectedBool bool
 		switch v := expectedValue.(type) {
@@ -236,6 +329,15 @@ func evaluateBool(operands map[string]interface{}, ctx *EvaluationContext) (bool
 			return false, fmt.Errorf("expected bool or string value for Bool, got %T", expectedValue)
 		}
 
+		actualValue, found := getBoolContextValue(key, ctx)
+		if !found {
+			if missingOK {
+				continue
+			}
+			// Key not found in context - condition fails
+			return false, nil
+		}
+
 		if actualValue != expectedBool {
 			return false, nil
 		}
@@ -244,6 +346,58 @@ func evaluateBool(operands map[string]interface{}, ctx *EvaluationContext) (bool
 	return true, nil
 }
 
+// evaluateNull checks key presence rather than value: "true" means the key
+// must be absent from the request context, "false" means it must be present.
+// The classic use is requiring MFA via "Null":{"aws:MultiFactorAuthPresent":"false"}
+func evaluateNull(operands map[string]interface{}, ctx *EvaluationContext) (bool, error) {
+	for key, expectedValue := range operands {
+		// Handle expected value as string "true"/"false" or bool
+		var expectNull bool
+		switch v := expectedValue.(type) {
+		case bool:
+			expectNull = v
+		case string:
+			expectNull = strings.ToLower(v) == "true"
+		default:
+			return false, fmt.Errorf("expected bool or string value for Null, got %T", expectedValue)
+		}
+
+		if contextKeyPresent(key, ctx) == expectNull {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// contextKeyPresent reports whether a condition key has a value in the
+// evaluation context. In a real AWS request aws:MultiFactorAuthPresent only
+// exists when temporary credentials were MFA-authenticated, so the key is
+// modeled as present only when MFAAuthenticated is set.
+func contextKeyPresent(key string, ctx *EvaluationContext) bool {
+	switch key {
+	case "aws:MultiFactorAuthPresent":
+		return ctx.MFAAuthenticated
+	case "aws:SecureTransport":
+		return true // Always present in real requests
+	case "aws:CurrentTime", "aws:EpochTime":
+		return !ctx.CurrentTime.IsZero()
+	}
+	if getContextValue(key, ctx) != "" {
+		return true
+	}
+	if _, ok := ctx.NumericContext[key]; ok {
+		return true
+	}
+	if _, ok := ctx.DateContext[key]; ok {
+		return true
+	}
+	if values, ok := ctx.MultiValueContext[key]; ok {
+		return len(values) > 0
+	}
+	return false
+}
+
 // evaluateIPAddress checks if source IP matches CIDR block
 func evaluateIPAddress(operands map[string]interface{}, ctx *EvaluationContext) (bool, error) {
 	for key, expectedValue := range operands {
@@ -310,12 +464,16 @@ func getContextValue(key string, ctx *EvaluationContext) string {
 	switch key {
 	case "aws:PrincipalOrgID":
 		return ctx.PrincipalOrgID
+	case "aws:PrincipalOrgPaths":
+		return ctx.PrincipalOrgPath
 	case "aws:PrincipalArn":
 		return ctx.PrincipalARN
 	case "aws:RequestedRegion":
 		return ctx.RequestedRegion
 	case "aws:SourceIp":
 		return ctx.SourceIP
+	case "aws:PrincipalType":
+		return PrincipalTypeFromARN(ctx.PrincipalARN)
 	default:
 		// Check principal tags
 		if strings.HasPrefix(key, "aws:PrincipalTag/") {
@@ -327,16 +485,268 @@ func getContextValue(key string, ctx *EvaluationContext) string {
 			tagKey := strings.TrimPrefix(key, "aws:ResourceTag/")
 			return ctx.ResourceTags[tagKey]
 		}
+		// Check request tags
+		if strings.HasPrefix(key, "aws:RequestTag/") {
+			tagKey := strings.TrimPrefix(key, "aws:RequestTag/")
+			return ctx.RequestTags[tagKey]
+		}
+		// Service-specific keys (s3:prefix, sns:Endpoint, ...)
+		return ctx.StringContext[key]
+	}
+}
+
+// PrincipalTypeFromARN derives the aws:PrincipalType condition value from a
+// principal ARN. IAM roles are reported as AssumedRole since requests made
+// with a role always carry assumed-role credentials. Returns "" when the
+// type cannot be determined.
+func PrincipalTypeFromARN(arn string) string {
+	switch {
+	case arn == "":
+		return ""
+	case arn == "*":
+		return "Anonymous"
+	case strings.HasSuffix(arn, ":root"):
+		return "Account"
+	case strings.Contains(arn, ":assumed-role/"), strings.Contains(arn, ":role/"):
+		return "AssumedRole"
+	case strings.Contains(arn, ":user/"):
+		return "User"
+	case strings.Contains(arn, ":federated-user/"):
+		return "FederatedUser"
+	default:
 		return ""
 	}
 }
 
+// getMultiValueContext retrieves multi-valued context keys (for set operators)
+// Returns (values, found) where found indicates if the key is a known multi-valued key
+func getMultiValueContext(key string, ctx *EvaluationContext) ([]string, bool) {
+	if values, ok := ctx.MultiValueContext[key]; ok {
+		return values, true
+	}
+
+	switch key {
+	case "aws:TagKeys":
+		// aws:TagKeys is the set of tag keys present in the request
+		keys := make([]string, 0, len(ctx.RequestTags))
+		for tagKey := range ctx.RequestTags {
+			keys = append(keys, tagKey)
+		}
+		return keys, true
+	default:
+		// Fall back to the single-valued context lookup
+		if v := getContextValue(key, ctx); v != "" {
+			return []string{v}, true
+		}
+		return nil, false
+	}
+}
+
+// expandPolicyVariables substitutes ${...} policy variables in a condition
+// value using the evaluation context, so shapes like
+// {"s3:prefix": "${aws:username}/"} compare against the real per-user value.
+// ${*}, ${?} and ${$} are IAM's literal escapes. A variable with no context
+// value expands to "", matching AWS where an unresolvable variable never
+// matches anything.
+func expandPolicyVariables(s string, ctx *EvaluationContext) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			break
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			// Unterminated variable - leave the rest untouched
+			b.WriteString(s)
+			break
+		}
+
+		b.WriteString(s[:start])
+		name := s[start+2 : start+end]
+		switch name {
+		case "*", "?", "$":
+			b.WriteString(name)
+		default:
+			b.WriteString(policyVariableValue(name, ctx))
+		}
+		s = s[start+end+1:]
+	}
+	return b.String()
+}
+
+// policyVariableValue resolves a policy variable name to its context value.
+// IAM treats variable names case-insensitively, so lookups are lowercased.
+func policyVariableValue(name string, ctx *EvaluationContext) string {
+	lower := strings.ToLower(name)
+	switch lower {
+	case "aws:username":
+		return usernameFromARN(ctx.PrincipalARN)
+	case "aws:principalarn":
+		return ctx.PrincipalARN
+	case "aws:principalorgid":
+		return ctx.PrincipalOrgID
+	case "aws:requestedregion":
+		return ctx.RequestedRegion
+	case "aws:sourceip":
+		return ctx.SourceIP
+	case "aws:principaltype":
+		return PrincipalTypeFromARN(ctx.PrincipalARN)
+	}
+	if strings.HasPrefix(lower, "aws:principaltag/") {
+		return ctx.PrincipalTags[name[len("aws:principaltag/"):]]
+	}
+	if strings.HasPrefix(lower, "aws:requesttag/") {
+		return ctx.RequestTags[name[len("aws:requesttag/"):]]
+	}
+	return ""
+}
+
+// usernameFromARN extracts the friendly user name from an IAM user ARN,
+// honoring paths (arn:aws:iam::123:user/division/alice -> alice)
+func usernameFromARN(arn string) string {
+	idx := strings.Index(arn, ":user/")
+	if idx < 0 {
+		return ""
+	}
+	path := arn[idx+len(":user/"):]
+	if slash := strings.LastIndex(path, "/"); slash >= 0 {
+		return path[slash+1:]
+	}
+	return path
+}
+
+// normalizeValues converts a condition operand value (string or []interface{}) to []string
+func normalizeValues(v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []interface{}:
+		result := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	case []string:
+		return val
+	default:
+		return nil
+	}
+}
+
+// evaluateSetOperator implements the ForAllValues:/ForAnyValue: qualifiers
+// for the string base operators. requireAll selects ForAllValues semantics
+// (every context value must match; an absent key passes vacuously) over
+// ForAnyValue (at least one must match; an absent or empty key fails)
+func evaluateSetOperator(base string, operands map[string]interface{}, ctx *EvaluationContext, requireAll bool) (bool, error) {
+	for key, expectedValue := range operands {
+		expectedValues := normalizeValues(expectedValue)
+		if expectedValues == nil {
+			return false, fmt.Errorf("expected string or list value for %s, got %T", base, expectedValue)
+		}
+
+		actualValues, found := getMultiValueContext(key, ctx)
+		if !found {
+			if requireAll {
+				// Key not present in context - ForAllValues passes vacuously
+				continue
+			}
+			return false, nil
+		}
+		if len(actualValues) == 0 && !requireAll {
+			// Empty context value set fails ForAnyValue
+			return false, nil
+		}
+
+		anyMatched := false
+		for _, actual := range actualValues {
+			matched, err := setValueMatches(base, actual, expectedValues, ctx)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				anyMatched = true
+				if !requireAll {
+					break
+				}
+			} else if requireAll {
+				return false, nil
+			}
+		}
+		if !requireAll && !anyMatched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// setValueMatches tests one context value against the expected set under a
+// string base operator. Negated bases succeed only when the value matches
+// none of the expected values
+func setValueMatches(base, actual string, expectedValues []string, ctx *EvaluationContext) (bool, error) {
+	negate := false
+	matchFn := func(expected string) bool { return actual == expected }
+
+	switch base {
+	case "StringEquals":
+	case "StringNotEquals":
+		negate = true
+	case "StringLike":
+		matchFn = func(expected string) bool { return wildcardMatch(expected, actual) }
+	case "StringNotLike":
+		negate = true
+		matchFn = func(expected string) bool { return wildcardMatch(expected, actual) }
+	default:
+		return false, fmt.Errorf("unsupported set operator base: %s", base)
+	}
+
+	for _, expected := range expectedValues {
+		if matchFn(expandPolicyVariables(expected, ctx)) {
+			return !negate, nil
+		}
+	}
+	return negate, nil
+}
+
+// OrgPathFromHierarchy renders the aws:PrincipalOrgPaths value for an account
+// from its collected OU hierarchy: organization ID, then the OUs from the root
+// down to the account's immediate parent, with a trailing slash (AWS's
+// documented format). Returns "" when the org ID or hierarchy is missing,
+// leaving the condition key absent rather than guessing at a partial path
+func OrgPathFromHierarchy(orgID string, hierarchy *types.OUHierarchy) string {
+	if orgID == "" || hierarchy == nil || len(hierarchy.ParentOUs) == 0 {
+		return ""
+	}
+
+	// ParentOUs runs from immediate parent to root; the path reads root-first
+	var b strings.Builder
+	b.WriteString(orgID)
+	for i := len(hierarchy.ParentOUs) - 1; i >= 0; i-- {
+		b.WriteString("/")
+		b.WriteString(hierarchy.ParentOUs[i])
+	}
+	b.WriteString("/")
+	return b.String()
+}
+
 // getBoolContextValue retrieves a boolean value from context by key
 // Returns (value, found) where found indicates if the key exists
 func getBoolContextValue(key string, ctx *EvaluationContext) (bool, bool) {
 	switch key {
 	case "aws:MultiFactorAuthPresent":
-		return ctx.MFAAuthenticated, true
+		// The key only exists in real requests when the session was
+		// MFA-authenticated; long-term credentials omit it entirely, so
+		// without MFA it is absent rather than present-and-false (this is
+		// what makes Bool:false policies miss long-term credentials)
+		return ctx.MFAAuthenticated, ctx.MFAAuthenticated
 	case "aws:SecureTransport":
 		return ctx.SecureTransport, true
 	default:
@@ -735,6 +1145,7 @@ func evaluateArnEquals(operands map[string]interface{}, ctx *EvaluationContext)
 		if !ok {
 			return false, fmt.Errorf("expected string ARN for ArnEquals, got %T", expectedValue)
 		}
+		expectedARN = expandPolicyVariables(expectedARN, ctx)
 
 		// Case-sensitive exact match
 		if actualARN != expectedARN {
@@ -757,6 +1168,7 @@ func evaluateArnLike(operands map[string]interface{}, ctx *EvaluationContext) (b
 		if !ok {
 			return false, fmt.Errorf("expected string pattern for ArnLike, got %T", expectedValue)
 		}
+		expectedPattern = expandPolicyVariables(expectedPattern, ctx)
 
 		// Use wildcard matching for ARN patterns
 		if !wildcardMatch(expectedPattern, actualARN) {
@@ -781,6 +1193,7 @@ func evaluateArnNotEquals(operands map[string]interface{}, ctx *EvaluationContex
 		if !ok {
 			return false, fmt.Errorf("expected string ARN for ArnNotEquals, got %T", expectedValue)
 		}
+		expectedARN = expandPolicyVariables(expectedARN, ctx)
 
 		// All values must NOT equal
 		if actualARN == expectedARN {
@@ -804,6 +1217,7 @@ func evaluateArnNotLike(operands map[string]interface{}, ctx *EvaluationContext)
 		if !ok {
 			return false, fmt.Errorf("expected string pattern for ArnNotLike, got %T", expectedValue)
 		}
+		expectedPattern = expandPolicyVariables(expectedPattern, ctx)
 
 		// All values must NOT match the pattern
 		if wildcardMatch(expectedPattern, actualARN) {
@@ -818,9 +1232,9 @@ func evaluateArnNotLike(operands map[string]interface{}, ctx *EvaluationContext)
 func getARNContextValue(key string, ctx *EvaluationContext) string {
 	switch key {
 	case "aws:SourceArn":
-		// Source ARN would be set in context for cross-service requests
-		// For now, return empty (could be extended)
-		return ""
+		// Set for cross-service requests (e.g. SNS delivering to SQS,
+		// S3 invoking Lambda) via the --source-arn flag
+		return ctx.SourceArn
 	case "aws:PrincipalArn":
 		return ctx.PrincipalARN
 	default:
@@ -3,6 +3,8 @@ package conditions
 import (
 	"testing"
 	"time"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
 )
 
 func TestEvaluate_EmptyCondition(t *testing.T) {
@@ -278,6 +280,91 @@ func TestEvaluateStringLike(t *testing.T) {
 	}
 }
 
+func TestEvaluateStringNotLike(t *testing.T) {
+	tests := []struct {
+		name     string
+		operands map[string]interface{}
+		ctx      *EvaluationContext
+		want     bool
+	}{
+		{
+			name: "Non-matching pattern passes",
+			operands: map[string]interface{}{
+				"aws:PrincipalArn": "arn:aws:iam::*:role/*",
+			},
+			ctx:  &EvaluationContext{PrincipalARN: "arn:aws:iam::123456789012:user/alice"},
+			want: true,
+		},
+		{
+			name: "Matching pattern fails",
+			operands: map[string]interface{}{
+				"aws:PrincipalArn": "arn:aws:iam::*:user/*",
+			},
+			ctx:  &EvaluationContext{PrincipalARN: "arn:aws:iam::123456789012:user/alice"},
+			want: false,
+		},
+		{
+			name: "Matching principal tag fails",
+			operands: map[string]interface{}{
+				"aws:PrincipalTag/team": "pay*",
+			},
+			ctx: &EvaluationContext{
+				PrincipalTags: map[string]string{"team": "payments"},
+			},
+			want: false,
+		},
+		{
+			name: "Absent key fails closed",
+			operands: map[string]interface{}{
+				"aws:PrincipalTag/team": "pay*",
+			},
+			ctx:  &EvaluationContext{PrincipalTags: map[string]string{}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluateStringNotLike(tt.operands, tt.ctx)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("evaluateStringNotLike() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_StringNotLike(t *testing.T) {
+	conditions := map[string]map[string]interface{}{
+		"StringNotLike": {
+			"aws:PrincipalTag/team": "contractor-*",
+		},
+	}
+
+	ctx := &EvaluationContext{
+		PrincipalTags: map[string]string{"team": "payments"},
+	}
+	result, err := Evaluate(conditions, ctx)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if !result {
+		t.Error("Expected non-matching tag to satisfy StringNotLike")
+	}
+
+	ctx.PrincipalTags["team"] = "contractor-east"
+	result, err = Evaluate(conditions, ctx)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result {
+		t.Error("Expected matching tag to fail StringNotLike")
+	}
+}
+
 func TestEvaluateBool(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -303,12 +390,14 @@ func TestEvaluateBool(t *testing.T) {
 			want: true,
 		},
 		{
-			name: "MFA false - match",
+			// Without MFA the key is absent (long-term credentials), so a
+			// plain Bool:false test fails - BoolIfExists is needed to catch it
+			name: "MFA false - key absent without MFA",
 			operands: map[string]interface{}{
 				"aws:MultiFactorAuthPresent": false,
 			},
 			ctx:  &EvaluationContext{MFAAuthenticated: false},
-			want: true,
+			want: false,
 		},
 		{
 			name: "MFA mismatch",
@@ -1283,20 +1372,840 @@ func TestBool_UnknownKey(t *testing.T) {
 	}
 }
 
-// TestBool_KnownKeyMatchingFalse verifies correct behavior for known keys with false value
-func TestBool_KnownKeyMatchingFalse(t *testing.T) {
+// TestBool_MFAKeyAbsentWithoutMFA verifies the MFA key is modeled as absent
+// for non-MFA requests, matching how AWS omits it for long-term credentials
+func TestBool_MFAKeyAbsentWithoutMFA(t *testing.T) {
 	operands := map[string]interface{}{
 		"aws:MultiFactorAuthPresent": false,
 	}
 	ctx := &EvaluationContext{
-		MFAAuthenticated: false, // Known key with false value
+		MFAAuthenticated: false, // Key absent, not present-and-false
 	}
 
 	got, err := evaluateBool(operands, ctx)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
-	if !got {
-		t.Error("Bool should pass when known key matches false value, got false")
+	if got {
+		t.Error("Bool should fail when the MFA key is absent, got true")
+	}
+}
+
+func TestEvaluateBoolIfExists(t *testing.T) {
+	tests := []struct {
+		name     string
+		operands map[string]interface{}
+		ctx      *EvaluationContext
+		want     bool
+		wantErr  bool
+	}{
+		{
+			// The deny-unless-MFA idiom: matches both present-and-false and
+			// the absent long-term-credential case
+			name: "MFA false - key absent matches",
+			operands: map[string]interface{}{
+				"aws:MultiFactorAuthPresent": "false",
+			},
+			ctx:  &EvaluationContext{MFAAuthenticated: false},
+			want: true,
+		},
+		{
+			name: "MFA false - MFA used does not match",
+			operands: map[string]interface{}{
+				"aws:MultiFactorAuthPresent": "false",
+			},
+			ctx:  &EvaluationContext{MFAAuthenticated: true},
+			want: false,
+		},
+		{
+			name: "present key still compared",
+			operands: map[string]interface{}{
+				"aws:SecureTransport": "true",
+			},
+			ctx:  &EvaluationContext{SecureTransport: false},
+			want: false,
+		},
+		{
+			name: "unknown key passes",
+			operands: map[string]interface{}{
+				"aws:ViaAWSService": "false",
+			},
+			ctx:  &EvaluationContext{},
+			want: true,
+		},
+		{
+			name: "invalid operand type",
+			operands: map[string]interface{}{
+				"aws:MultiFactorAuthPresent": 123,
+			},
+			ctx:     &EvaluationContext{},
+			want:    false,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluateBoolIfExists(tt.operands, tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("evaluateBoolIfExists() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("evaluateBoolIfExists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateStringEquals_RequestTag(t *testing.T) {
+	ctx := NewDefaultContext()
+	ctx.RequestTags["CostCenter"] = "12345"
+
+	condition := map[string]map[string]interface{}{
+		"StringEquals": {
+			"aws:RequestTag/CostCenter": "12345",
+		},
+	}
+
+	result, err := Evaluate(condition, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result {
+		t.Error("matching request tag should pass")
+	}
+
+	// Missing tag should fail
+	condition = map[string]map[string]interface{}{
+		"StringEquals": {
+			"aws:RequestTag/Team": "payments",
+		},
+	}
+
+	result, err = Evaluate(condition, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result {
+		t.Error("missing request tag should fail")
+	}
+}
+
+func TestEvaluateForAllValues_TagKeys(t *testing.T) {
+	ctx := NewDefaultContext()
+	ctx.RequestTags["CostCenter"] = "12345"
+	ctx.RequestTags["Team"] = "payments"
+
+	// All request tag keys are in the allowed set
+	condition := map[string]map[string]interface{}{
+		"ForAllValues:StringEquals": {
+			"aws:TagKeys": []interface{}{"CostCenter", "Team", "Environment"},
+		},
+	}
+
+	result, err := Evaluate(condition, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result {
+		t.Error("all tag keys in allowed set should pass")
+	}
+
+	// A tag key outside the allowed set fails
+	condition = map[string]map[string]interface{}{
+		"ForAllValues:StringEquals": {
+			"aws:TagKeys": []interface{}{"CostCenter"},
+		},
+	}
+
+	result, err = Evaluate(condition, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result {
+		t.Error("tag key outside allowed set should fail")
+	}
+}
+
+func TestEvaluateForAllValues_EmptyContextPasses(t *testing.T) {
+	ctx := NewDefaultContext()
+
+	// No request tags: ForAllValues passes vacuously
+	condition := map[string]map[string]interface{}{
+		"ForAllValues:StringEquals": {
+			"aws:TagKeys": []interface{}{"CostCenter"},
+		},
+	}
+
+	result, err := Evaluate(condition, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result {
+		t.Error("empty context value set should pass ForAllValues")
+	}
+}
+
+func TestEvaluateForAnyValue_TagKeys(t *testing.T) {
+	ctx := NewDefaultContext()
+	ctx.RequestTags["Team"] = "payments"
+
+	condition := map[string]map[string]interface{}{
+		"ForAnyValue:StringEquals": {
+			"aws:TagKeys": []interface{}{"CostCenter", "Team"},
+		},
+	}
+
+	result, err := Evaluate(condition, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result {
+		t.Error("at least one matching tag key should pass ForAnyValue")
+	}
+
+	// No request tags: ForAnyValue fails
+	ctx = NewDefaultContext()
+	result, err = Evaluate(condition, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result {
+		t.Error("empty context value set should fail ForAnyValue")
+	}
+}
+
+func TestEvaluateNull(t *testing.T) {
+	tests := []struct {
+		name     string
+		operands map[string]interface{}
+		ctx      *EvaluationContext
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name: "require MFA key present - MFA used",
+			operands: map[string]interface{}{
+				"aws:MultiFactorAuthPresent": "false",
+			},
+			ctx:  &EvaluationContext{MFAAuthenticated: true},
+			want: true,
+		},
+		{
+			name: "require MFA key present - no MFA",
+			operands: map[string]interface{}{
+				"aws:MultiFactorAuthPresent": "false",
+			},
+			ctx:  &EvaluationContext{MFAAuthenticated: false},
+			want: false,
+		},
+		{
+			name: "require MFA key absent - no MFA",
+			operands: map[string]interface{}{
+				"aws:MultiFactorAuthPresent": "true",
+			},
+			ctx:  &EvaluationContext{MFAAuthenticated: false},
+			want: true,
+		},
+		{
+			name: "require MFA key absent - MFA used",
+			operands: map[string]interface{}{
+				"aws:MultiFactorAuthPresent": "true",
+			},
+			ctx:  &EvaluationContext{MFAAuthenticated: true},
+			want: false,
+		},
+		{
+			name: "bool operand value",
+			operands: map[string]interface{}{
+				"aws:MultiFactorAuthPresent": false,
+			},
+			ctx:  &EvaluationContext{MFAAuthenticated: true},
+			want: true,
+		},
+		{
+			name: "principal tag present",
+			operands: map[string]interface{}{
+				"aws:PrincipalTag/Team": "false",
+			},
+			ctx: &EvaluationContext{
+				PrincipalTags: map[string]string{"Team": "payments"},
+			},
+			want: true,
+		},
+		{
+			name: "principal tag absent",
+			operands: map[string]interface{}{
+				"aws:PrincipalTag/Team": "false",
+			},
+			ctx:  &EvaluationContext{PrincipalTags: map[string]string{}},
+			want: false,
+		},
+		{
+			name: "SecureTransport always present",
+			operands: map[string]interface{}{
+				"aws:SecureTransport": "true",
+			},
+			ctx:  &EvaluationContext{},
+			want: false,
+		},
+		{
+			name: "invalid operand type",
+			operands: map[string]interface{}{
+				"aws:MultiFactorAuthPresent": 123,
+			},
+			ctx:     &EvaluationContext{},
+			want:    false,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluateNull(tt.operands, tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("evaluateNull() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("evaluateNull() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEvaluate_NullRequireMFADeny covers the classic deny-without-MFA
+// statement: the deny condition matches (so the deny applies) exactly when
+// the MFA key is absent
+func TestEvaluate_NullRequireMFADeny(t *testing.T) {
+	condition := map[string]map[string]interface{}{
+		"Null": {
+			"aws:MultiFactorAuthPresent": "true",
+		},
+	}
+
+	noMFA := NewDefaultContext()
+	matched, err := Evaluate(condition, noMFA)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if !matched {
+		t.Error("Expected deny condition to match when MFA key is absent")
+	}
+
+	withMFA := NewDefaultContext()
+	withMFA.MFAAuthenticated = true
+	matched, err = Evaluate(condition, withMFA)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if matched {
+		t.Error("Expected deny condition to NOT match when MFA was used")
+	}
+}
+
+func TestPrincipalTypeFromARN(t *testing.T) {
+	cases := map[string]string{
+		"":  "",
+		"*": "Anonymous",
+		"arn:aws:iam::123456789012:root":                     "Account",
+		"arn:aws:iam::123456789012:user/alice":               "User",
+		"arn:aws:iam::123456789012:role/app":                 "AssumedRole",
+		"arn:aws:sts::123456789012:assumed-role/app/session": "AssumedRole",
+		"arn:aws:sts::123456789012:federated-user/bob":       "FederatedUser",
+		"not-an-arn": "",
+	}
+	for arn, want := range cases {
+		if got := PrincipalTypeFromARN(arn); got != want {
+			t.Errorf("PrincipalTypeFromARN(%q) = %q, want %q", arn, got, want)
+		}
+	}
+}
+
+// TestEvaluate_PrincipalType covers resource policies restricted to
+// assumed-role principals
+func TestEvaluate_PrincipalType(t *testing.T) {
+	condition := map[string]map[string]interface{}{
+		"StringEquals": {
+			"aws:PrincipalType": "AssumedRole",
+		},
+	}
+
+	roleCtx := NewDefaultContext()
+	roleCtx.PrincipalARN = "arn:aws:iam::123456789012:role/app"
+	matched, err := Evaluate(condition, roleCtx)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if !matched {
+		t.Error("Expected AssumedRole restriction to match a role principal")
+	}
+
+	userCtx := NewDefaultContext()
+	userCtx.PrincipalARN = "arn:aws:iam::123456789012:user/alice"
+	matched, err = Evaluate(condition, userCtx)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if matched {
+		t.Error("Expected AssumedRole restriction to NOT match an IAM user")
+	}
+}
+
+func TestExpandPolicyVariables(t *testing.T) {
+	ctx := &EvaluationContext{
+		PrincipalARN:  "arn:aws:iam::123456789012:user/division/alice",
+		PrincipalOrgID: "o-123456",
+		PrincipalTags: map[string]string{"team": "platform"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "no variables passes through",
+			input: "home/static/",
+			want:  "home/static/",
+		},
+		{
+			name:  "username from user ARN with path",
+			input: "${aws:username}/",
+			want:  "alice/",
+		},
+		{
+			name:  "variable name is case-insensitive",
+			input: "${aws:Username}/",
+			want:  "alice/",
+		},
+		{
+			name:  "principal tag variable",
+			input: "team-${aws:PrincipalTag/team}",
+			want:  "team-platform",
+		},
+		{
+			name:  "literal wildcard escape",
+			input: "${*}-suffix",
+			want:  "*-suffix",
+		},
+		{
+			name:  "unknown variable expands to empty",
+			input: "${aws:nonexistent}/data",
+			want:  "/data",
+		},
+		{
+			name:  "unterminated variable left untouched",
+			input: "${aws:username/",
+			want:  "${aws:username/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandPolicyVariables(tt.input, ctx); got != tt.want {
+				t.Errorf("expandPolicyVariables(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_PerUserS3Prefix(t *testing.T) {
+	// The classic per-user home directory condition:
+	// without variable expansion this shape never matches
+	condition := map[string]map[string]interface{}{
+		"StringLike": {
+			"s3:prefix": "${aws:username}/*",
+		},
+	}
+
+	alice := &EvaluationContext{
+		PrincipalARN:  "arn:aws:iam::123456789012:user/alice",
+		StringContext: map[string]string{"s3:prefix": "alice/reports/2024.csv"},
+	}
+	matched, err := Evaluate(condition, alice)
+	if err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if !matched {
+		t.Error("Expected alice listing her own prefix to match")
+	}
+
+	// alice reaching into bob's prefix does not match
+	intruder := &EvaluationContext{
+		PrincipalARN:  "arn:aws:iam::123456789012:user/alice",
+		StringContext: map[string]string{"s3:prefix": "bob/reports/2024.csv"},
+	}
+	matched, err = Evaluate(condition, intruder)
+	if err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if matched {
+		t.Error("Expected alice listing bob's prefix to not match")
+	}
+}
+
+func TestEvaluateStringEquals_PolicyVariable(t *testing.T) {
+	condition := map[string]map[string]interface{}{
+		"StringEquals": {
+			"aws:PrincipalArn": "arn:aws:iam::123456789012:user/${aws:username}",
+		},
+	}
+	ctx := &EvaluationContext{
+		PrincipalARN: "arn:aws:iam::123456789012:user/carol",
+	}
+
+	matched, err := Evaluate(condition, ctx)
+	if err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if !matched {
+		t.Error("Expected variable-expanded ARN to match the principal")
+	}
+}
+
+func TestEvaluate_MFAAgeCondition(t *testing.T) {
+	// Common session-freshness guard: MFA must have happened recently
+	condition := map[string]map[string]interface{}{
+		"NumericLessThan": {
+			"aws:MultiFactorAuthAge": "3600",
+		},
+	}
+
+	fresh := &EvaluationContext{
+		MFAAuthenticated: true,
+		NumericContext:   map[string]float64{"aws:MultiFactorAuthAge": 300},
+	}
+	matched, err := Evaluate(condition, fresh)
+	if err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if !matched {
+		t.Error("Expected fresh MFA session to pass the age condition")
+	}
+
+	stale := &EvaluationContext{
+		MFAAuthenticated: true,
+		NumericContext:   map[string]float64{"aws:MultiFactorAuthAge": 7200},
+	}
+	matched, err = Evaluate(condition, stale)
+	if err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if matched {
+		t.Error("Expected stale MFA session to fail the age condition")
+	}
+
+	// No MFA: the key is absent, so the condition fails closed rather than
+	// matching
+	noMFA := &EvaluationContext{
+		NumericContext: make(map[string]float64),
+	}
+	matched, err = Evaluate(condition, noMFA)
+	if err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if matched {
+		t.Error("Expected missing MFA age key to fail the condition")
+	}
+}
+
+func TestEvaluatePrincipalOrgPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition map[string]map[string]interface{}
+		ctx       *EvaluationContext
+		want      bool
+	}{
+		{
+			name: "ForAnyValue StringLike wildcard path matches nested OU",
+			condition: map[string]map[string]interface{}{
+				"ForAnyValue:StringLike": {
+					"aws:PrincipalOrgPaths": "o-abc/r-root/ou-prod/*",
+				},
+			},
+			ctx:  &EvaluationContext{PrincipalOrgPath: "o-abc/r-root/ou-prod/ou-payments/"},
+			want: true,
+		},
+		{
+			name: "ForAnyValue StringLike rejects a different OU branch",
+			condition: map[string]map[string]interface{}{
+				"ForAnyValue:StringLike": {
+					"aws:PrincipalOrgPaths": "o-abc/r-root/ou-prod/*",
+				},
+			},
+			ctx:  &EvaluationContext{PrincipalOrgPath: "o-abc/r-root/ou-sandbox/"},
+			want: false,
+		},
+		{
+			name: "ForAnyValue StringLike with list of patterns",
+			condition: map[string]map[string]interface{}{
+				"ForAnyValue:StringLike": {
+					"aws:PrincipalOrgPaths": []interface{}{
+						"o-abc/r-root/ou-staging/*",
+						"o-abc/r-root/ou-prod/*",
+					},
+				},
+			},
+			ctx:  &EvaluationContext{PrincipalOrgPath: "o-abc/r-root/ou-prod/"},
+			want: true,
+		},
+		{
+			name: "StringLike works on the single-valued path",
+			condition: map[string]map[string]interface{}{
+				"StringLike": {
+					"aws:PrincipalOrgPaths": "o-abc/r-root/ou-prod/*",
+				},
+			},
+			ctx:  &EvaluationContext{PrincipalOrgPath: "o-abc/r-root/ou-prod/ou-payments/"},
+			want: true,
+		},
+		{
+			name: "Missing path fails the condition",
+			condition: map[string]map[string]interface{}{
+				"ForAnyValue:StringLike": {
+					"aws:PrincipalOrgPaths": "o-abc/r-root/ou-prod/*",
+				},
+			},
+			ctx:  &EvaluationContext{},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Evaluate(tt.condition, tt.ctx)
+			if err != nil {
+				t.Fatalf("Evaluate() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrgPathFromHierarchy(t *testing.T) {
+	hierarchy := &types.OUHierarchy{
+		AccountID: "123456789012",
+		ParentOUs: []string{"ou-prod", "r-root"},
+	}
+
+	got := OrgPathFromHierarchy("o-abc", hierarchy)
+	want := "o-abc/r-root/ou-prod/"
+	if got != want {
+		t.Errorf("OrgPathFromHierarchy() = %q, want %q", got, want)
+	}
+
+	if got := OrgPathFromHierarchy("", hierarchy); got != "" {
+		t.Errorf("OrgPathFromHierarchy() without org ID = %q, want empty", got)
+	}
+	if got := OrgPathFromHierarchy("o-abc", nil); got != "" {
+		t.Errorf("OrgPathFromHierarchy() without hierarchy = %q, want empty", got)
+	}
+}
+
+func TestEvaluateIfExists(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition map[string]map[string]interface{}
+		ctx       *EvaluationContext
+		want      bool
+	}{
+		{
+			name: "StringEqualsIfExists passes when source IP is absent",
+			condition: map[string]map[string]interface{}{
+				"StringEqualsIfExists": {
+					"aws:SourceIp": "203.0.113.10",
+				},
+			},
+			ctx:  &EvaluationContext{PrincipalARN: "arn:aws:iam::123456789012:user/alice"},
+			want: true,
+		},
+		{
+			name: "StringEqualsIfExists still fails when present key mismatches",
+			condition: map[string]map[string]interface{}{
+				"StringEqualsIfExists": {
+					"aws:SourceIp": "203.0.113.10",
+				},
+			},
+			ctx:  &EvaluationContext{SourceIP: "198.51.100.7"},
+			want: false,
+		},
+		{
+			name: "StringEqualsIfExists passes when present key matches",
+			condition: map[string]map[string]interface{}{
+				"StringEqualsIfExists": {
+					"aws:RequestedRegion": "us-east-1",
+				},
+			},
+			ctx:  &EvaluationContext{RequestedRegion: "us-east-1"},
+			want: true,
+		},
+		{
+			name: "mixed absent and present keys only evaluate the present one",
+			condition: map[string]map[string]interface{}{
+				"StringEqualsIfExists": {
+					"aws:RequestedRegion": "us-east-1",
+					"aws:SourceIp":        "203.0.113.10",
+				},
+			},
+			ctx:  &EvaluationContext{RequestedRegion: "us-east-1"},
+			want: true,
+		},
+		{
+			name: "IpAddressIfExists passes without a source IP",
+			condition: map[string]map[string]interface{}{
+				"IpAddressIfExists": {
+					"aws:SourceIp": "10.0.0.0/8",
+				},
+			},
+			ctx:  &EvaluationContext{},
+			want: true,
+		},
+		{
+			name: "NumericLessThanIfExists passes when numeric key is absent",
+			condition: map[string]map[string]interface{}{
+				"NumericLessThanIfExists": {
+					"s3:max-keys": "100",
+				},
+			},
+			ctx:  &EvaluationContext{},
+			want: true,
+		},
+		{
+			name: "ArnLikeIfExists fails when present ARN does not match",
+			condition: map[string]map[string]interface{}{
+				"ArnLikeIfExists": {
+					"aws:PrincipalArn": "arn:aws:iam::123456789012:role/app-*",
+				},
+			},
+			ctx:  &EvaluationContext{PrincipalARN: "arn:aws:iam::123456789012:user/alice"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Evaluate(tt.condition, tt.ctx)
+			if err != nil {
+				t.Fatalf("Evaluate() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetOperatorQualifiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition map[string]map[string]interface{}
+		setup     func(ctx *EvaluationContext)
+		want      bool
+	}{
+		{
+			name: "ForAllValues StringLike passes when every tag key matches a pattern",
+			condition: map[string]map[string]interface{}{
+				"ForAllValues:StringLike": {
+					"aws:TagKeys": []interface{}{"project-*", "env"},
+				},
+			},
+			setup: func(ctx *EvaluationContext) {
+				ctx.RequestTags["project-alpha"] = "x"
+				ctx.RequestTags["env"] = "prod"
+			},
+			want: true,
+		},
+		{
+			name: "ForAllValues StringLike fails on an unmatched tag key",
+			condition: map[string]map[string]interface{}{
+				"ForAllValues:StringLike": {
+					"aws:TagKeys": "project-*",
+				},
+			},
+			setup: func(ctx *EvaluationContext) {
+				ctx.RequestTags["project-alpha"] = "x"
+				ctx.RequestTags["owner"] = "alice"
+			},
+			want: false,
+		},
+		{
+			name: "ForAnyValue StringNotEquals passes when a value falls outside the set",
+			condition: map[string]map[string]interface{}{
+				"ForAnyValue:StringNotEquals": {
+					"aws:CalledVia": "cloudformation.amazonaws.com",
+				},
+			},
+			setup: func(ctx *EvaluationContext) {
+				ctx.MultiValueContext["aws:CalledVia"] = []string{"athena.amazonaws.com"}
+			},
+			want: true,
+		},
+		{
+			name: "ForAllValues StringEquals reads the multi-valued context",
+			condition: map[string]map[string]interface{}{
+				"ForAllValues:StringEquals": {
+					"aws:CalledVia": []interface{}{"athena.amazonaws.com", "cloudformation.amazonaws.com"},
+				},
+			},
+			setup: func(ctx *EvaluationContext) {
+				ctx.MultiValueContext["aws:CalledVia"] = []string{"athena.amazonaws.com"}
+			},
+			want: true,
+		},
+		{
+			name: "ForAllValues StringEquals fails when a context value is outside the set",
+			condition: map[string]map[string]interface{}{
+				"ForAllValues:StringEquals": {
+					"aws:CalledVia": "athena.amazonaws.com",
+				},
+			},
+			setup: func(ctx *EvaluationContext) {
+				ctx.MultiValueContext["aws:CalledVia"] = []string{"athena.amazonaws.com", "dynamodb.amazonaws.com"}
+			},
+			want: false,
+		},
+		{
+			name: "ForAnyValue StringEquals fails on an absent multi-valued key",
+			condition: map[string]map[string]interface{}{
+				"ForAnyValue:StringEquals": {
+					"aws:CalledVia": "athena.amazonaws.com",
+				},
+			},
+			setup: func(ctx *EvaluationContext) {},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewDefaultContext()
+			tt.setup(ctx)
+
+			got, err := Evaluate(tt.condition, ctx)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetOperatorUnsupportedBase(t *testing.T) {
+	condition := map[string]map[string]interface{}{
+		"ForAnyValue:NumericEquals": {
+			"aws:CalledVia": "1",
+		},
+	}
+	ctx := NewDefaultContext()
+	ctx.MultiValueContext["aws:CalledVia"] = []string{"1"}
+
+	if _, err := Evaluate(condition, ctx); err == nil {
+		t.Error("expected error for unsupported set operator base")
 	}
 }
@@ -0,0 +1,110 @@
+package conditions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Pinned reports whether every condition key referenced by a condition block
+// has an explicitly supplied value in the context, as opposed to relying on
+// the permissive defaults from NewDefaultContext. A grant whose conditions
+// only pass because of those defaults MAY apply, not WILL.
+func Pinned(condition map[string]map[string]interface{}, ctx *EvaluationContext) bool {
+	if ctx == nil {
+		return false
+	}
+
+	for _, operands := range condition {
+		for key := range operands {
+			if !keyPinned(key, ctx) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// keyPinned reports whether a single condition key carries a real value in
+// the context rather than a permissive default
+func keyPinned(key string, ctx *EvaluationContext) bool {
+	switch key {
+	case "aws:SourceIp":
+		// "0.0.0.0" is the permissive-default sentinel, not a real source IP
+		return ctx.SourceIP != "" && ctx.SourceIP != "0.0.0.0"
+	case "aws:PrincipalArn", "aws:PrincipalType":
+		return ctx.PrincipalARN != ""
+	case "aws:PrincipalOrgID":
+		return ctx.PrincipalOrgID != ""
+	case "aws:PrincipalOrgPaths":
+		return ctx.PrincipalOrgPath != ""
+	case "aws:RequestedRegion":
+		return ctx.RequestedRegion != ""
+	case "aws:MultiFactorAuthPresent":
+		// false is the default, so only an asserted MFA session counts
+		return ctx.MFAAuthenticated
+	case "aws:SecureTransport":
+		// Always assumed true by the default context, never user-supplied
+		return false
+	case "aws:TagKeys":
+		return len(ctx.RequestTags) > 0
+	case "aws:CurrentTime", "aws:EpochTime":
+		return !ctx.CurrentTime.IsZero()
+	default:
+		if strings.HasPrefix(key, "aws:PrincipalTag/") {
+			_, ok := ctx.PrincipalTags[strings.TrimPrefix(key, "aws:PrincipalTag/")]
+			return ok
+		}
+		if strings.HasPrefix(key, "aws:ResourceTag/") {
+			_, ok := ctx.ResourceTags[strings.TrimPrefix(key, "aws:ResourceTag/")]
+			return ok
+		}
+		if strings.HasPrefix(key, "aws:RequestTag/") {
+			_, ok := ctx.RequestTags[strings.TrimPrefix(key, "aws:RequestTag/")]
+			return ok
+		}
+		if _, ok := ctx.NumericContext[key]; ok {
+			return true
+		}
+		if _, ok := ctx.DateContext[key]; ok {
+			return true
+		}
+		return false
+	}
+}
+
+// Describe renders a condition block as human-readable strings, one per
+// operator/key pair, in deterministic order (for display and stable output)
+func Describe(condition map[string]map[string]interface{}) []string {
+	operators := make([]string, 0, len(condition))
+	for operator := range condition {
+		operators = append(operators, operator)
+	}
+	sort.Strings(operators)
+
+	var result []string
+	for _, operator := range operators {
+		operands := condition[operator]
+
+		keys := make([]string, 0, len(operands))
+		for key := range operands {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			result = append(result, fmt.Sprintf("%s %s = %s", operator, key, formatConditionValue(operands[key])))
+		}
+	}
+
+	return result
+}
+
+// formatConditionValue formats a condition operand value (string, list, bool, ...)
+func formatConditionValue(v interface{}) string {
+	if values := normalizeValues(v); len(values) > 0 {
+		return strings.Join(values, ", ")
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,78 @@
+package conditions
+
+import (
+	"testing"
+)
+
+func TestPinned(t *testing.T) {
+	ipCondition := map[string]map[string]interface{}{
+		"IpAddress": {"aws:SourceIp": "203.0.113.0/24"},
+	}
+
+	// Default context carries the permissive sentinel, not a real IP
+	if Pinned(ipCondition, NewDefaultContext()) {
+		t.Error("IP condition should not be pinned by the default context")
+	}
+
+	ctx := NewDefaultContext()
+	ctx.SourceIP = "203.0.113.50"
+	if !Pinned(ipCondition, ctx) {
+		t.Error("IP condition should be pinned when a source IP is supplied")
+	}
+
+	// nil context pins nothing
+	if Pinned(ipCondition, nil) {
+		t.Error("No condition should be pinned by a nil context")
+	}
+}
+
+func TestPinned_MixedKeys(t *testing.T) {
+	condition := map[string]map[string]interface{}{
+		"StringEquals": {"aws:PrincipalOrgID": "o-example123"},
+		"Bool":         {"aws:MultiFactorAuthPresent": "true"},
+	}
+
+	ctx := NewDefaultContext()
+	ctx.PrincipalOrgID = "o-example123"
+	if Pinned(condition, ctx) {
+		t.Error("Condition should not be pinned while MFA is still the default")
+	}
+
+	ctx.MFAAuthenticated = true
+	if !Pinned(condition, ctx) {
+		t.Error("Condition should be pinned once all keys have supplied values")
+	}
+}
+
+func TestPinned_SecureTransportNeverPinned(t *testing.T) {
+	condition := map[string]map[string]interface{}{
+		"Bool": {"aws:SecureTransport": "true"},
+	}
+
+	// Secure transport is always an assumption, never a supplied value
+	if Pinned(condition, NewDefaultContext()) {
+		t.Error("aws:SecureTransport should never count as pinned")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	condition := map[string]map[string]interface{}{
+		"IpAddress":    {"aws:SourceIp": "203.0.113.0/24"},
+		"StringEquals": {"aws:PrincipalOrgID": []interface{}{"o-aaa", "o-bbb"}},
+	}
+
+	got := Describe(condition)
+	want := []string{
+		"IpAddress aws:SourceIp = 203.0.113.0/24",
+		"StringEquals aws:PrincipalOrgID = o-aaa, o-bbb",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d descriptions, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Description %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package query
+
+import (
+	"sort"
+
+	"github.com/pfrederiksen/aws-access-map/internal/policy"
+)
+
+// Capability is a single (action, resource) pair a principal can exercise.
+// Actions come back as written in the granting policies, so wildcard patterns
+// like "s3:*" appear verbatim.
+type Capability struct {
+	Action      string `json:"action"`
+	ResourceARN string `json:"resource"`
+}
+
+// AllowedAction declares one entry of a permission allowlist for
+// UnexpectedPermissions. Action and Resource use the same wildcard patterns
+// as IAM policies ("s3:Get*", "arn:aws:s3:::logs-*"). An empty Resource
+// matches any resource.
+type AllowedAction struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource,omitempty"`
+}
+
+// EffectivePermissions enumerates what a principal can actually do: every
+// action appearing on its permission edges (including edges inherited via
+// groups) is checked against every known resource using the full CanAccess
+// evaluation, so SCPs, boundaries, explicit denies, and conditions all apply.
+func (e *Engine) EffectivePermissions(principalARN string) ([]Capability, error) {
+	var capabilities []Capability
+
+	for _, action := range e.graph.GetPrincipalActionNames(principalARN) {
+		for _, resource := range e.graph.GetAllResources() {
+			if e.graph.CanAccess(principalARN, action, resource.ARN, e.context) {
+				capabilities = append(capabilities, Capability{
+					Action:      action,
+					ResourceARN: resource.ARN,
+				})
+			}
+		}
+	}
+
+	sort.Slice(capabilities, func(i, j int) bool {
+		if capabilities[i].Action != capabilities[j].Action {
+			return capabilities[i].Action < capabilities[j].Action
+		}
+		return capabilities[i].ResourceARN < capabilities[j].ResourceARN
+	})
+
+	return capabilities, nil
+}
+
+// DiffCapabilities compares two effective-permission sets and returns the
+// capabilities present only in after (added) and only in before (removed).
+// Both inputs keep their sorted order from EffectivePermissions.
+func DiffCapabilities(before, after []Capability) (added, removed []Capability) {
+	inBefore := make(map[Capability]bool, len(before))
+	for _, capability := range before {
+		inBefore[capability] = true
+	}
+	inAfter := make(map[Capability]bool, len(after))
+	for _, capability := range after {
+		inAfter[capability] = true
+	}
+
+	for _, capability := range after {
+		if !inBefore[capability] {
+			added = append(added, capability)
+		}
+	}
+	for _, capability := range before {
+		if !inAfter[capability] {
+			removed = append(removed, capability)
+		}
+	}
+	return added, removed
+}
+
+// UnexpectedPermissions returns the effective capabilities of a principal
+// that no allowlist entry covers. A capability is covered when some entry's
+// action and resource patterns both match it; a wildcard capability like
+// "s3:*" is only covered by an entry at least as broad.
+func (e *Engine) UnexpectedPermissions(principalARN string, allowed []AllowedAction) ([]Capability, error) {
+	capabilities, err := e.EffectivePermissions(principalARN)
+	if err != nil {
+		return nil, err
+	}
+
+	var unexpected []Capability
+	for _, capability := range capabilities {
+		covered := false
+		for _, entry := range allowed {
+			resourcePattern := entry.Resource
+			if resourcePattern == "" {
+				resourcePattern = "*"
+			}
+			if policy.MatchesAction(entry.Action, capability.Action) &&
+				policy.MatchesResource(resourcePattern, capability.ResourceARN) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			unexpected = append(unexpected, capability)
+		}
+	}
+
+	return unexpected, nil
+}
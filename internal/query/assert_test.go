@@ -0,0 +1,132 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestEffectivePermissions(t *testing.T) {
+	g := setupTestGraph()
+	e := New(g)
+
+	capabilities, err := e.EffectivePermissions("arn:aws:iam::123456789012:user/s3-user")
+	if err != nil {
+		t.Fatalf("EffectivePermissions() error = %v", err)
+	}
+
+	if len(capabilities) != 1 {
+		t.Fatalf("Expected 1 capability, got %d", len(capabilities))
+	}
+	if capabilities[0].Action != "s3:*" {
+		t.Errorf("Expected action s3:*, got %s", capabilities[0].Action)
+	}
+	if capabilities[0].ResourceARN != "arn:aws:s3:::public-bucket" {
+		t.Errorf("Expected resource arn:aws:s3:::public-bucket, got %s", capabilities[0].ResourceARN)
+	}
+}
+
+func TestUnexpectedPermissions_WithinAllowlist(t *testing.T) {
+	g := setupTestGraph()
+	e := New(g)
+
+	allowed := []AllowedAction{
+		{Action: "s3:*", Resource: "arn:aws:s3:::*"},
+	}
+
+	unexpected, err := e.UnexpectedPermissions("arn:aws:iam::123456789012:user/s3-user", allowed)
+	if err != nil {
+		t.Fatalf("UnexpectedPermissions() error = %v", err)
+	}
+	if len(unexpected) != 0 {
+		t.Errorf("Expected no unexpected capabilities, got %v", unexpected)
+	}
+}
+
+func TestUnexpectedPermissions_BeyondAllowlist(t *testing.T) {
+	g := setupTestGraph()
+	e := New(g)
+
+	// s3-user has s3:* on everything; allowlist only covers GetObject
+	allowed := []AllowedAction{
+		{Action: "s3:GetObject", Resource: "arn:aws:s3:::public-bucket/*"},
+	}
+
+	unexpected, err := e.UnexpectedPermissions("arn:aws:iam::123456789012:user/s3-user", allowed)
+	if err != nil {
+		t.Fatalf("UnexpectedPermissions() error = %v", err)
+	}
+	if len(unexpected) == 0 {
+		t.Fatal("Expected the s3:* capability to be flagged as unexpected")
+	}
+	if unexpected[0].Action != "s3:*" {
+		t.Errorf("Expected flagged action s3:*, got %s", unexpected[0].Action)
+	}
+}
+
+func TestDiffCapabilities(t *testing.T) {
+	before := []Capability{
+		{Action: "s3:GetObject", ResourceARN: "arn:aws:s3:::data"},
+		{Action: "sqs:SendMessage", ResourceARN: "arn:aws:sqs:us-east-1:123456789012:jobs"},
+	}
+	after := []Capability{
+		{Action: "kms:Decrypt", ResourceARN: "arn:aws:kms:us-east-1:123456789012:key/abc"},
+		{Action: "s3:GetObject", ResourceARN: "arn:aws:s3:::data"},
+	}
+
+	added, removed := DiffCapabilities(before, after)
+
+	if len(added) != 1 || added[0].Action != "kms:Decrypt" {
+		t.Errorf("Expected added = [kms:Decrypt], got %v", added)
+	}
+	if len(removed) != 1 || removed[0].Action != "sqs:SendMessage" {
+		t.Errorf("Expected removed = [sqs:SendMessage], got %v", removed)
+	}
+
+	added, removed = DiffCapabilities(before, before)
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("Expected no diff for identical sets, got added=%v removed=%v", added, removed)
+	}
+}
+
+func TestUnexpectedPermissions_ViaGroup(t *testing.T) {
+	g := graph.New()
+
+	groupARN := "arn:aws:iam::123456789012:group/developers"
+	g.AddPrincipal(&types.Principal{
+		ARN:  groupARN,
+		Type: types.PrincipalTypeGroup,
+		Name: "developers",
+	})
+	g.AddEdge(groupARN, "sqs:SendMessage", "*", false)
+
+	userARN := "arn:aws:iam::123456789012:user/dev"
+	g.AddPrincipal(&types.Principal{
+		ARN:              userARN,
+		Type:             types.PrincipalTypeUser,
+		Name:             "dev",
+		GroupMemberships: []string{groupARN},
+	})
+
+	g.AddResource(&types.Resource{
+		ARN:  "arn:aws:sqs:us-east-1:123456789012:jobs",
+		Type: types.ResourceTypeSQS,
+		Name: "jobs",
+	})
+
+	e := New(g)
+
+	unexpected, err := e.UnexpectedPermissions(userARN, []AllowedAction{
+		{Action: "s3:GetObject"},
+	})
+	if err != nil {
+		t.Fatalf("UnexpectedPermissions() error = %v", err)
+	}
+	if len(unexpected) != 1 {
+		t.Fatalf("Expected 1 unexpected capability via group, got %d", len(unexpected))
+	}
+	if unexpected[0].Action != "sqs:SendMessage" {
+		t.Errorf("Expected flagged action sqs:SendMessage, got %s", unexpected[0].Action)
+	}
+}
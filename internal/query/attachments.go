@@ -0,0 +1,40 @@
+package query
+
+import (
+	"sort"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// FindPrincipalsWithPolicy returns the principals whose collected policies
+// came from the given managed policy, matched by source policy ARN or name.
+// This answers "who has AdministratorAccess attached" - attachment, not
+// effective access - so a principal appears even if SCPs or boundaries would
+// block the policy's grants. Results are sorted by principal ARN.
+func (e *Engine) FindPrincipalsWithPolicy(policyRef string) []*types.Principal {
+	var result []*types.Principal
+
+	for _, principal := range e.graph.GetAllPrincipals() {
+		for _, doc := range principal.Policies {
+			if policyMatchesRef(doc, policyRef) {
+				result = append(result, principal)
+				break
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ARN < result[j].ARN
+	})
+
+	return result
+}
+
+// policyMatchesRef reports whether a policy document came from the managed
+// policy identified by ref (an ARN or a bare policy name)
+func policyMatchesRef(doc types.PolicyDocument, ref string) bool {
+	if doc.SourcePolicyARN == "" && doc.SourcePolicyName == "" {
+		return false // Inline policy, no attachment to match
+	}
+	return doc.SourcePolicyARN == ref || doc.SourcePolicyName == ref
+}
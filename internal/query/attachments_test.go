@@ -0,0 +1,68 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestFindPrincipalsWithPolicy(t *testing.T) {
+	adminPolicyARN := "arn:aws:iam::aws:policy/AdministratorAccess"
+	adminDoc := types.PolicyDocument{
+		Version:          "2012-10-17",
+		SourcePolicyARN:  adminPolicyARN,
+		SourcePolicyName: "AdministratorAccess",
+		Statements: []types.Statement{
+			{Effect: types.EffectAllow, Action: "*", Resource: "*"},
+		},
+	}
+	inlineDoc := types.PolicyDocument{
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "*"},
+		},
+	}
+
+	g := graph.New()
+	g.AddPrincipal(&types.Principal{
+		ARN:      "arn:aws:iam::123456789012:user/admin",
+		Type:     types.PrincipalTypeUser,
+		Name:     "admin",
+		Policies: []types.PolicyDocument{adminDoc},
+	})
+	g.AddPrincipal(&types.Principal{
+		ARN:      "arn:aws:iam::123456789012:role/ops",
+		Type:     types.PrincipalTypeRole,
+		Name:     "ops",
+		Policies: []types.PolicyDocument{inlineDoc, adminDoc},
+	})
+	g.AddPrincipal(&types.Principal{
+		ARN:      "arn:aws:iam::123456789012:user/reader",
+		Type:     types.PrincipalTypeUser,
+		Name:     "reader",
+		Policies: []types.PolicyDocument{inlineDoc},
+	})
+
+	e := New(g)
+
+	// Match by ARN
+	principals := e.FindPrincipalsWithPolicy(adminPolicyARN)
+	if len(principals) != 2 {
+		t.Fatalf("Expected 2 principals with the policy attached, got %d", len(principals))
+	}
+	// Sorted by ARN: role/ops before user/admin
+	if principals[0].Name != "ops" || principals[1].Name != "admin" {
+		t.Errorf("Unexpected order: %s, %s", principals[0].Name, principals[1].Name)
+	}
+
+	// Match by bare name
+	if byName := e.FindPrincipalsWithPolicy("AdministratorAccess"); len(byName) != 2 {
+		t.Errorf("Expected 2 principals matching by name, got %d", len(byName))
+	}
+
+	// Inline policies never match, even with an empty ref
+	if matched := e.FindPrincipalsWithPolicy(""); len(matched) != 0 {
+		t.Errorf("Empty ref must not match inline policies, got %d principals", len(matched))
+	}
+}
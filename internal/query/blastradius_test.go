@@ -0,0 +1,110 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestBlastRadius(t *testing.T) {
+	alice := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/alice",
+		Type: types.PrincipalTypeUser,
+		Name: "alice",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "s3:GetObject",
+						Resource: "arn:aws:s3:::dev-bucket/*",
+					},
+				},
+			},
+		},
+	}
+	// Role alice can assume, reaching a second service
+	opsRole := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:role/OpsRole",
+		Type: types.PrincipalTypeRole,
+		Name: "OpsRole",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "sqs:SendMessage",
+						Resource: "arn:aws:sqs:us-east-1:123456789012:jobs",
+					},
+				},
+			},
+		},
+		TrustPolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect: types.EffectAllow,
+					Principal: map[string]interface{}{
+						"AWS": alice.ARN,
+					},
+					Action: "sts:AssumeRole",
+				},
+			},
+		},
+	}
+
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::dev-bucket/*",
+		Type: types.ResourceTypeS3,
+		Name: "dev-bucket",
+	}
+	queue := &types.Resource{
+		ARN:  "arn:aws:sqs:us-east-1:123456789012:jobs",
+		Type: types.ResourceTypeSQS,
+		Name: "jobs",
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{alice, opsRole},
+		Resources:  []*types.Resource{bucket, queue},
+	})
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+
+	radius, err := New(g).BlastRadius(alice.ARN)
+	if err != nil {
+		t.Fatalf("BlastRadius() error = %v", err)
+	}
+
+	// Alice herself plus the assumable role
+	if len(radius.Identities) != 2 {
+		t.Fatalf("Expected 2 identities, got %d: %v", len(radius.Identities), radius.Identities)
+	}
+	if radius.Identities[1] != opsRole.ARN {
+		t.Errorf("Expected assumable role %s, got %s", opsRole.ARN, radius.Identities[1])
+	}
+
+	// Both the direct S3 grant and the role's SQS grant are in the radius,
+	// sorted by service
+	if len(radius.Resources) != 2 {
+		t.Fatalf("Expected 2 reachable resources, got %d: %+v", len(radius.Resources), radius.Resources)
+	}
+	if radius.Resources[0].Service != "s3" || radius.Resources[0].ResourceARN != bucket.ARN {
+		t.Errorf("First resource = %+v, want the s3 bucket", radius.Resources[0])
+	}
+	if radius.Resources[1].Service != "sqs" || len(radius.Resources[1].Actions) != 1 || radius.Resources[1].Actions[0] != "sqs:SendMessage" {
+		t.Errorf("Second resource = %+v, want the sqs queue with sqs:SendMessage", radius.Resources[1])
+	}
+}
+
+func TestBlastRadius_PrincipalNotFound(t *testing.T) {
+	g := graph.New()
+	if _, err := New(g).BlastRadius("arn:aws:iam::123456789012:user/ghost"); err == nil {
+		t.Error("BlastRadius() should error for an unknown principal")
+	}
+}
@@ -0,0 +1,117 @@
+package query
+
+import (
+	"sort"
+
+	"github.com/pfrederiksen/aws-access-map/internal/findings"
+	"github.com/pfrederiksen/aws-access-map/internal/policy"
+)
+
+// CorrelatedFinding pairs an external scanner finding with the graph context
+// this tool can add: for principals the blast radius, for resources the set
+// of principals holding grants on them
+type CorrelatedFinding struct {
+	Finding findings.Finding `json:"finding"`
+
+	// PrincipalARN is set when the finding's resource is a graph principal
+	PrincipalARN string `json:"principal,omitempty"`
+
+	// BlastRadius is the reachable resource set for a matched principal
+	BlastRadius *BlastRadiusResult `json:"blast_radius,omitempty"`
+
+	// ResourceARN is set when the finding's resource is a graph resource
+	ResourceARN string `json:"resource,omitempty"`
+
+	// GrantedPrincipals lists principals with allow edges on a matched
+	// resource - the audience whose access the finding puts at risk
+	GrantedPrincipals []string `json:"granted_principals,omitempty"`
+
+	// Unmatched findings carry neither ARN; they are kept so the report
+	// shows the full scanner output alongside the correlated subset
+}
+
+// CorrelateFindings matches external scanner findings against the graph and
+// augments the matches with reachability context. Findings that reference
+// nothing in the graph pass through unmatched; a scanner covering services
+// this tool does not collect is normal, not an error.
+func (e *Engine) CorrelateFindings(items []findings.Finding) []CorrelatedFinding {
+	result := make([]CorrelatedFinding, 0, len(items))
+	for _, finding := range items {
+		correlated := CorrelatedFinding{Finding: finding}
+
+		if arn := e.matchPrincipal(finding); arn != "" {
+			correlated.PrincipalARN = arn
+			// Blast radius answers "here's everything this principal can
+			// reach"; a lookup failure just leaves the match unaugmented
+			if radius, err := e.BlastRadius(arn); err == nil {
+				correlated.BlastRadius = radius
+			}
+		} else if arn := e.matchResource(finding); arn != "" {
+			correlated.ResourceARN = arn
+			correlated.GrantedPrincipals = e.principalsWithGrants(arn)
+		}
+
+		result = append(result, correlated)
+	}
+	return result
+}
+
+// matchPrincipal resolves a finding to a graph principal by ARN, falling back
+// to the resource ID as a principal name
+func (e *Engine) matchPrincipal(finding findings.Finding) string {
+	if finding.ResourceARN != "" {
+		if _, ok := e.graph.GetPrincipal(finding.ResourceARN); ok {
+			return finding.ResourceARN
+		}
+	}
+	if finding.ResourceID == "" {
+		return ""
+	}
+	for _, principal := range e.graph.GetAllPrincipals() {
+		if principal.Name == finding.ResourceID {
+			return principal.ARN
+		}
+	}
+	return ""
+}
+
+// matchResource resolves a finding to a graph resource by ARN, falling back
+// to the resource ID as a resource name
+func (e *Engine) matchResource(finding findings.Finding) string {
+	if finding.ResourceARN != "" {
+		if _, ok := e.graph.GetResource(finding.ResourceARN); ok {
+			return finding.ResourceARN
+		}
+	}
+	if finding.ResourceID == "" {
+		return ""
+	}
+	for _, resource := range e.graph.GetAllResources() {
+		if resource.Name == finding.ResourceID {
+			return resource.ARN
+		}
+	}
+	return ""
+}
+
+// principalsWithGrants lists the distinct principals holding allow edges that
+// cover the resource, sorted for stable output
+func (e *Engine) principalsWithGrants(resourceARN string) []string {
+	seen := map[string]bool{}
+	for _, edge := range e.graph.GetPermissionEdges() {
+		if edge.IsDeny {
+			continue
+		}
+		if !policy.MatchesResource(edge.ResourceARN, resourceARN) {
+			continue
+		}
+		seen[edge.PrincipalARN] = true
+	}
+
+	principals := make([]string, 0, len(seen))
+	for arn := range seen {
+		principals = append(principals, arn)
+	}
+	sort.Strings(principals)
+	return principals
+}
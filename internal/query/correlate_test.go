@@ -0,0 +1,136 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/findings"
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func correlateTestGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	deployer := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:role/ci-deploy",
+		Type:      types.PrincipalTypeRole,
+		Name:      "ci-deploy",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "s3:PutObject",
+						Resource: "arn:aws:s3:::prod-data*",
+					},
+				},
+			},
+		},
+	}
+	reader := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/analyst",
+		Type:      types.PrincipalTypeUser,
+		Name:      "analyst",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "s3:GetObject",
+						Resource: "arn:aws:s3:::prod-data",
+					},
+				},
+			},
+		},
+	}
+	bucket := &types.Resource{
+		ARN:       "arn:aws:s3:::prod-data",
+		Type:      types.ResourceTypeS3,
+		Name:      "prod-data",
+		AccountID: "123456789012",
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{deployer, reader},
+		Resources:  []*types.Resource{bucket},
+	})
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+	return g
+}
+
+func TestCorrelateFindings(t *testing.T) {
+	engine := New(correlateTestGraph(t))
+
+	correlated := engine.CorrelateFindings([]findings.Finding{
+		{
+			CheckID:     "iam_role_administratoraccess_policy",
+			Status:      "FAIL",
+			Severity:    "high",
+			ResourceARN: "arn:aws:iam::123456789012:role/ci-deploy",
+			ResourceID:  "ci-deploy",
+		},
+		{
+			CheckID:     "s3_bucket_public_access",
+			Status:      "FAIL",
+			Severity:    "critical",
+			ResourceARN: "arn:aws:s3:::prod-data",
+			ResourceID:  "prod-data",
+		},
+		{
+			CheckID:    "ec2_instance_public_ip",
+			Status:     "FAIL",
+			ResourceID: "i-0123456789abcdef0",
+		},
+	})
+
+	if len(correlated) != 3 {
+		t.Fatalf("Expected 3 correlated findings, got %d", len(correlated))
+	}
+
+	// Principal finding gains a blast radius
+	principal := correlated[0]
+	if principal.PrincipalARN != "arn:aws:iam::123456789012:role/ci-deploy" {
+		t.Errorf("Expected principal match, got %q", principal.PrincipalARN)
+	}
+	if principal.BlastRadius == nil {
+		t.Fatal("Expected blast radius for the matched principal")
+	}
+	if len(principal.BlastRadius.Resources) == 0 {
+		t.Error("Expected reachable resources in the blast radius")
+	}
+
+	// Resource finding gains the principals holding grants on it
+	resource := correlated[1]
+	if resource.ResourceARN != "arn:aws:s3:::prod-data" {
+		t.Errorf("Expected resource match, got %q", resource.ResourceARN)
+	}
+	if len(resource.GrantedPrincipals) != 2 {
+		t.Fatalf("Expected 2 granted principals, got %v", resource.GrantedPrincipals)
+	}
+
+	// Unknown resources pass through unmatched
+	unmatched := correlated[2]
+	if unmatched.PrincipalARN != "" || unmatched.ResourceARN != "" {
+		t.Errorf("Expected no match for unknown resource, got %+v", unmatched)
+	}
+}
+
+func TestCorrelateFindings_NameFallback(t *testing.T) {
+	engine := New(correlateTestGraph(t))
+
+	// No ARN in the finding - match on the resource ID as a name
+	correlated := engine.CorrelateFindings([]findings.Finding{
+		{CheckID: "iam_user_mfa", Status: "FAIL", ResourceID: "analyst"},
+	})
+
+	if correlated[0].PrincipalARN != "arn:aws:iam::123456789012:user/analyst" {
+		t.Errorf("Expected name-based principal match, got %q", correlated[0].PrincipalARN)
+	}
+}
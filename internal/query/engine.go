@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pfrederiksen/aws-access-map/internal/graph"
 	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
@@ -12,8 +13,29 @@ import (
 type Engine struct {
 	graph   *graph.Graph
 	context *conditions.EvaluationContext
+
+	// candidates restricts who-can evaluation to these principal ARNs;
+	// nil means evaluate every principal in the graph
+	candidates map[string]bool
+
+	// withEvidence makes WhoCanDetailed attach the granting policy statements
+	// to each principal decision
+	withEvidence bool
+
+	// pathMaxDepth and pathMaxPaths bound FindPaths' BFS; zero means the
+	// package defaults apply
+	pathMaxDepth int
+	pathMaxPaths int
 }
 
+// Default FindPaths limits. BFS cost grows with the number of assumable-role
+// edges raised to the depth, so deep searches over dense role meshes get
+// expensive quickly
+const (
+	defaultPathMaxDepth = 5  // Prevent runaway queries
+	defaultPathMaxPaths = 10 // Limit result size
+)
+
 // New creates a new query engine with default permissive context
 func New(g *graph.Graph) *Engine {
 	return &Engine{
@@ -25,20 +47,101 @@ func New(g *graph.Graph) *Engine {
 // WithContext returns a new engine with the specified evaluation context
 func (e *Engine) WithContext(ctx *conditions.EvaluationContext) *Engine {
 	return &Engine{
-		graph:   e.graph,
-		context: ctx,
+		graph:        e.graph,
+		context:      ctx,
+		candidates:   e.candidates,
+		withEvidence: e.withEvidence,
+		pathMaxDepth: e.pathMaxDepth,
+		pathMaxPaths: e.pathMaxPaths,
+	}
+}
+
+// WithPathLimits returns a new engine whose FindPaths explores up to maxDepth
+// role-assumption hops and returns at most maxPaths paths. Values below 1 keep
+// the defaults (5 hops, 10 paths). Large depths over dense role meshes can be
+// slow - each extra hop multiplies the search frontier
+func (e *Engine) WithPathLimits(maxDepth, maxPaths int) *Engine {
+	clone := &Engine{
+		graph:        e.graph,
+		context:      e.context,
+		candidates:   e.candidates,
+		withEvidence: e.withEvidence,
+		pathMaxDepth: e.pathMaxDepth,
+		pathMaxPaths: e.pathMaxPaths,
+	}
+	if maxDepth >= 1 {
+		clone.pathMaxDepth = maxDepth
+	}
+	if maxPaths >= 1 {
+		clone.pathMaxPaths = maxPaths
+	}
+	return clone
+}
+
+// WithEvidence returns a new engine whose WhoCanDetailed results carry the
+// policy statements that grant each principal's access
+func (e *Engine) WithEvidence(enabled bool) *Engine {
+	return &Engine{
+		graph:        e.graph,
+		context:      e.context,
+		candidates:   e.candidates,
+		withEvidence: enabled,
+		pathMaxDepth: e.pathMaxDepth,
+		pathMaxPaths: e.pathMaxPaths,
+	}
+}
+
+// WithCandidates returns a new engine whose who-can queries only evaluate the
+// given principal ARNs, a large-account fast path for when the suspects are
+// already known. An empty list leaves the engine unrestricted.
+func (e *Engine) WithCandidates(principalARNs []string) *Engine {
+	if len(principalARNs) == 0 {
+		return e
+	}
+	candidates := make(map[string]bool, len(principalARNs))
+	for _, arn := range principalARNs {
+		candidates[arn] = true
+	}
+	return &Engine{
+		graph:        e.graph,
+		context:      e.context,
+		candidates:   candidates,
+		withEvidence: e.withEvidence,
+		pathMaxDepth: e.pathMaxDepth,
+		pathMaxPaths: e.pathMaxPaths,
 	}
 }
 
+// candidatePrincipals returns the principals who-can queries should evaluate,
+// honoring any candidate restriction
+func (e *Engine) candidatePrincipals() []*types.Principal {
+	principals := e.graph.GetAllPrincipals()
+	if e.candidates == nil {
+		return principals
+	}
+	filtered := make([]*types.Principal, 0, len(e.candidates))
+	for _, principal := range principals {
+		if e.candidates[principal.ARN] {
+			filtered = append(filtered, principal)
+		}
+	}
+	return filtered
+}
+
 // WhoCan finds all principals that can perform an action on a resource
 // Uses the engine's evaluation context for condition evaluation
 func (e *Engine) WhoCan(resourceARN, action string) ([]*types.Principal, error) {
 	var result []*types.Principal
 
+	// Memoize the per-account SCP verdict for this (action, resource) pair:
+	// it is identical for every principal in an account, so thousands of
+	// principals should not each re-run the SCP filtering
+	scpCache := e.graph.NewSCPDecisionCache()
+
 	// Check all principals
-	for _, principal := range e.graph.GetAllPrincipals() {
+	for _, principal := range e.candidatePrincipals() {
 		// Check direct access with context
-		if e.graph.CanAccess(principal.ARN, action, resourceARN, e.context) {
+		if e.graph.CanAccessCached(principal.ARN, action, resourceARN, scpCache, e.context) {
 			result = append(result, principal)
 		}
 	}
@@ -48,13 +151,179 @@ func (e *Engine) WhoCan(resourceARN, action string) ([]*types.Principal, error)
 	return result, nil
 }
 
+// PrincipalDecision pairs a principal with the tri-state decision for an action
+// and, for CONDITIONAL access, the conditions that gate it. Evidence is
+// populated only when the engine was built WithEvidence.
+type PrincipalDecision struct {
+	Principal  *types.Principal
+	Decision   graph.AccessDecision
+	Conditions []string
+	Evidence   []graph.AccessEvidence
+}
+
+// WhoCanDetailed is WhoCan with tri-state results: principals whose access
+// depends on conditions the evaluation context left unspecified come back as
+// CONDITIONAL (with those conditions) instead of blending in as allowed
+func (e *Engine) WhoCanDetailed(resourceARN, action string) ([]PrincipalDecision, error) {
+	var result []PrincipalDecision
+
+	for _, principal := range e.candidatePrincipals() {
+		detail := e.graph.CanAccessDetail(principal.ARN, action, resourceARN, e.context)
+		if detail.Decision == graph.DecisionDenied {
+			continue
+		}
+		decision := PrincipalDecision{
+			Principal:  principal,
+			Decision:   detail.Decision,
+			Conditions: detail.Conditions,
+		}
+		if e.withEvidence {
+			decision.Evidence = e.graph.ExplainAccess(principal.ARN, action, resourceARN, e.context)
+		}
+		result = append(result, decision)
+	}
+
+	return result, nil
+}
+
+// PrincipalFilter restricts who-can results by principal type and/or account.
+// Empty fields leave that dimension unfiltered, so the zero value keeps every
+// result
+type PrincipalFilter struct {
+	Types    []string // Principal types to keep (user, role, group, ...)
+	Accounts []string // Account IDs to keep
+}
+
+// knownPrincipalTypes is the set of type names PrincipalFilter accepts
+var knownPrincipalTypes = map[types.PrincipalType]bool{
+	types.PrincipalTypeUser:     true,
+	types.PrincipalTypeRole:     true,
+	types.PrincipalTypeGroup:    true,
+	types.PrincipalTypeService:  true,
+	types.PrincipalTypePublic:   true,
+	types.PrincipalTypeSSOUser:  true,
+	types.PrincipalTypeSSOGroup: true,
+}
+
+// Validate rejects unknown principal type names so a typo like --type iam
+// fails loudly instead of silently matching nothing
+func (f PrincipalFilter) Validate() error {
+	for _, t := range f.Types {
+		name := types.PrincipalType(strings.ToLower(strings.TrimSpace(t)))
+		if !knownPrincipalTypes[name] {
+			return fmt.Errorf("unknown principal type %q (valid: user, role, group, service, public, sso-user, sso-group)", strings.TrimSpace(t))
+		}
+	}
+	return nil
+}
+
+// Empty reports whether the filter restricts nothing
+func (f PrincipalFilter) Empty() bool {
+	return len(f.Types) == 0 && len(f.Accounts) == 0
+}
+
+// Matches reports whether a principal passes the filter
+func (f PrincipalFilter) Matches(principal *types.Principal) bool {
+	if len(f.Types) > 0 {
+		matched := false
+		for _, t := range f.Types {
+			if types.PrincipalType(strings.ToLower(strings.TrimSpace(t))) == principal.Type {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(f.Accounts) > 0 {
+		matched := false
+		for _, account := range f.Accounts {
+			if strings.TrimSpace(account) == principal.AccountID {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the principals that pass the filter, preserving order
+func (f PrincipalFilter) Apply(principals []*types.Principal) []*types.Principal {
+	if f.Empty() {
+		return principals
+	}
+	var filtered []*types.Principal
+	for _, principal := range principals {
+		if f.Matches(principal) {
+			filtered = append(filtered, principal)
+		}
+	}
+	return filtered
+}
+
+// WhoCanFiltered is WhoCan with the results restricted to principals that
+// pass the filter. An empty filter behaves exactly like WhoCan
+func (e *Engine) WhoCanFiltered(resourceARN, action string, filter PrincipalFilter) ([]*types.Principal, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	principals, err := e.WhoCan(resourceARN, action)
+	if err != nil {
+		return nil, err
+	}
+	return filter.Apply(principals), nil
+}
+
+// WhoCanDetailedFiltered is WhoCanDetailed restricted the same way
+func (e *Engine) WhoCanDetailedFiltered(resourceARN, action string, filter PrincipalFilter) ([]PrincipalDecision, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	decisions, err := e.WhoCanDetailed(resourceARN, action)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Empty() {
+		return decisions, nil
+	}
+	var filtered []PrincipalDecision
+	for _, decision := range decisions {
+		if filter.Matches(decision.Principal) {
+			filtered = append(filtered, decision)
+		}
+	}
+	return filtered, nil
+}
+
 // FindPaths finds all access paths from a principal to a resource
 // It uses BFS to discover both direct access and transitive access through role assumptions
 func (e *Engine) FindPaths(fromPrincipalARN, toResourceARN, action string) ([]*types.AccessPath, error) {
-	// Validate principal exists first
+	// Validate principal exists first. Service principals (e.g.
+	// lambda.amazonaws.com) are not graph principals, but they appear as
+	// trustors in trust policies, so synthesize one when it is referenced -
+	// this answers "can the Lambda service reach this resource"
 	principal, ok := e.graph.GetPrincipal(fromPrincipalARN)
 	if !ok {
-		return nil, fmt.Errorf("principal not found: %s", fromPrincipalARN)
+		if isServicePrincipal(fromPrincipalARN) {
+			principal, ok = e.synthesizeServicePrincipal(fromPrincipalARN)
+			if !ok {
+				return nil, fmt.Errorf("service principal %s is not referenced by any trust or resource policy", fromPrincipalARN)
+			}
+		} else if principal, ok = e.synthesizeWorkloadPrincipal(fromPrincipalARN); !ok {
+			return nil, fmt.Errorf("principal not found: %s", fromPrincipalARN)
+		}
+	}
+
+	// Resolves the synthetic starting principal as well as real graph members
+	lookupPrincipal := func(arn string) (*types.Principal, bool) {
+		if arn == principal.ARN {
+			return principal, true
+		}
+		return e.graph.GetPrincipal(arn)
 	}
 
 	// BFS queue: each item is (current principal, path of hops taken to get here)
@@ -68,8 +337,14 @@ func (e *Engine) FindPaths(fromPrincipalARN, toResourceARN, action string) ([]*t
 	visited[fromPrincipalARN] = true
 
 	var paths []*types.AccessPath
-	const maxDepth = 5      // Prevent runaway queries
-	const maxPaths = 10     // Limit result size
+	maxDepth := e.pathMaxDepth
+	if maxDepth < 1 {
+		maxDepth = defaultPathMaxDepth
+	}
+	maxPaths := e.pathMaxPaths
+	if maxPaths < 1 {
+		maxPaths = defaultPathMaxPaths
+	}
 
 	for len(queue) > 0 {
 		// Check if we've found enough paths
@@ -96,7 +371,7 @@ func (e *Engine) FindPaths(fromPrincipalARN, toResourceARN, action string) ([]*t
 			}
 
 			// Get the current principal for the final hop
-			currentPrincipal, ok := e.graph.GetPrincipal(current.principalARN)
+			currentPrincipal, ok := lookupPrincipal(current.principalARN)
 			if !ok {
 				continue
 			}
@@ -133,10 +408,16 @@ func (e *Engine) FindPaths(fromPrincipalARN, toResourceARN, action string) ([]*t
 				continue
 			}
 
+			// Trust alone is not enough: an SCP, permission boundary or
+			// explicit deny on sts:AssumeRole blocks the hop in practice
+			if e.graph.AssumeRoleBlocked(current.principalARN, role.ARN, e.context) {
+				continue
+			}
+
 			visited[role.ARN] = true
 
 			// Get current principal for the hop
-			currentPrincipal, ok := e.graph.GetPrincipal(current.principalARN)
+			currentPrincipal, ok := lookupPrincipal(current.principalARN)
 			if !ok {
 				continue
 			}
@@ -162,6 +443,76 @@ func (e *Engine) FindPaths(fromPrincipalARN, toResourceARN, action string) ([]*t
 	return paths, nil
 }
 
+// isServicePrincipal reports whether an identifier is an AWS service
+// principal (e.g. lambda.amazonaws.com) rather than an ARN
+func isServicePrincipal(s string) bool {
+	return !strings.HasPrefix(s, "arn:") && strings.HasSuffix(s, ".amazonaws.com")
+}
+
+// synthesizeServicePrincipal builds an in-memory principal for a service that
+// at least one trust or resource policy references. The graph itself is not
+// mutated; FindPaths resolves the synthetic principal locally.
+func (e *Engine) synthesizeServicePrincipal(service string) (*types.Principal, bool) {
+	if len(e.graph.GetRolesCanAssume(service)) == 0 && len(e.graph.GetPrincipalActionNames(service)) == 0 {
+		return nil, false
+	}
+	return &types.Principal{
+		ARN:  service,
+		Type: types.PrincipalTypeService,
+		Name: service,
+	}, true
+}
+
+// synthesizeWorkloadPrincipal builds an in-memory principal for a compute
+// resource that runs as IAM roles - currently ECS task definitions, whose
+// task/execution role linkage the graph records as trust relations. Like
+// service principals, the graph itself is not mutated
+func (e *Engine) synthesizeWorkloadPrincipal(arn string) (*types.Principal, bool) {
+	resource, ok := e.graph.GetResource(arn)
+	if !ok || resource.Type != types.ResourceTypeECS {
+		return nil, false
+	}
+	if len(e.graph.GetRolesCanAssume(arn)) == 0 {
+		return nil, false
+	}
+	return &types.Principal{
+		ARN:  arn,
+		Type: types.PrincipalTypeService,
+		Name: resource.Name,
+	}, true
+}
+
+// ActionPrincipals pairs an action with the principals able to perform it
+type ActionPrincipals struct {
+	Action     string
+	Principals []*types.Principal
+}
+
+// WhoCanEach runs WhoCan for each action against the resource, preserving
+// the input order so bulk audit output is stable
+func (e *Engine) WhoCanEach(resourceARN string, actions []string) ([]ActionPrincipals, error) {
+	results := make([]ActionPrincipals, 0, len(actions))
+
+	for _, action := range actions {
+		principals, err := e.WhoCan(resourceARN, action)
+		if err != nil {
+			return nil, fmt.Errorf("query failed for action %s: %w", action, err)
+		}
+		results = append(results, ActionPrincipals{
+			Action:     action,
+			Principals: principals,
+		})
+	}
+
+	return results, nil
+}
+
+// DataFlowsFrom returns where data or events from a resource flow onward
+// (replication destinations, notification targets)
+func (e *Engine) DataFlowsFrom(resourceARN string) []types.DataFlow {
+	return e.graph.GetDataFlows(resourceARN)
+}
+
 // HighRiskFinding represents a high-risk access pattern
 type HighRiskFinding struct {
 	Type        string
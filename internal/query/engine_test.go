@@ -115,6 +115,33 @@ func TestWhoCan_S3GetObject(t *testing.T) {
 	}
 }
 
+func TestWhoCan_WithCandidates(t *testing.T) {
+	g := setupTestGraph()
+	e := New(g).WithCandidates([]string{"arn:aws:iam::123456789012:user/s3-user"})
+
+	// Both admin and s3-user can read, but only s3-user is a candidate
+	principals, err := e.WhoCan("arn:aws:s3:::any-bucket/*", "s3:GetObject")
+	if err != nil {
+		t.Fatalf("WhoCan() error = %v", err)
+	}
+
+	if len(principals) != 1 {
+		t.Fatalf("WhoCan() found %d principals, expected 1 (candidates restricted)", len(principals))
+	}
+	if principals[0].Name != "s3-user" {
+		t.Errorf("WhoCan() found %s, expected s3-user", principals[0].Name)
+	}
+
+	// An empty candidate list leaves the engine unrestricted
+	principals, err = e.WithCandidates(nil).WhoCan("arn:aws:s3:::any-bucket/*", "s3:GetObject")
+	if err != nil {
+		t.Fatalf("WhoCan() error = %v", err)
+	}
+	if len(principals) != 1 {
+		t.Errorf("WithCandidates(nil) should keep the existing restriction, got %d principals", len(principals))
+	}
+}
+
 func TestWhoCan_SpecificBucket(t *testing.T) {
 	g := setupTestGraph()
 	e := New(g)
@@ -175,6 +202,49 @@ func TestWhoCan_NoMatch(t *testing.T) {
 	}
 }
 
+func TestWhoCan_ServicePrincipal(t *testing.T) {
+	// Bucket policy granting write access to the CloudTrail service principal
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::trail-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "trail-bucket",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect: types.EffectAllow,
+					Principal: map[string]interface{}{
+						"Service": "cloudtrail.amazonaws.com",
+					},
+					Action:   "s3:PutObject",
+					Resource: "arn:aws:s3:::trail-bucket/*",
+				},
+			},
+		},
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		Resources: []*types.Resource{bucket},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// The service principal has no IAM node, but the graph synthesizes one
+	// from the resource policy, so who-can reports the service access
+	principals, err := New(g).WhoCan("arn:aws:s3:::trail-bucket/*", "s3:PutObject")
+	if err != nil {
+		t.Fatalf("WhoCan() error = %v", err)
+	}
+
+	if len(principals) != 1 {
+		t.Fatalf("WhoCan() found %d principals, expected 1 (the service principal)", len(principals))
+	}
+	if principals[0].ARN != "cloudtrail.amazonaws.com" || principals[0].Type != types.PrincipalTypeService {
+		t.Errorf("WhoCan() found %+v, want synthetic cloudtrail.amazonaws.com service principal", principals[0])
+	}
+}
+
 func TestFindPaths_DirectAccess(t *testing.T) {
 	g := setupTestGraph()
 	e := New(g)
@@ -329,6 +399,84 @@ func TestFindPaths_SingleRoleAssumption(t *testing.T) {
 	}
 }
 
+func TestFindPaths_ServicePrincipal(t *testing.T) {
+	// Lambda execution role trusted by the Lambda service principal
+	execRole := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:role/LambdaExec",
+		Type: types.PrincipalTypeRole,
+		Name: "LambdaExec",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "s3:GetObject",
+						Resource: "arn:aws:s3:::app-bucket/*",
+					},
+				},
+			},
+		},
+		TrustPolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect: types.EffectAllow,
+					Principal: map[string]interface{}{
+						"Service": "lambda.amazonaws.com",
+					},
+					Action: "sts:AssumeRole",
+				},
+			},
+		},
+	}
+
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::app-bucket/*",
+		Type: types.ResourceTypeS3,
+		Name: "app-bucket",
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		Principals: []*types.Principal{execRole},
+		Resources:  []*types.Resource{bucket},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	e := New(g)
+
+	// The service principal is not a graph principal, but it is referenced
+	// in the trust policy, so FindPaths synthesizes it
+	paths, err := e.FindPaths("lambda.amazonaws.com", bucket.ARN, "s3:GetObject")
+	if err != nil {
+		t.Fatalf("FindPaths() error = %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("FindPaths() should find path from the Lambda service through the execution role")
+	}
+
+	path := paths[0]
+	if path.From.ARN != "lambda.amazonaws.com" || path.From.Type != types.PrincipalTypeService {
+		t.Errorf("path.From = %+v, want synthetic service principal", path.From)
+	}
+	if len(path.Hops) != 2 {
+		t.Fatalf("Expected 2 hops, got %d", len(path.Hops))
+	}
+	if path.Hops[0].Action != "sts:AssumeRole" {
+		t.Errorf("First hop action should be sts:AssumeRole, got %s", path.Hops[0].Action)
+	}
+	if hopRole, ok := path.Hops[0].To.(*types.Principal); !ok || hopRole.ARN != execRole.ARN {
+		t.Errorf("First hop should target %s, got %+v", execRole.ARN, path.Hops[0].To)
+	}
+
+	// A service nothing references is still an error
+	if _, err := e.FindPaths("glue.amazonaws.com", bucket.ARN, "s3:GetObject"); err == nil {
+		t.Error("FindPaths() should error for a service principal no policy references")
+	}
+}
+
 func TestFindPaths_TwoHopRoleChain(t *testing.T) {
 	// Alice → DevRole → ProdRole → prod-bucket
 	alice := &types.Principal{
@@ -536,6 +684,81 @@ func TestFindPaths_MaxDepthLimit(t *testing.T) {
 	}
 }
 
+func TestFindPaths_WithPathLimits(t *testing.T) {
+	g := graph.New()
+
+	// Same 10-role chain as the max-depth test: unreachable at the default
+	// depth of 5, reachable when the caller raises the limit
+	roles := make([]*types.Principal, 10)
+	for i := 0; i < 10; i++ {
+		roles[i] = &types.Principal{
+			ARN:  fmt.Sprintf("arn:aws:iam::123456789012:role/Role%d", i),
+			Type: types.PrincipalTypeRole,
+			Name: fmt.Sprintf("Role%d", i),
+		}
+	}
+
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::test-bucket/*",
+		Type: types.ResourceTypeS3,
+		Name: "test-bucket",
+	}
+
+	for i := 0; i < 9; i++ {
+		roles[i+1].TrustPolicy = &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Action:    "sts:AssumeRole",
+					Principal: map[string]interface{}{"AWS": roles[i].ARN},
+				},
+			},
+		}
+	}
+
+	roles[9].Policies = []types.PolicyDocument{
+		{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:   types.EffectAllow,
+					Action:   "s3:GetObject",
+					Resource: bucket.ARN,
+				},
+			},
+		},
+	}
+
+	collection := &types.CollectionResult{
+		Principals: roles,
+		Resources:  []*types.Resource{bucket},
+	}
+	g, err := graph.Build(collection)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	e := New(g).WithPathLimits(10, 10)
+
+	paths, err := e.FindPaths(roles[0].ARN, bucket.ARN, "s3:GetObject")
+	if err != nil {
+		t.Fatalf("FindPaths() error = %v", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("FindPaths() with raised depth limit should find the 10-hop chain, got %d paths", len(paths))
+	}
+
+	// Values below 1 keep the defaults, so the chain stays out of reach
+	paths, err = New(g).WithPathLimits(0, 0).FindPaths(roles[0].ARN, bucket.ARN, "s3:GetObject")
+	if err != nil {
+		t.Fatalf("FindPaths() error = %v", err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("WithPathLimits(0, 0) should keep the default depth limit, got %d paths", len(paths))
+	}
+}
+
 func TestFindPaths_MultiplePaths(t *testing.T) {
 	// Alice can reach bucket via TWO different roles
 	alice := &types.Principal{
@@ -1325,3 +1548,615 @@ func TestFindHighRiskAccess_CrossRegionAccess(t *testing.T) {
 	// Full implementation requires collecting region metadata beyond ARN parsing
 	t.Skip("Cross-region detection limited by global IAM principals and S3 ARNs - requires region-tagged resources")
 }
+
+func TestWhoCanEach(t *testing.T) {
+	g := setupTestGraph()
+	e := New(g)
+
+	actions := []string{"s3:GetObject", "iam:CreateRole"}
+	results, err := e.WhoCanEach("arn:aws:s3:::any-bucket/*", actions)
+	if err != nil {
+		t.Fatalf("WhoCanEach() error = %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("WhoCanEach() returned %d results, want 2", len(results))
+	}
+
+	// Input order must be preserved for stable reports
+	if results[0].Action != "s3:GetObject" || results[1].Action != "iam:CreateRole" {
+		t.Errorf("WhoCanEach() order = [%s, %s], want input order", results[0].Action, results[1].Action)
+	}
+
+	// s3:GetObject: admin (*) and s3-user (s3:*)
+	if len(results[0].Principals) < 2 {
+		t.Errorf("WhoCanEach() found %d principals for s3:GetObject, want at least 2", len(results[0].Principals))
+	}
+
+	// iam:CreateRole: only admin
+	for _, p := range results[1].Principals {
+		if p.Name == "s3-user" || p.Name == "read-only" {
+			t.Errorf("WhoCanEach() incorrectly granted iam:CreateRole to %s", p.Name)
+		}
+	}
+}
+
+// scpWhoCanFixture builds a multi-account dataset with account-scoped SCPs:
+// account 1111 blocks s3:DeleteBucket, account 2222 has no restrictions
+func scpWhoCanFixture(t *testing.T, usersPerAccount int) *graph.Graph {
+	t.Helper()
+	g, err := graph.BuildMultiAccount(multiAccountSCPFixture(usersPerAccount))
+	if err != nil {
+		t.Fatalf("BuildMultiAccount() failed: %v", err)
+	}
+	return g
+}
+
+func multiAccountSCPFixture(usersPerAccount int) *types.MultiAccountCollectionResult {
+	policy := types.PolicyDocument{
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{
+				Effect:   types.EffectAllow,
+				Action:   "s3:DeleteBucket",
+				Resource: "arn:aws:s3:::shared-data",
+			},
+		},
+	}
+	makeAccount := func(accountID string, scps []types.PolicyDocument) *types.CollectionResult {
+		result := &types.CollectionResult{AccountID: accountID, SCPs: scps}
+		for i := 0; i < usersPerAccount; i++ {
+			result.Principals = append(result.Principals, &types.Principal{
+				ARN:       fmt.Sprintf("arn:aws:iam::%s:user/user-%d", accountID, i),
+				Type:      types.PrincipalTypeUser,
+				Name:      fmt.Sprintf("user-%d", i),
+				AccountID: accountID,
+				Policies:  []types.PolicyDocument{policy},
+			})
+		}
+		return result
+	}
+
+	restrictive := []types.PolicyDocument{
+		{
+			ID:      "deny-bucket-deletion",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{Effect: types.EffectAllow, Action: "*", Resource: "*"},
+				{Effect: types.EffectDeny, Action: "s3:DeleteBucket", Resource: "*"},
+			},
+		},
+	}
+
+	return &types.MultiAccountCollectionResult{
+		Accounts: map[string]*types.CollectionResult{
+			"111111111111": makeAccount("111111111111", restrictive),
+			"222222222222": makeAccount("222222222222", nil),
+		},
+	}
+}
+
+// TestWhoCan_SCPCacheMatchesPerPrincipalEvaluation verifies that the
+// memoized per-account SCP verdict yields exactly the results of evaluating
+// each principal individually
+func TestWhoCan_SCPCacheMatchesPerPrincipalEvaluation(t *testing.T) {
+	g := scpWhoCanFixture(t, 10)
+	engine := New(g)
+
+	results, err := engine.WhoCan("arn:aws:s3:::shared-data", "s3:DeleteBucket")
+	if err != nil {
+		t.Fatalf("WhoCan() failed: %v", err)
+	}
+
+	got := make(map[string]bool, len(results))
+	for _, p := range results {
+		got[p.ARN] = true
+	}
+
+	for _, principal := range g.GetAllPrincipals() {
+		want := g.CanAccess(principal.ARN, "s3:DeleteBucket", "arn:aws:s3:::shared-data")
+		if got[principal.ARN] != want {
+			t.Errorf("Principal %s: WhoCan=%v, CanAccess=%v", principal.ARN, got[principal.ARN], want)
+		}
+	}
+
+	// The SCP-restricted account contributes no principals, the open one all
+	if len(results) != 10 {
+		t.Errorf("Expected 10 principals (open account only), got %d", len(results))
+	}
+}
+
+// BenchmarkWhoCan_SCPHeavy measures the hot query across many principals in
+// SCP-governed accounts, where the per-account verdict cache pays off
+func BenchmarkWhoCan_SCPHeavy(b *testing.B) {
+	g, err := graph.BuildMultiAccount(multiAccountSCPFixture(500))
+	if err != nil {
+		b.Fatalf("BuildMultiAccount() failed: %v", err)
+	}
+	engine := New(g)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := engine.WhoCan("arn:aws:s3:::shared-data", "s3:DeleteBucket"); err != nil {
+			b.Fatalf("WhoCan() failed: %v", err)
+		}
+	}
+}
+
+func TestFindPublicAccess(t *testing.T) {
+	openBucket := &types.Resource{
+		ARN:  "arn:aws:s3:::open-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "open-bucket",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: "*",
+					Action:    []interface{}{"s3:GetObject", "s3:ListBucket"},
+					Resource:  "arn:aws:s3:::open-bucket/*",
+				},
+			},
+		},
+	}
+
+	// arn:aws:iam::*:root grants to every account's root - equally public
+	rootBucket := &types.Resource{
+		ARN:  "arn:aws:s3:::root-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "root-bucket",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: map[string]interface{}{"AWS": "arn:aws:iam::*:root"},
+					Action:    "s3:GetObject",
+					Resource:  "arn:aws:s3:::root-bucket/*",
+				},
+			},
+		},
+	}
+
+	// Wildcard principal scoped to the organization is not public
+	orgBucket := &types.Resource{
+		ARN:  "arn:aws:s3:::org-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "org-bucket",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: "*",
+					Action:    "s3:GetObject",
+					Resource:  "arn:aws:s3:::org-bucket/*",
+					Condition: map[string]map[string]interface{}{
+						"StringEquals": {"aws:PrincipalOrgID": "o-example123"},
+					},
+				},
+			},
+		},
+	}
+
+	// Specific-principal grant is not public either
+	privateBucket := &types.Resource{
+		ARN:  "arn:aws:s3:::private-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "private-bucket",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: map[string]interface{}{"AWS": "arn:aws:iam::123456789012:user/alice"},
+					Action:    "s3:GetObject",
+					Resource:  "arn:aws:s3:::private-bucket/*",
+				},
+			},
+		},
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		Resources: []*types.Resource{openBucket, rootBucket, orgBucket, privateBucket},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	results := New(g).FindPublicAccess()
+	if len(results) != 2 {
+		t.Fatalf("FindPublicAccess() returned %d resources, expected 2 (open-bucket and root-bucket)", len(results))
+	}
+
+	byARN := make(map[string][]string)
+	for _, r := range results {
+		byARN[r.Resource.ARN] = r.Actions
+	}
+
+	if actions := byARN["arn:aws:s3:::open-bucket"]; len(actions) != 2 {
+		t.Errorf("open-bucket exposed actions = %v, expected s3:GetObject and s3:ListBucket", actions)
+	}
+	if actions := byARN["arn:aws:s3:::root-bucket"]; len(actions) != 1 || actions[0] != "s3:GetObject" {
+		t.Errorf("root-bucket exposed actions = %v, expected [s3:GetObject]", actions)
+	}
+	if _, found := byARN["arn:aws:s3:::org-bucket"]; found {
+		t.Error("org-scoped bucket should not be reported as public")
+	}
+	if _, found := byARN["arn:aws:s3:::private-bucket"]; found {
+		t.Error("bucket granting a specific principal should not be reported as public")
+	}
+}
+
+func TestFindPaths_ECSTaskDefinition(t *testing.T) {
+	// Task role with access to a secret
+	taskRole := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:role/app-task-role",
+		Type: types.PrincipalTypeRole,
+		Name: "app-task-role",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "secretsmanager:GetSecretValue",
+						Resource: "arn:aws:secretsmanager:us-east-1:123456789012:secret:app-db",
+					},
+				},
+			},
+		},
+	}
+
+	secret := &types.Resource{
+		ARN:  "arn:aws:secretsmanager:us-east-1:123456789012:secret:app-db",
+		Type: types.ResourceTypeSecretsManager,
+		Name: "app-db",
+	}
+
+	taskDef := &types.Resource{
+		ARN:         "arn:aws:ecs:us-east-1:123456789012:task-definition/app:3",
+		Type:        types.ResourceTypeECS,
+		Name:        "app:3",
+		TaskRoleARN: taskRole.ARN,
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		Principals: []*types.Principal{taskRole},
+		Resources:  []*types.Resource{secret, taskDef},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	e := New(g)
+
+	// The task definition is a resource, but its task role linkage lets a
+	// path start from it and step through the role to the secret
+	paths, err := e.FindPaths(taskDef.ARN, secret.ARN, "secretsmanager:GetSecretValue")
+	if err != nil {
+		t.Fatalf("FindPaths() error = %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("FindPaths() should find a path from the task definition through its task role")
+	}
+
+	path := paths[0]
+	if path.From.ARN != taskDef.ARN {
+		t.Errorf("path.From.ARN = %s, want the task definition", path.From.ARN)
+	}
+	if len(path.Hops) != 2 {
+		t.Fatalf("Expected 2 hops (task role, then secret), got %d", len(path.Hops))
+	}
+	if hopRole, ok := path.Hops[0].To.(*types.Principal); !ok || hopRole.ARN != taskRole.ARN {
+		t.Errorf("First hop should target the task role, got %+v", path.Hops[0].To)
+	}
+
+	// A task definition with no role linkage stays unreachable as a start
+	bare := &types.Resource{
+		ARN:  "arn:aws:ecs:us-east-1:123456789012:task-definition/bare:1",
+		Type: types.ResourceTypeECS,
+		Name: "bare:1",
+	}
+	g.AddResource(bare)
+	if _, err := e.FindPaths(bare.ARN, secret.ARN, "secretsmanager:GetSecretValue"); err == nil {
+		t.Error("FindPaths() should error for a task definition with no role linkage")
+	}
+}
+
+func TestFindConfusedDeputyRisks(t *testing.T) {
+	// Service principal with no source-pinning condition - the risk
+	unpinnedBucket := &types.Resource{
+		ARN:  "arn:aws:s3:::logs-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "logs-bucket",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Sid:       "AllowCloudTrailWrite",
+					Effect:    types.EffectAllow,
+					Principal: map[string]interface{}{"Service": "cloudtrail.amazonaws.com"},
+					Action:    "s3:PutObject",
+					Resource:  "arn:aws:s3:::logs-bucket/*",
+				},
+			},
+		},
+	}
+
+	// Same grant pinned to the owning account - not a risk
+	pinnedBucket := &types.Resource{
+		ARN:  "arn:aws:s3:::pinned-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "pinned-bucket",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: map[string]interface{}{"Service": "cloudtrail.amazonaws.com"},
+					Action:    "s3:PutObject",
+					Resource:  "arn:aws:s3:::pinned-bucket/*",
+					Condition: map[string]map[string]interface{}{
+						"StringEquals": {"aws:SourceAccount": "123456789012"},
+					},
+				},
+			},
+		},
+	}
+
+	// Account-principal grant has no service principal to confuse
+	accountBucket := &types.Resource{
+		ARN:  "arn:aws:s3:::account-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "account-bucket",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: map[string]interface{}{"AWS": "arn:aws:iam::123456789012:root"},
+					Action:    "s3:GetObject",
+					Resource:  "arn:aws:s3:::account-bucket/*",
+				},
+			},
+		},
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		Resources: []*types.Resource{unpinnedBucket, pinnedBucket, accountBucket},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	findings := New(g).FindConfusedDeputyRisks()
+	if len(findings) != 1 {
+		t.Fatalf("FindConfusedDeputyRisks() returned %d findings, want 1: %+v", len(findings), findings)
+	}
+
+	f := findings[0]
+	if f.Resource.ARN != unpinnedBucket.ARN {
+		t.Errorf("finding resource = %s, want %s", f.Resource.ARN, unpinnedBucket.ARN)
+	}
+	if f.Sid != "AllowCloudTrailWrite" {
+		t.Errorf("finding Sid = %q, want AllowCloudTrailWrite", f.Sid)
+	}
+	if len(f.ServicePrincipals) != 1 || f.ServicePrincipals[0] != "cloudtrail.amazonaws.com" {
+		t.Errorf("finding ServicePrincipals = %v, want [cloudtrail.amazonaws.com]", f.ServicePrincipals)
+	}
+	if len(f.Actions) != 1 || f.Actions[0] != "s3:PutObject" {
+		t.Errorf("finding Actions = %v, want [s3:PutObject]", f.Actions)
+	}
+}
+
+func TestWhoCanFiltered(t *testing.T) {
+	g := graph.New()
+
+	user := &types.Principal{
+		ARN:       "arn:aws:iam::111111111111:user/alice",
+		Type:      types.PrincipalTypeUser,
+		Name:      "alice",
+		AccountID: "111111111111",
+	}
+	g.AddPrincipal(user)
+	g.AddEdge(user.ARN, "s3:GetObject", "*", false)
+
+	role := &types.Principal{
+		ARN:       "arn:aws:iam::222222222222:role/app",
+		Type:      types.PrincipalTypeRole,
+		Name:      "app",
+		AccountID: "222222222222",
+	}
+	g.AddPrincipal(role)
+	g.AddEdge(role.ARN, "s3:GetObject", "*", false)
+
+	e := New(g)
+
+	// An empty filter keeps current behavior
+	principals, err := e.WhoCanFiltered("arn:aws:s3:::data/*", "s3:GetObject", PrincipalFilter{})
+	if err != nil {
+		t.Fatalf("WhoCanFiltered() error = %v", err)
+	}
+	if len(principals) != 2 {
+		t.Fatalf("empty filter: got %d principals, want 2", len(principals))
+	}
+
+	// Filter by type
+	principals, err = e.WhoCanFiltered("arn:aws:s3:::data/*", "s3:GetObject", PrincipalFilter{Types: []string{"role"}})
+	if err != nil {
+		t.Fatalf("WhoCanFiltered() error = %v", err)
+	}
+	if len(principals) != 1 || principals[0].Name != "app" {
+		t.Errorf("type filter: got %v, want only role app", principals)
+	}
+
+	// Filter by account (with surrounding whitespace, as from a comma split)
+	principals, err = e.WhoCanFiltered("arn:aws:s3:::data/*", "s3:GetObject", PrincipalFilter{Accounts: []string{" 111111111111 "}})
+	if err != nil {
+		t.Fatalf("WhoCanFiltered() error = %v", err)
+	}
+	if len(principals) != 1 || principals[0].Name != "alice" {
+		t.Errorf("account filter: got %v, want only alice", principals)
+	}
+
+	// Both dimensions must match
+	principals, err = e.WhoCanFiltered("arn:aws:s3:::data/*", "s3:GetObject", PrincipalFilter{
+		Types:    []string{"user"},
+		Accounts: []string{"222222222222"},
+	})
+	if err != nil {
+		t.Fatalf("WhoCanFiltered() error = %v", err)
+	}
+	if len(principals) != 0 {
+		t.Errorf("combined filter: got %v, want none", principals)
+	}
+
+	// Unknown types produce a clear error
+	if _, err := e.WhoCanFiltered("arn:aws:s3:::data/*", "s3:GetObject", PrincipalFilter{Types: []string{"iam"}}); err == nil {
+		t.Error("expected error for unknown principal type")
+	}
+}
+
+func TestWhoCanDetailedFiltered(t *testing.T) {
+	g := setupTestGraph()
+	e := New(g)
+
+	decisions, err := e.WhoCanDetailedFiltered("arn:aws:s3:::any-bucket/*", "s3:GetObject", PrincipalFilter{Types: []string{"user"}})
+	if err != nil {
+		t.Fatalf("WhoCanDetailedFiltered() error = %v", err)
+	}
+	for _, decision := range decisions {
+		if decision.Principal.Type != types.PrincipalTypeUser {
+			t.Errorf("filter leaked principal of type %s", decision.Principal.Type)
+		}
+	}
+	if len(decisions) == 0 {
+		t.Error("expected at least one user decision")
+	}
+}
+
+// assumePathFixture builds a single-account graph where alice can reach the
+// prod bucket only by assuming DataRole; scps, when non-nil, govern the
+// whole account
+func assumePathFixture(t *testing.T, scps []types.PolicyDocument) *graph.Graph {
+	t.Helper()
+
+	alice := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/alice",
+		Type:      types.PrincipalTypeUser,
+		Name:      "alice",
+		AccountID: "123456789012",
+	}
+
+	dataRole := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:role/DataRole",
+		Type:      types.PrincipalTypeRole,
+		Name:      "DataRole",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "s3:GetObject",
+						Resource: "arn:aws:s3:::prod-data/*",
+					},
+				},
+			},
+		},
+		TrustPolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect: types.EffectAllow,
+					Principal: map[string]interface{}{
+						"AWS": alice.ARN,
+					},
+					Action: "sts:AssumeRole",
+				},
+			},
+		},
+	}
+
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::prod-data/*",
+		Type: types.ResourceTypeS3,
+		Name: "prod-data",
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{alice, dataRole},
+		Resources:  []*types.Resource{bucket},
+		SCPs:       scps,
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	return g
+}
+
+// denyAllExcept builds a restrictive SCP set: everything is allow-listed,
+// then the given action is explicitly denied
+func denyAllExcept(action string) []types.PolicyDocument {
+	return []types.PolicyDocument{
+		{
+			ID:      "restrictive",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{Effect: types.EffectAllow, Action: "*", Resource: "*"},
+				{Effect: types.EffectDeny, Action: action, Resource: "*"},
+			},
+		},
+	}
+}
+
+func TestFindPaths_SCPBlocksFinalAction(t *testing.T) {
+	// Sanity check: with no SCP the assume-role path exists
+	e := New(assumePathFixture(t, nil))
+	paths, err := e.FindPaths(
+		"arn:aws:iam::123456789012:user/alice",
+		"arn:aws:s3:::prod-data/*",
+		"s3:GetObject",
+	)
+	if err != nil {
+		t.Fatalf("FindPaths() error = %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("FindPaths() should find the assume-role path without an SCP")
+	}
+
+	// An SCP denying the final action trumps the role's identity allow
+	e = New(assumePathFixture(t, denyAllExcept("s3:GetObject")))
+	paths, err = e.FindPaths(
+		"arn:aws:iam::123456789012:user/alice",
+		"arn:aws:s3:::prod-data/*",
+		"s3:GetObject",
+	)
+	if err != nil {
+		t.Fatalf("FindPaths() error = %v", err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("FindPaths() returned %d paths, want 0 when an SCP denies the final action", len(paths))
+	}
+}
+
+func TestFindPaths_SCPBlocksAssumeRoleHop(t *testing.T) {
+	// The trust policy still admits alice, but the SCP denies sts:AssumeRole,
+	// so the hop must be pruned before the role's permissions are explored
+	e := New(assumePathFixture(t, denyAllExcept("sts:AssumeRole")))
+	paths, err := e.FindPaths(
+		"arn:aws:iam::123456789012:user/alice",
+		"arn:aws:s3:::prod-data/*",
+		"s3:GetObject",
+	)
+	if err != nil {
+		t.Fatalf("FindPaths() error = %v", err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("FindPaths() returned %d paths, want 0 when an SCP denies sts:AssumeRole", len(paths))
+	}
+}
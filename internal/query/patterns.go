@@ -2,6 +2,8 @@ package query
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pfrederiksen/aws-access-map/pkg/types"
 )
@@ -62,9 +64,134 @@ func (e *Engine) FindHighRiskAccess() ([]HighRiskFinding, error) {
 	missingPolicyFindings := e.findResourcesWithoutPolicies()
 	findings = append(findings, missingPolicyFindings...)
 
+	// Pattern 14: Resource policies granting access to nonexistent principals
+	deadGrantFindings := e.findDeadResourcePolicyGrants()
+	findings = append(findings, deadGrantFindings...)
+
+	// Pattern 15: Trust policy statements that cannot grant assumption
+	trustActionFindings := e.findMismatchedTrustActions()
+	findings = append(findings, trustActionFindings...)
+
+	// Pattern 16: Principals that can modify their own permissions
+	selfMutationFindings := e.findSelfPermissionMutation()
+	findings = append(findings, selfMutationFindings...)
+
+	// Pattern 17: iam:PassRole combined with a compute launch action
+	passRoleFindings := e.findPassRoleToCompute()
+	findings = append(findings, passRoleFindings...)
+
+	// Pattern 18: open subscribe/send grants on messaging resources
+	messagingFindings := e.findOpenMessagingPolicies()
+	findings = append(findings, messagingFindings...)
+
 	return findings, nil
 }
 
+// findMismatchedTrustActions surfaces trust policy statements recorded during
+// graph construction whose Action is not an STS assume action - the role looks
+// assumable in the policy but never will be
+func (e *Engine) findMismatchedTrustActions() []HighRiskFinding {
+	var findings []HighRiskFinding
+
+	for _, issue := range e.graph.GetTrustPolicyIssues() {
+		principal, _ := e.graph.GetPrincipal(issue.RoleARN)
+
+		detail := issue.Reason
+		if len(issue.Actions) > 0 {
+			detail = fmt.Sprintf("%s (Action: %s)", issue.Reason, strings.Join(issue.Actions, ", "))
+		}
+		sid := ""
+		if issue.Sid != "" {
+			sid = fmt.Sprintf(" (Sid: %s)", issue.Sid)
+		}
+
+		findings = append(findings, HighRiskFinding{
+			Type:        "Mismatched Trust Policy Action",
+			Severity:    "MEDIUM",
+			Description: fmt.Sprintf("Role '%s' has a trust statement%s that cannot grant assumption: %s", issue.RoleARN, sid, detail),
+			Principal:   principal,
+			Action:      "sts:AssumeRole",
+		})
+	}
+
+	return findings
+}
+
+// findSelfPermissionMutation detects principals that can edit their own
+// attached policies, inline policies, or boundary - a direct self-escalation
+// path even when the current grants look narrow
+func (e *Engine) findSelfPermissionMutation() []HighRiskFinding {
+	var findings []HighRiskFinding
+
+	// Self-mutating IAM actions by principal type
+	userActions := []string{
+		"iam:PutUserPolicy",
+		"iam:AttachUserPolicy",
+		"iam:DeleteUserPermissionsBoundary",
+	}
+	roleActions := []string{
+		"iam:PutRolePolicy",
+		"iam:AttachRolePolicy",
+		"iam:DeleteRolePermissionsBoundary",
+		"iam:UpdateAssumeRolePolicy",
+	}
+
+	for _, principal := range e.graph.GetAllPrincipals() {
+		var actions []string
+		var label string
+		switch principal.Type {
+		case types.PrincipalTypeUser:
+			actions = userActions
+			label = "User"
+		case types.PrincipalTypeRole:
+			actions = roleActions
+			label = "Role"
+		default:
+			continue
+		}
+
+		// Track the broadest scope among the self-grants: a wildcard grant
+		// also covers every other principal, not just this one
+		var foundActions []string
+		broad := false
+		for _, action := range actions {
+			if !e.graph.CanAccess(principal.ARN, action, principal.ARN) {
+				continue
+			}
+			foundActions = append(foundActions, action)
+			if e.graph.CanAccess(principal.ARN, action, "*") {
+				broad = true
+			}
+		}
+
+		if len(foundActions) == 0 {
+			continue
+		}
+
+		actionStr := foundActions[0]
+		if len(foundActions) > 1 {
+			actionStr = fmt.Sprintf("%s (+%d more)", actionStr, len(foundActions)-1)
+		}
+
+		severity := "HIGH"
+		scope := "its own ARN"
+		if broad {
+			severity = "CRITICAL"
+			scope = "a wildcard resource"
+		}
+
+		findings = append(findings, HighRiskFinding{
+			Type:        "Self Permission Modification",
+			Severity:    severity,
+			Description: fmt.Sprintf("%s '%s' can modify its own permissions via %s scoped to %s", label, principal.Name, actionStr, scope),
+			Principal:   principal,
+			Action:      actionStr,
+		})
+	}
+
+	return findings
+}
+
 // findAdminAccess detects principals with unrestricted admin access
 func (e *Engine) findAdminAccess() []HighRiskFinding {
 	var findings []HighRiskFinding
@@ -387,6 +514,332 @@ func (e *Engine) findWildcardResourcePolicies() []HighRiskFinding {
 	return findings
 }
 
+// findOpenMessagingPolicies detects SNS topics and SQS queues whose resource
+// policy grants subscribe/send to a wildcard principal. Open messaging grants
+// get their own finding type because the abuse (spam, message injection) is
+// distinct from data exposure. Grants scoped by an account, org, or SourceArn
+// condition are downgraded rather than dropped - the scope key is doing the
+// access control the principal element isn't.
+func (e *Engine) findOpenMessagingPolicies() []HighRiskFinding {
+	var findings []HighRiskFinding
+
+	// Actions that let an outside party inject or attach to the resource
+	messagingActions := map[string]bool{
+		"sns:Subscribe":   true,
+		"sns:Publish":     true,
+		"sqs:SendMessage": true,
+	}
+
+	// Condition keys that meaningfully scope a wildcard-principal grant
+	scopingKeys := map[string]bool{
+		"aws:sourcearn":      true,
+		"aws:sourceaccount":  true,
+		"aws:sourceowner":    true,
+		"aws:principalorgid": true,
+		"aws:principalaccount": true,
+	}
+
+	for _, resource := range e.graph.GetAllResources() {
+		if resource.Type != types.ResourceTypeSNS && resource.Type != types.ResourceTypeSQS {
+			continue
+		}
+		if resource.ResourcePolicy == nil {
+			continue
+		}
+
+		for _, stmt := range resource.ResourcePolicy.Statements {
+			if stmt.Effect != types.EffectAllow || !statementHasWildcardPrincipal(stmt) {
+				continue
+			}
+
+			var grantedActions []string
+			for _, action := range statementActions(stmt) {
+				if messagingActions[action] || action == "*" ||
+					action == "sns:*" || action == "sqs:*" {
+					grantedActions = append(grantedActions, action)
+				}
+			}
+			if len(grantedActions) == 0 {
+				continue
+			}
+
+			scoped := false
+			for _, operands := range stmt.Condition {
+				for key := range operands {
+					if scopingKeys[strings.ToLower(key)] {
+						scoped = true
+					}
+				}
+			}
+
+			actionStr := strings.Join(grantedActions, ", ")
+			if scoped {
+				findings = append(findings, HighRiskFinding{
+					Type:        "Open Messaging Policy",
+					Severity:    "LOW",
+					Description: fmt.Sprintf("Resource '%s' (%s) allows %s to any principal, scoped only by a source/account condition", resource.Name, resource.Type, actionStr),
+					Resource:    resource,
+					Action:      actionStr,
+				})
+			} else {
+				findings = append(findings, HighRiskFinding{
+					Type:        "Open Messaging Policy",
+					Severity:    "HIGH",
+					Description: fmt.Sprintf("Resource '%s' (%s) allows %s to any principal with no scoping condition", resource.Name, resource.Type, actionStr),
+					Resource:    resource,
+					Action:      actionStr,
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+// statementHasWildcardPrincipal reports whether a statement's Principal is
+// "*" directly or via {"AWS": "*"} (including list forms)
+func statementHasWildcardPrincipal(stmt types.Statement) bool {
+	switch p := stmt.Principal.(type) {
+	case string:
+		return p == "*"
+	case map[string]interface{}:
+		for _, value := range p {
+			switch v := value.(type) {
+			case string:
+				if v == "*" {
+					return true
+				}
+			case []interface{}:
+				for _, item := range v {
+					if str, ok := item.(string); ok && str == "*" {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// statementActions collects a statement's Action element into a string slice,
+// tolerating the single-string and list JSON forms
+func statementActions(stmt types.Statement) []string {
+	var actions []string
+	switch a := stmt.Action.(type) {
+	case string:
+		actions = append(actions, a)
+	case []interface{}:
+		for _, item := range a {
+			if str, ok := item.(string); ok {
+				actions = append(actions, str)
+			}
+		}
+	case []string:
+		actions = append(actions, a...)
+	}
+	return actions
+}
+
+// PublicResource pairs a publicly accessible resource with the actions its
+// resource policy exposes to anonymous callers
+type PublicResource struct {
+	Resource *types.Resource `json:"resource"`
+	Actions  []string        `json:"actions"`
+}
+
+// audienceRestrictingKeys are condition keys that narrow a wildcard-principal
+// grant to the organization, a specific caller, or a network boundary - a
+// statement carrying one is scoped, not truly public
+var audienceRestrictingKeys = map[string]bool{
+	"aws:principalorgid":    true,
+	"aws:principalorgpaths": true,
+	"aws:principalaccount":  true,
+	"aws:principalarn":      true,
+	"aws:sourcearn":         true,
+	"aws:sourceaccount":     true,
+	"aws:sourceowner":       true,
+	"aws:sourcevpc":         true,
+	"aws:sourcevpce":        true,
+	"aws:sourceip":          true,
+}
+
+// FindPublicAccess returns resources whose resource policy grants access to
+// everyone - principal "*" or the equivalent arn:aws:iam::*:root - without a
+// condition restricting the audience (aws:PrincipalOrgID, aws:SourceArn and
+// friends). Each result lists the actions exposed publicly, so reports can
+// show not just that a resource is open but what the public can do to it
+func (e *Engine) FindPublicAccess() []PublicResource {
+	var results []PublicResource
+
+	for _, resource := range e.graph.GetAllResources() {
+		if resource.ResourcePolicy == nil {
+			continue
+		}
+
+		var actions []string
+		seen := make(map[string]bool)
+		for _, stmt := range resource.ResourcePolicy.Statements {
+			if stmt.Effect != types.EffectAllow || !statementHasPublicPrincipal(stmt) {
+				continue
+			}
+			if conditionRestrictsAudience(stmt.Condition) {
+				continue
+			}
+			for _, action := range statementActions(stmt) {
+				if !seen[action] {
+					seen[action] = true
+					actions = append(actions, action)
+				}
+			}
+		}
+
+		if len(actions) > 0 {
+			results = append(results, PublicResource{Resource: resource, Actions: actions})
+		}
+	}
+
+	return results
+}
+
+// statementHasPublicPrincipal extends statementHasWildcardPrincipal with the
+// arn:aws:iam::*:root form, which grants to every account's root
+func statementHasPublicPrincipal(stmt types.Statement) bool {
+	isPublic := func(s string) bool {
+		return s == "*" || s == "arn:aws:iam::*:root"
+	}
+
+	switch p := stmt.Principal.(type) {
+	case string:
+		return isPublic(p)
+	case map[string]interface{}:
+		for _, value := range p {
+			switch v := value.(type) {
+			case string:
+				if isPublic(v) {
+					return true
+				}
+			case []interface{}:
+				for _, item := range v {
+					if str, ok := item.(string); ok && isPublic(str) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// conditionRestrictsAudience reports whether a condition block narrows who a
+// wildcard-principal statement actually applies to
+func conditionRestrictsAudience(condition map[string]map[string]interface{}) bool {
+	for _, operands := range condition {
+		for key := range operands {
+			if audienceRestrictingKeys[strings.ToLower(key)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ConfusedDeputyRisk flags a resource-policy statement that grants access to
+// a service principal without pinning which customer the service must be
+// acting for. Without aws:SourceAccount or aws:SourceArn, another account's
+// use of the same service can be routed through it to this resource - the
+// classic confused-deputy pattern
+type ConfusedDeputyRisk struct {
+	Resource          *types.Resource `json:"resource"`
+	ServicePrincipals []string        `json:"service_principals"`
+	Sid               string          `json:"sid,omitempty"`
+	Actions           []string        `json:"actions"`
+}
+
+// sourcePinningKeys are condition keys that tie a service-principal grant to
+// a specific originating account or resource, defusing the confused deputy
+var sourcePinningKeys = map[string]bool{
+	"aws:sourcearn":     true,
+	"aws:sourceaccount": true,
+	"aws:sourceowner":   true,
+}
+
+// FindConfusedDeputyRisks returns one finding per Allow statement that grants
+// access to a service principal without an aws:SourceAccount/aws:SourceArn
+// condition restricting which account's resources the service may act for
+func (e *Engine) FindConfusedDeputyRisks() []ConfusedDeputyRisk {
+	var results []ConfusedDeputyRisk
+
+	for _, resource := range e.graph.GetAllResources() {
+		if resource.ResourcePolicy == nil {
+			continue
+		}
+
+		for _, stmt := range resource.ResourcePolicy.Statements {
+			if stmt.Effect != types.EffectAllow {
+				continue
+			}
+			services := statementServicePrincipals(stmt)
+			if len(services) == 0 || conditionPinsSource(stmt.Condition) {
+				continue
+			}
+			results = append(results, ConfusedDeputyRisk{
+				Resource:          resource,
+				ServicePrincipals: services,
+				Sid:               stmt.Sid,
+				Actions:           statementActions(stmt),
+			})
+		}
+	}
+
+	return results
+}
+
+// statementServicePrincipals extracts the service principals a statement
+// grants to: the {"Service": ...} map form and the bare service hostname form
+func statementServicePrincipals(stmt types.Statement) []string {
+	isService := func(s string) bool {
+		return !strings.HasPrefix(s, "arn:") && strings.HasSuffix(s, ".amazonaws.com")
+	}
+
+	var services []string
+	switch p := stmt.Principal.(type) {
+	case string:
+		if isService(p) {
+			services = append(services, p)
+		}
+	case map[string]interface{}:
+		for key, value := range p {
+			if !strings.EqualFold(key, "Service") {
+				continue
+			}
+			switch v := value.(type) {
+			case string:
+				services = append(services, v)
+			case []interface{}:
+				for _, item := range v {
+					if s, ok := item.(string); ok {
+						services = append(services, s)
+					}
+				}
+			}
+		}
+	}
+	return services
+}
+
+// conditionPinsSource reports whether a condition block ties a grant to a
+// specific source account or resource
+func conditionPinsSource(condition map[string]map[string]interface{}) bool {
+	for _, operands := range condition {
+		for key := range operands {
+			if sourcePinningKeys[strings.ToLower(key)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // findServiceRoleEscalation detects service roles that can escalate privileges
 func (e *Engine) findServiceRoleEscalation() []HighRiskFinding {
 	var findings []HighRiskFinding
@@ -435,6 +888,59 @@ func (e *Engine) findServiceRoleEscalation() []HighRiskFinding {
 	return findings
 }
 
+// findPassRoleToCompute detects principals that hold iam:PassRole together
+// with a compute launch action - the classic escalation where a privileged
+// role is passed to a new instance, function, or task the principal controls
+func (e *Engine) findPassRoleToCompute() []HighRiskFinding {
+	var findings []HighRiskFinding
+
+	// Launch actions that accept a passed role
+	computeActions := []string{
+		"ec2:RunInstances",
+		"lambda:CreateFunction",
+		"ecs:RunTask",
+		"glue:CreateJob",
+		"sagemaker:CreateNotebookInstance",
+	}
+
+	for _, principal := range e.graph.GetAllPrincipals() {
+		// Groups cannot make API calls themselves; their members are
+		// evaluated directly
+		if principal.Type != types.PrincipalTypeUser && principal.Type != types.PrincipalTypeRole {
+			continue
+		}
+
+		if !e.graph.CanAccess(principal.ARN, "iam:PassRole", "*") {
+			continue
+		}
+
+		var foundActions []string
+		for _, action := range computeActions {
+			if e.graph.CanAccess(principal.ARN, action, "*") {
+				foundActions = append(foundActions, action)
+			}
+		}
+		if len(foundActions) == 0 {
+			continue
+		}
+
+		actionStr := foundActions[0]
+		if len(foundActions) > 1 {
+			actionStr = fmt.Sprintf("%s (+%d more)", actionStr, len(foundActions)-1)
+		}
+
+		findings = append(findings, HighRiskFinding{
+			Type:        "PassRole to Compute Service",
+			Severity:    "HIGH",
+			Description: fmt.Sprintf("%s '%s' can pass roles to compute services it launches (%s), allowing escalation to any passable role's permissions", principal.Type, principal.Name, actionStr),
+			Principal:   principal,
+			Action:      actionStr,
+		})
+	}
+
+	return findings
+}
+
 // findMissingMFAForPrivilegedUsers detects privileged users without MFA requirements
 func (e *Engine) findMissingMFAForPrivilegedUsers() []HighRiskFinding {
 	var findings []HighRiskFinding
@@ -664,6 +1170,91 @@ func (e *Engine) findResourcesWithoutPolicies() []HighRiskFinding {
 	return findings
 }
 
+// findDeadResourcePolicyGrants detects resource policies granting access to
+// principal ARNs that don't resolve to any collected principal (deleted users/roles)
+func (e *Engine) findDeadResourcePolicyGrants() []HighRiskFinding {
+	var findings []HighRiskFinding
+
+	for _, resource := range e.graph.GetAllResources() {
+		if resource.ResourcePolicy == nil {
+			continue
+		}
+
+		// Track ARNs per resource so the same dead grant isn't reported twice
+		reported := make(map[string]bool)
+
+		for _, stmt := range resource.ResourcePolicy.Statements {
+			for _, principalARN := range extractAWSPrincipalARNs(stmt.Principal) {
+				if reported[principalARN] {
+					continue
+				}
+
+				if _, ok := e.graph.GetPrincipal(principalARN); ok {
+					continue
+				}
+
+				reported[principalARN] = true
+				findings = append(findings, HighRiskFinding{
+					Type:        "Dead Resource Policy Grant",
+					Severity:    "LOW",
+					Description: fmt.Sprintf("Resource '%s' (%s) grants access to principal '%s' which does not exist in the collected inventory", resource.Name, resource.Type, principalARN),
+					Resource:    resource,
+					Action:      "",
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+// extractAWSPrincipalARNs returns the IAM user/role ARNs referenced in a
+// statement's Principal element. Wildcard, service, federated, and
+// account-level (root / bare account ID) principals are skipped since they
+// don't correspond to individual collected principals.
+func extractAWSPrincipalARNs(principal interface{}) []string {
+	var arns []string
+
+	collect := func(value string) {
+		// Only individual IAM users/roles can go stale; account-level and
+		// wildcard grants are checked by other patterns
+		if value == "*" || !containsIgnoreCase(value, "arn:aws") {
+			return
+		}
+		parts := splitARN(value)
+		if len(parts) < 6 || parts[2] != "iam" {
+			return
+		}
+		resource := parts[5]
+		if resource == "root" {
+			return
+		}
+		if !containsIgnoreCase(resource, "user/") && !containsIgnoreCase(resource, "role/") {
+			return
+		}
+		arns = append(arns, value)
+	}
+
+	switch p := principal.(type) {
+	case string:
+		collect(p)
+	case map[string]interface{}:
+		// Only the "AWS" key holds IAM principals; Service/Federated are skipped
+		switch v := p["AWS"].(type) {
+		case string:
+			collect(v)
+		case []interface{}:
+			for _, item := range v {
+				if str, ok := item.(string); ok {
+					collect(str)
+				}
+			}
+		}
+	}
+
+	return arns
+}
+
 // Helper function to check if string contains substring (case-insensitive)
 func containsIgnoreCase(s, substr string) bool {
 	sLower := ""
@@ -705,3 +1296,97 @@ func splitARN(arn string) []string {
 	result = append(result, current)
 	return result
 }
+
+// FindUnusedPrivilegedRoles flags roles that hold powerful permissions but
+// have not been assumed within the given number of days - prime cleanup
+// candidates. Roles with no last-used data are treated as never used. The
+// threshold comes from the caller (--unused-days), so this runs separately
+// from FindHighRiskAccess.
+func (e *Engine) FindUnusedPrivilegedRoles(unusedDays int) []HighRiskFinding {
+	var findings []HighRiskFinding
+	cutoff := time.Now().AddDate(0, 0, -unusedDays)
+
+	// Ordered so the reported privilege is deterministic when several apply
+	privilegedActions := []struct {
+		action      string
+		description string
+	}{
+		{"iam:*", "full IAM access"},
+		{"kms:Decrypt", "KMS decryption access"},
+		{"secretsmanager:GetSecretValue", "secrets retrieval access"},
+		{"sts:AssumeRole", "role assumption access"},
+	}
+
+	for _, principal := range e.graph.GetAllPrincipals() {
+		if principal.Type != types.PrincipalTypeRole {
+			continue
+		}
+
+		// Skip roles assumed within the threshold
+		if principal.RoleLastUsed != nil && principal.RoleLastUsed.After(cutoff) {
+			continue
+		}
+
+		privilege := ""
+		severity := ""
+		if e.graph.CanAccess(principal.ARN, "*", "*") {
+			privilege = "unrestricted admin access"
+			severity = "HIGH"
+		} else {
+			for _, candidate := range privilegedActions {
+				if e.graph.CanAccess(principal.ARN, candidate.action, "*") {
+					privilege = candidate.description
+					severity = "MEDIUM"
+					break
+				}
+			}
+		}
+		if privilege == "" {
+			continue // Unused but not privileged; not worth a finding
+		}
+
+		usage := "has never been assumed"
+		if principal.RoleLastUsed != nil {
+			usage = fmt.Sprintf("was last assumed %s", principal.RoleLastUsed.Format("2006-01-02"))
+		}
+
+		findings = append(findings, HighRiskFinding{
+			Type:        "Unused Privileged Role",
+			Severity:    severity,
+			Description: fmt.Sprintf("Role '%s' has %s but %s (threshold: %d days)", principal.Name, privilege, usage, unusedDays),
+			Principal:   principal,
+		})
+	}
+
+	return findings
+}
+
+// FindUnusedManagedPolicies flags customer-managed policies attached to zero
+// principals - dead weight that widens the review surface without granting
+// anything. Cross-references the collected inventory against the policies
+// actually attached to principals in the graph, and trusts AWS's own
+// attachment count to cover principals outside the collected set.
+func (e *Engine) FindUnusedManagedPolicies() []HighRiskFinding {
+	attached := make(map[string]bool)
+	for _, principal := range e.graph.GetAllPrincipals() {
+		for _, doc := range principal.Policies {
+			if doc.SourcePolicyARN != "" {
+				attached[doc.SourcePolicyARN] = true
+			}
+		}
+	}
+
+	var findings []HighRiskFinding
+	for _, mp := range e.graph.GetManagedPolicies() {
+		if attached[mp.ARN] || mp.AttachmentCount > 0 {
+			continue
+		}
+		findings = append(findings, HighRiskFinding{
+			Type:        "Unused Managed Policy",
+			Severity:    "LOW",
+			Description: fmt.Sprintf("Customer-managed policy '%s' (%s) is attached to zero principals", mp.Name, mp.ARN),
+		})
+	}
+
+	return findings
+}
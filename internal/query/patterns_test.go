@@ -0,0 +1,560 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestFindMismatchedTrustActions(t *testing.T) {
+	role := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:role/BrokenRole",
+		Type:      types.PrincipalTypeRole,
+		Name:      "BrokenRole",
+		AccountID: "123456789012",
+		TrustPolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect: types.EffectAllow,
+					Principal: map[string]interface{}{
+						"AWS": "arn:aws:iam::123456789012:user/alice",
+					},
+					Action: "s3:GetObject",
+				},
+			},
+		},
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{role},
+	})
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+
+	findings := New(g).findMismatchedTrustActions()
+
+	if len(findings) != 1 {
+		t.Fatalf("findMismatchedTrustActions() returned %d findings, want 1", len(findings))
+	}
+	if findings[0].Type != "Mismatched Trust Policy Action" {
+		t.Errorf("finding type = %s, want Mismatched Trust Policy Action", findings[0].Type)
+	}
+	if findings[0].Principal == nil || findings[0].Principal.ARN != role.ARN {
+		t.Errorf("finding principal = %v, want %s", findings[0].Principal, role.ARN)
+	}
+}
+
+func TestFindSelfPermissionMutation(t *testing.T) {
+	selfEditor := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/self-editor",
+		Type:      types.PrincipalTypeUser,
+		Name:      "self-editor",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "iam:PutUserPolicy",
+						Resource: "arn:aws:iam::123456789012:user/self-editor",
+					},
+				},
+			},
+		},
+	}
+	broadEditor := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:role/iam-admin",
+		Type:      types.PrincipalTypeRole,
+		Name:      "iam-admin",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "iam:AttachRolePolicy",
+						Resource: "*",
+					},
+				},
+			},
+		},
+	}
+	bystander := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/reader",
+		Type:      types.PrincipalTypeUser,
+		Name:      "reader",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect: types.EffectAllow,
+						Action: "iam:PutUserPolicy",
+						// Scoped to someone else, so not a self-grant
+						Resource: "arn:aws:iam::123456789012:user/other",
+					},
+				},
+			},
+		},
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{selfEditor, broadEditor, bystander},
+	})
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+
+	findings := New(g).findSelfPermissionMutation()
+
+	if len(findings) != 2 {
+		t.Fatalf("findSelfPermissionMutation() returned %d findings, want 2", len(findings))
+	}
+
+	bySeverity := map[string]HighRiskFinding{}
+	for _, f := range findings {
+		if f.Type != "Self Permission Modification" {
+			t.Errorf("finding type = %s, want Self Permission Modification", f.Type)
+		}
+		bySeverity[f.Severity] = f
+	}
+
+	// Self-scoped grant is HIGH, wildcard grant is CRITICAL
+	if f, ok := bySeverity["HIGH"]; !ok || f.Principal.ARN != selfEditor.ARN {
+		t.Errorf("Expected HIGH finding for %s, got %+v", selfEditor.ARN, bySeverity["HIGH"])
+	}
+	if f, ok := bySeverity["CRITICAL"]; !ok || f.Principal.ARN != broadEditor.ARN {
+		t.Errorf("Expected CRITICAL finding for %s, got %+v", broadEditor.ARN, bySeverity["CRITICAL"])
+	}
+}
+
+func TestFindDeadResourcePolicyGrants(t *testing.T) {
+	alice := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/alice",
+		Type:      types.PrincipalTypeUser,
+		Name:      "alice",
+		AccountID: "123456789012",
+	}
+
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::data-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "data-bucket",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect: types.EffectAllow,
+					Principal: map[string]interface{}{
+						"AWS": []interface{}{
+							alice.ARN,
+							"arn:aws:iam::123456789012:role/DeletedRole",
+						},
+					},
+					Action:   "s3:GetObject",
+					Resource: "arn:aws:s3:::data-bucket/*",
+				},
+				{
+					// Service and account-level principals must not be flagged
+					Effect: types.EffectAllow,
+					Principal: map[string]interface{}{
+						"Service": "lambda.amazonaws.com",
+					},
+					Action:   "s3:GetObject",
+					Resource: "arn:aws:s3:::data-bucket/*",
+				},
+			},
+		},
+	}
+
+	collection := &types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{alice},
+		Resources:  []*types.Resource{bucket},
+	}
+
+	g, err := graph.Build(collection)
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+
+	engine := New(g)
+	findings := engine.findDeadResourcePolicyGrants()
+
+	if len(findings) != 1 {
+		t.Fatalf("findDeadResourcePolicyGrants() returned %d findings, want 1", len(findings))
+	}
+
+	finding := findings[0]
+	if finding.Type != "Dead Resource Policy Grant" {
+		t.Errorf("finding type = %s, want Dead Resource Policy Grant", finding.Type)
+	}
+	if finding.Resource == nil || finding.Resource.ARN != bucket.ARN {
+		t.Errorf("finding resource = %v, want %s", finding.Resource, bucket.ARN)
+	}
+}
+
+func TestExtractAWSPrincipalARNs(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal interface{}
+		want      int
+	}{
+		{
+			name:      "single user ARN string",
+			principal: "arn:aws:iam::123456789012:user/alice",
+			want:      1,
+		},
+		{
+			name:      "wildcard skipped",
+			principal: "*",
+			want:      0,
+		},
+		{
+			name: "AWS map with role ARN",
+			principal: map[string]interface{}{
+				"AWS": "arn:aws:iam::123456789012:role/AppRole",
+			},
+			want: 1,
+		},
+		{
+			name: "root principal skipped",
+			principal: map[string]interface{}{
+				"AWS": "arn:aws:iam::123456789012:root",
+			},
+			want: 0,
+		},
+		{
+			name: "service principal skipped",
+			principal: map[string]interface{}{
+				"Service": "ec2.amazonaws.com",
+			},
+			want: 0,
+		},
+		{
+			name: "federated principal skipped",
+			principal: map[string]interface{}{
+				"Federated": "arn:aws:iam::123456789012:saml-provider/Okta",
+			},
+			want: 0,
+		},
+		{
+			name: "mixed list",
+			principal: map[string]interface{}{
+				"AWS": []interface{}{
+					"arn:aws:iam::123456789012:user/alice",
+					"arn:aws:iam::123456789012:role/AppRole",
+					"*",
+					"123456789012",
+				},
+			},
+			want: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractAWSPrincipalARNs(tt.principal)
+			if len(got) != tt.want {
+				t.Errorf("extractAWSPrincipalARNs() returned %d ARNs (%v), want %d", len(got), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindUnusedPrivilegedRoles(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -180)
+	recent := time.Now().AddDate(0, 0, -5)
+
+	staleAdmin := &types.Principal{
+		ARN:          "arn:aws:iam::123456789012:role/StaleAdmin",
+		Type:         types.PrincipalTypeRole,
+		Name:         "StaleAdmin",
+		RoleLastUsed: &old,
+	}
+	neverUsedAdmin := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:role/NeverUsedAdmin",
+		Type: types.PrincipalTypeRole,
+		Name: "NeverUsedAdmin",
+	}
+	activeAdmin := &types.Principal{
+		ARN:          "arn:aws:iam::123456789012:role/ActiveAdmin",
+		Type:         types.PrincipalTypeRole,
+		Name:         "ActiveAdmin",
+		RoleLastUsed: &recent,
+	}
+	staleHarmless := &types.Principal{
+		ARN:          "arn:aws:iam::123456789012:role/StaleReader",
+		Type:         types.PrincipalTypeRole,
+		Name:         "StaleReader",
+		RoleLastUsed: &old,
+	}
+
+	g := graph.New()
+	for _, p := range []*types.Principal{staleAdmin, neverUsedAdmin, activeAdmin, staleHarmless} {
+		g.AddPrincipal(p)
+	}
+	g.AddEdge(staleAdmin.ARN, "*", "*", false)
+	g.AddEdge(neverUsedAdmin.ARN, "*", "*", false)
+	g.AddEdge(activeAdmin.ARN, "*", "*", false)
+	g.AddEdge(staleHarmless.ARN, "s3:GetObject", "arn:aws:s3:::public-bucket/*", false)
+
+	engine := New(g)
+	findings := engine.FindUnusedPrivilegedRoles(90)
+
+	flagged := make(map[string]bool)
+	for _, f := range findings {
+		if f.Type != "Unused Privileged Role" {
+			t.Errorf("Unexpected finding type %q", f.Type)
+		}
+		flagged[f.Principal.Name] = true
+	}
+
+	if !flagged["StaleAdmin"] {
+		t.Error("Expected StaleAdmin (unused 180 days) to be flagged")
+	}
+	if !flagged["NeverUsedAdmin"] {
+		t.Error("Expected NeverUsedAdmin (no last-used data) to be flagged")
+	}
+	if flagged["ActiveAdmin"] {
+		t.Error("Did not expect recently-assumed ActiveAdmin to be flagged")
+	}
+	if flagged["StaleReader"] {
+		t.Error("Did not expect unprivileged StaleReader to be flagged")
+	}
+}
+
+func TestFindPassRoleToCompute(t *testing.T) {
+	launcher := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/launcher",
+		Type:      types.PrincipalTypeUser,
+		Name:      "launcher",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   []interface{}{"iam:PassRole", "ec2:RunInstances", "lambda:CreateFunction"},
+						Resource: "*",
+					},
+				},
+			},
+		},
+	}
+	// Holds PassRole but no compute launch action: not a finding
+	passOnly := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:role/pass-only",
+		Type:      types.PrincipalTypeRole,
+		Name:      "pass-only",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "iam:PassRole",
+						Resource: "*",
+					},
+				},
+			},
+		},
+	}
+	// Holds a launch action but no PassRole: not a finding
+	computeOnly := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/compute-only",
+		Type:      types.PrincipalTypeUser,
+		Name:      "compute-only",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "ecs:RunTask",
+						Resource: "*",
+					},
+				},
+			},
+		},
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{launcher, passOnly, computeOnly},
+	})
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+
+	findings := New(g).findPassRoleToCompute()
+
+	if len(findings) != 1 {
+		t.Fatalf("findPassRoleToCompute() returned %d findings, want 1", len(findings))
+	}
+	if findings[0].Principal == nil || findings[0].Principal.ARN != launcher.ARN {
+		t.Errorf("finding principal = %v, want %s", findings[0].Principal, launcher.ARN)
+	}
+	if findings[0].Severity != "HIGH" {
+		t.Errorf("finding severity = %s, want HIGH", findings[0].Severity)
+	}
+	if findings[0].Type != "PassRole to Compute Service" {
+		t.Errorf("finding type = %s, want PassRole to Compute Service", findings[0].Type)
+	}
+}
+
+func TestFindOpenMessagingPolicies(t *testing.T) {
+	openQueue := &types.Resource{
+		ARN:       "arn:aws:sqs:us-east-1:123456789012:open-queue",
+		Type:      types.ResourceTypeSQS,
+		Name:      "open-queue",
+		AccountID: "123456789012",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: "*",
+					Action:    "sqs:SendMessage",
+					Resource:  "arn:aws:sqs:us-east-1:123456789012:open-queue",
+				},
+			},
+		},
+	}
+	// Wildcard principal, but scoped to a source ARN: downgraded, not dropped
+	scopedTopic := &types.Resource{
+		ARN:       "arn:aws:sns:us-east-1:123456789012:scoped-topic",
+		Type:      types.ResourceTypeSNS,
+		Name:      "scoped-topic",
+		AccountID: "123456789012",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: map[string]interface{}{"AWS": "*"},
+					Action:    "sns:Publish",
+					Resource:  "arn:aws:sns:us-east-1:123456789012:scoped-topic",
+					Condition: map[string]map[string]interface{}{
+						"ArnEquals": {
+							"aws:SourceArn": "arn:aws:s3:::events-bucket",
+						},
+					},
+				},
+			},
+		},
+	}
+	// Wildcard principal on a non-messaging action: not this finding's business
+	readOnlyTopic := &types.Resource{
+		ARN:       "arn:aws:sns:us-east-1:123456789012:readonly-topic",
+		Type:      types.ResourceTypeSNS,
+		Name:      "readonly-topic",
+		AccountID: "123456789012",
+		ResourcePolicy: &types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{
+					Effect:    types.EffectAllow,
+					Principal: "*",
+					Action:    "sns:GetTopicAttributes",
+					Resource:  "arn:aws:sns:us-east-1:123456789012:readonly-topic",
+				},
+			},
+		},
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		AccountID: "123456789012",
+		Resources: []*types.Resource{openQueue, scopedTopic, readOnlyTopic},
+	})
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+
+	findings := New(g).findOpenMessagingPolicies()
+
+	if len(findings) != 2 {
+		t.Fatalf("findOpenMessagingPolicies() returned %d findings, want 2", len(findings))
+	}
+
+	bySeverity := make(map[string]string)
+	for _, f := range findings {
+		if f.Type != "Open Messaging Policy" {
+			t.Errorf("finding type = %s, want Open Messaging Policy", f.Type)
+		}
+		if f.Resource == nil {
+			t.Fatal("finding has nil resource")
+		}
+		bySeverity[f.Severity] = f.Resource.ARN
+	}
+
+	if bySeverity["HIGH"] != openQueue.ARN {
+		t.Errorf("HIGH finding resource = %s, want %s", bySeverity["HIGH"], openQueue.ARN)
+	}
+	if bySeverity["LOW"] != scopedTopic.ARN {
+		t.Errorf("LOW finding resource = %s, want %s", bySeverity["LOW"], scopedTopic.ARN)
+	}
+}
+
+func TestFindUnusedManagedPolicies(t *testing.T) {
+	attachedARN := "arn:aws:iam::123456789012:policy/team-access"
+	unusedARN := "arn:aws:iam::123456789012:policy/old-migration"
+	externalARN := "arn:aws:iam::123456789012:policy/group-only"
+
+	collection := &types.CollectionResult{
+		AccountID: "123456789012",
+		Principals: []*types.Principal{
+			{
+				ARN:       "arn:aws:iam::123456789012:user/alice",
+				Type:      types.PrincipalTypeUser,
+				Name:      "alice",
+				AccountID: "123456789012",
+				Policies: []types.PolicyDocument{
+					{
+						Version:         "2012-10-17",
+						SourcePolicyARN: attachedARN,
+						Statements: []types.Statement{
+							{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "*"},
+						},
+					},
+				},
+			},
+		},
+		ManagedPolicies: []types.ManagedPolicyInfo{
+			{ARN: attachedARN, Name: "team-access", AttachmentCount: 1},
+			{ARN: unusedARN, Name: "old-migration", AttachmentCount: 0},
+			// Attached somewhere outside the collected principal set
+			{ARN: externalARN, Name: "group-only", AttachmentCount: 2},
+		},
+	}
+
+	g, err := graph.Build(collection)
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+
+	findings := New(g).FindUnusedManagedPolicies()
+
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 unused policy finding, got %d: %+v", len(findings), findings)
+	}
+	if findings[0].Type != "Unused Managed Policy" || findings[0].Severity != "LOW" {
+		t.Errorf("Unexpected finding shape: %+v", findings[0])
+	}
+	if !strings.Contains(findings[0].Description, unusedARN) {
+		t.Errorf("Expected the unused policy ARN in the description, got %q", findings[0].Description)
+	}
+}
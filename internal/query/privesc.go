@@ -0,0 +1,125 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pfrederiksen/aws-access-map/internal/policy"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// Escalation-enabling IAM actions: any one of these lets the caller rewrite
+// permissions and escalate to admin. Keyed by action with the technique a
+// finding should describe.
+var escalationTechniques = map[string]string{
+	"iam:PutUserPolicy":       "write an inline policy granting arbitrary permissions to a user",
+	"iam:AttachUserPolicy":    "attach a managed policy such as AdministratorAccess to a user",
+	"iam:CreatePolicyVersion": "push a new default version of an existing managed policy",
+	"iam:PutRolePolicy":       "write an inline policy granting arbitrary permissions to a role",
+}
+
+// escalationActions holds the map keys in a stable order for iteration
+var escalationActions = []string{
+	"iam:PutUserPolicy",
+	"iam:AttachUserPolicy",
+	"iam:CreatePolicyVersion",
+	"iam:PutRolePolicy",
+}
+
+// EscalationFinding describes one privilege-escalation capability: a
+// principal holding an IAM action that rewrites permissions, and whether the
+// grant targets the principal itself or other principals
+type EscalationFinding struct {
+	PrincipalARN string `json:"principal"`
+	Action       string `json:"action"`
+
+	// Target classifies the grant scope: "self", "other principals",
+	// "itself and other principals", or "any principal" for wildcards
+	Target string `json:"target"`
+
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+// FindPrivilegeEscalation scans every principal for escalation-enabling IAM
+// actions (iam:PutUserPolicy and friends). It walks the allow edges to find
+// candidate grants, then re-checks each through CanAccess so denies, SCPs,
+// and boundaries are respected. One finding is returned per principal and
+// action, with the broadest target scope observed.
+func (e *Engine) FindPrivilegeEscalation() []EscalationFinding {
+	type grantKey struct {
+		principalARN string
+		action       string
+	}
+	// Scope sets per grant: "self", "other", "any"
+	scopes := make(map[grantKey]map[string]bool)
+
+	for _, edge := range e.graph.GetPermissionEdges() {
+		if edge.IsDeny {
+			continue
+		}
+		for _, action := range escalationActions {
+			if !policy.MatchesAction(edge.Action, action) {
+				continue
+			}
+			// The edge is a candidate; confirm the full evaluation path
+			// (denies, boundaries, SCPs, conditions) still allows it
+			if !e.graph.CanAccess(edge.PrincipalARN, action, edge.ResourceARN) {
+				continue
+			}
+
+			key := grantKey{edge.PrincipalARN, action}
+			if scopes[key] == nil {
+				scopes[key] = make(map[string]bool)
+			}
+			switch {
+			case edge.ResourceARN == "*":
+				scopes[key]["any"] = true
+			case edge.ResourceARN == edge.PrincipalARN:
+				scopes[key]["self"] = true
+			case policy.MatchesResource(edge.ResourceARN, edge.PrincipalARN):
+				// A pattern covering the caller's own ARN covers others too
+				scopes[key]["any"] = true
+			default:
+				scopes[key]["other"] = true
+			}
+		}
+	}
+
+	var findings []EscalationFinding
+	for key, scope := range scopes {
+		principal, ok := e.graph.GetPrincipal(key.principalARN)
+		if !ok || principal.Type == types.PrincipalTypePublic {
+			continue
+		}
+
+		target := "self"
+		severity := "HIGH"
+		switch {
+		case scope["any"]:
+			target = "any principal"
+			severity = "CRITICAL"
+		case scope["self"] && scope["other"]:
+			target = "itself and other principals"
+		case scope["other"]:
+			target = "other principals"
+		}
+
+		findings = append(findings, EscalationFinding{
+			PrincipalARN: key.principalARN,
+			Action:       key.action,
+			Target:       target,
+			Severity:     severity,
+			Description: fmt.Sprintf("'%s' can call %s against %s - %s",
+				principal.Name, key.action, target, escalationTechniques[key.action]),
+		})
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].PrincipalARN != findings[j].PrincipalARN {
+			return findings[i].PrincipalARN < findings[j].PrincipalARN
+		}
+		return findings[i].Action < findings[j].Action
+	})
+	return findings
+}
@@ -0,0 +1,137 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func privescTestGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	allow := func(action, resource string) types.PolicyDocument {
+		return types.PolicyDocument{
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{Effect: types.EffectAllow, Action: action, Resource: resource},
+			},
+		}
+	}
+
+	selfEditor := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/self-editor",
+		Type:      types.PrincipalTypeUser,
+		Name:      "self-editor",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			allow("iam:PutUserPolicy", "arn:aws:iam::123456789012:user/self-editor"),
+		},
+	}
+	adminBot := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:role/admin-bot",
+		Type:      types.PrincipalTypeRole,
+		Name:      "admin-bot",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			allow("iam:AttachUserPolicy", "*"),
+		},
+	}
+	teamLead := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/team-lead",
+		Type:      types.PrincipalTypeUser,
+		Name:      "team-lead",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			allow("iam:PutUserPolicy", "arn:aws:iam::123456789012:user/self-editor"),
+		},
+	}
+	bystander := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/bystander",
+		Type:      types.PrincipalTypeUser,
+		Name:      "bystander",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			allow("s3:GetObject", "arn:aws:s3:::data/*"),
+		},
+	}
+	blocked := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/blocked",
+		Type:      types.PrincipalTypeUser,
+		Name:      "blocked",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			allow("iam:CreatePolicyVersion", "*"),
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{Effect: types.EffectDeny, Action: "iam:*", Resource: "*"},
+				},
+			},
+		},
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{selfEditor, adminBot, teamLead, bystander, blocked},
+	})
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+	return g
+}
+
+func TestFindPrivilegeEscalation(t *testing.T) {
+	engine := New(privescTestGraph(t))
+
+	findings := engine.FindPrivilegeEscalation()
+
+	byPrincipal := make(map[string]EscalationFinding)
+	for _, f := range findings {
+		byPrincipal[f.PrincipalARN] = f
+	}
+
+	// Self-scoped grant
+	selfEditor, ok := byPrincipal["arn:aws:iam::123456789012:user/self-editor"]
+	if !ok {
+		t.Fatal("Expected a finding for self-editor")
+	}
+	if selfEditor.Action != "iam:PutUserPolicy" || selfEditor.Target != "self" {
+		t.Errorf("Unexpected self-editor finding: %+v", selfEditor)
+	}
+	if selfEditor.Severity != "HIGH" {
+		t.Errorf("Expected HIGH for a self-scoped grant, got %s", selfEditor.Severity)
+	}
+
+	// Wildcard grant covers every principal
+	adminBot, ok := byPrincipal["arn:aws:iam::123456789012:role/admin-bot"]
+	if !ok {
+		t.Fatal("Expected a finding for admin-bot")
+	}
+	if adminBot.Target != "any principal" || adminBot.Severity != "CRITICAL" {
+		t.Errorf("Unexpected admin-bot finding: %+v", adminBot)
+	}
+
+	// Grant targeting a different principal
+	teamLead, ok := byPrincipal["arn:aws:iam::123456789012:user/team-lead"]
+	if !ok {
+		t.Fatal("Expected a finding for team-lead")
+	}
+	if teamLead.Target != "other principals" {
+		t.Errorf("Expected 'other principals' target, got %q", teamLead.Target)
+	}
+
+	// No escalation actions, no finding
+	if _, ok := byPrincipal["arn:aws:iam::123456789012:user/bystander"]; ok {
+		t.Error("Did not expect a finding for bystander")
+	}
+
+	// The explicit deny wins over the wildcard allow
+	if _, ok := byPrincipal["arn:aws:iam::123456789012:user/blocked"]; ok {
+		t.Error("Did not expect a finding for a principal with an explicit deny")
+	}
+
+	if len(findings) != 3 {
+		t.Errorf("Expected 3 findings, got %d: %+v", len(findings), findings)
+	}
+}
@@ -0,0 +1,40 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResolveResource expands a bare friendly name like "my-bucket" or
+// "my-function" to the full ARN of the matching resource in the graph. Full
+// ARNs, wildcards, and service shorthands like s3://my-bucket pass through
+// unchanged, as does a name with no match (the query then treats it
+// literally). A name that matches multiple resources is an error listing the
+// candidate ARNs so the user can disambiguate.
+func (e *Engine) ResolveResource(identifier string) (string, error) {
+	if identifier == "" ||
+		strings.HasPrefix(identifier, "arn:") ||
+		strings.Contains(identifier, "://") ||
+		strings.ContainsAny(identifier, "*?") {
+		return identifier, nil
+	}
+
+	var matches []string
+	for _, resource := range e.graph.GetAllResources() {
+		if resource.Name == identifier {
+			matches = append(matches, resource.ARN)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return identifier, nil
+	case 1:
+		return matches[0], nil
+	default:
+		sort.Strings(matches)
+		return "", fmt.Errorf("resource name %q is ambiguous, use a full ARN: %s",
+			identifier, strings.Join(matches, ", "))
+	}
+}
@@ -0,0 +1,99 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestResolveResource(t *testing.T) {
+	collection := &types.CollectionResult{
+		AccountID: "123456789012",
+		Resources: []*types.Resource{
+			{
+				ARN:  "arn:aws:s3:::app-data",
+				Type: "AWS::S3::Bucket",
+				Name: "app-data",
+			},
+			{
+				ARN:  "arn:aws:lambda:us-east-1:123456789012:function:processor",
+				Type: "AWS::Lambda::Function",
+				Name: "processor",
+			},
+			{
+				ARN:  "arn:aws:sqs:us-east-1:123456789012:processor",
+				Type: "AWS::SQS::Queue",
+				Name: "processor",
+			},
+		},
+	}
+
+	g, err := graph.Build(collection)
+	if err != nil {
+		t.Fatalf("Failed to build graph: %v", err)
+	}
+	engine := New(g)
+
+	tests := []struct {
+		name       string
+		identifier string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "unique name expands to ARN",
+			identifier: "app-data",
+			want:       "arn:aws:s3:::app-data",
+		},
+		{
+			name:       "full ARN passes through",
+			identifier: "arn:aws:s3:::app-data",
+			want:       "arn:aws:s3:::app-data",
+		},
+		{
+			name:       "service shorthand passes through",
+			identifier: "s3://app-data",
+			want:       "s3://app-data",
+		},
+		{
+			name:       "wildcard passes through",
+			identifier: "*",
+			want:       "*",
+		},
+		{
+			name:       "unknown name passes through",
+			identifier: "no-such-resource",
+			want:       "no-such-resource",
+		},
+		{
+			name:       "ambiguous name errors",
+			identifier: "processor",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.ResolveResource(tt.identifier)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error for %q, got %q", tt.identifier, got)
+				}
+				// The error should list both candidates for disambiguation
+				if !strings.Contains(err.Error(), "arn:aws:lambda:us-east-1:123456789012:function:processor") ||
+					!strings.Contains(err.Error(), "arn:aws:sqs:us-east-1:123456789012:processor") {
+					t.Errorf("Expected error to list candidate ARNs, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveResource(%q) failed: %v", tt.identifier, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveResource(%q) = %q, want %q", tt.identifier, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package query
+
+import (
+	"sort"
+
+	"github.com/pfrederiksen/aws-access-map/internal/policy"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// SCPConflict describes an action one SCP allows while another SCP applying to
+// the same accounts denies it. Since deny always wins in SCP evaluation, the
+// allowed action is unreachable for the affected accounts.
+type SCPConflict struct {
+	Action           string   // Action pattern from the Allow statement
+	DenyPattern      string   // Action pattern from the Deny statement covering it
+	AllowedPolicyID  string   // SCP containing the Allow
+	DeniedPolicyID   string   // SCP containing the Deny
+	AffectedAccounts []string // Accounts where both SCPs apply
+}
+
+// FindSCPConflicts analyzes SCP attachments against the OU hierarchy and
+// reports actions allowed at one level but denied at another (e.g. an OU
+// allowing a service the root denies). hierarchies maps account ID to its
+// OU membership; accounts missing from the map are matched conservatively.
+func FindSCPConflicts(attachments []types.SCPAttachment, hierarchies map[string]*types.OUHierarchy) []SCPConflict {
+	var conflicts []SCPConflict
+
+	for i, allowSCP := range attachments {
+		allowActions := scpActionsWithEffect(allowSCP.Policy, types.EffectAllow)
+		if len(allowActions) == 0 {
+			continue
+		}
+
+		for j, denySCP := range attachments {
+			if i == j {
+				continue
+			}
+
+			denyActions := scpActionsWithEffect(denySCP.Policy, types.EffectDeny)
+			if len(denyActions) == 0 {
+				continue
+			}
+
+			// Both SCPs must apply to at least one common account for the
+			// deny to shadow the allow
+			affected := accountsWithBothSCPs(allowSCP, denySCP, hierarchies)
+			if len(affected) == 0 {
+				continue
+			}
+
+			for _, allowAction := range allowActions {
+				for _, denyAction := range denyActions {
+					if !policy.MatchesAction(denyAction, allowAction) {
+						continue
+					}
+
+					conflicts = append(conflicts, SCPConflict{
+						Action:           allowAction,
+						DenyPattern:      denyAction,
+						AllowedPolicyID:  allowSCP.Policy.ID,
+						DeniedPolicyID:   denySCP.Policy.ID,
+						AffectedAccounts: affected,
+					})
+				}
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// scpActionsWithEffect collects the action patterns from statements with the
+// given effect. NotAction statements are skipped - they can't be compared
+// pattern-to-pattern.
+func scpActionsWithEffect(doc types.PolicyDocument, effect types.Effect) []string {
+	var actions []string
+
+	for _, stmt := range doc.Statements {
+		if stmt.Effect != effect {
+			continue
+		}
+
+		switch a := stmt.Action.(type) {
+		case string:
+			actions = append(actions, a)
+		case []interface{}:
+			for _, item := range a {
+				if str, ok := item.(string); ok {
+					actions = append(actions, str)
+				}
+			}
+		case []string:
+			actions = append(actions, a...)
+		}
+	}
+
+	return actions
+}
+
+// accountsWithBothSCPs returns the sorted account IDs where both SCPs apply,
+// based on their targets and each account's OU hierarchy
+func accountsWithBothSCPs(a, b types.SCPAttachment, hierarchies map[string]*types.OUHierarchy) []string {
+	var accounts []string
+
+	for accountID, hierarchy := range hierarchies {
+		if scpAppliesToAccount(a, accountID, hierarchy) && scpAppliesToAccount(b, accountID, hierarchy) {
+			accounts = append(accounts, accountID)
+		}
+	}
+
+	sort.Strings(accounts)
+	return accounts
+}
+
+// scpAppliesToAccount mirrors the per-account SCP filtering used when
+// building a graph: root targets apply everywhere, account targets must
+// match, and OU targets must appear in the account's parent chain
+func scpAppliesToAccount(attachment types.SCPAttachment, accountID string, hierarchy *types.OUHierarchy) bool {
+	parentOUs := make(map[string]bool)
+	if hierarchy != nil {
+		for _, ouID := range hierarchy.ParentOUs {
+			parentOUs[ouID] = true
+		}
+	}
+
+	for _, target := range attachment.Targets {
+		switch target.Type {
+		case types.SCPTargetTypeRoot:
+			return true
+		case types.SCPTargetTypeAccount:
+			if target.ID == accountID {
+				return true
+			}
+		case types.SCPTargetTypeOrganizationalUnit:
+			if hierarchy == nil || parentOUs[target.ID] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestFindSCPConflicts_OULevelConflict(t *testing.T) {
+	// Root SCP denies s3:*, an OU SCP allows s3:GetObject - the allow is unreachable
+	rootDeny := types.SCPAttachment{
+		Policy: types.PolicyDocument{
+			ID:      "p-root-deny",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{Effect: types.EffectDeny, Action: "s3:*", Resource: "*"},
+			},
+		},
+		Targets: []types.SCPTarget{
+			{Type: types.SCPTargetTypeRoot, ID: "ROOT"},
+		},
+	}
+	ouAllow := types.SCPAttachment{
+		Policy: types.PolicyDocument{
+			ID:      "p-ou-allow",
+			Version: "2012-10-17",
+			Statements: []types.Statement{
+				{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "*"},
+			},
+		},
+		Targets: []types.SCPTarget{
+			{Type: types.SCPTargetTypeOrganizationalUnit, ID: "ou-1234"},
+		},
+	}
+
+	hierarchies := map[string]*types.OUHierarchy{
+		"111111111111": {AccountID: "111111111111", ParentOUs: []string{"ou-1234"}},
+		"222222222222": {AccountID: "222222222222", ParentOUs: []string{"ou-9999"}},
+	}
+
+	conflicts := FindSCPConflicts([]types.SCPAttachment{rootDeny, ouAllow}, hierarchies)
+
+	if len(conflicts) != 1 {
+		t.Fatalf("FindSCPConflicts() returned %d conflicts, want 1", len(conflicts))
+	}
+
+	conflict := conflicts[0]
+	if conflict.Action != "s3:GetObject" {
+		t.Errorf("conflict action = %s, want s3:GetObject", conflict.Action)
+	}
+	if conflict.AllowedPolicyID != "p-ou-allow" || conflict.DeniedPolicyID != "p-root-deny" {
+		t.Errorf("conflict policies = %s/%s, want p-ou-allow/p-root-deny", conflict.AllowedPolicyID, conflict.DeniedPolicyID)
+	}
+	// Only the account under ou-1234 is affected by both SCPs
+	if len(conflict.AffectedAccounts) != 1 || conflict.AffectedAccounts[0] != "111111111111" {
+		t.Errorf("affected accounts = %v, want [111111111111]", conflict.AffectedAccounts)
+	}
+}
+
+func TestFindSCPConflicts_NoOverlap(t *testing.T) {
+	// Deny and allow target disjoint OUs - no account sees both
+	ouDeny := types.SCPAttachment{
+		Policy: types.PolicyDocument{
+			ID: "p-deny",
+			Statements: []types.Statement{
+				{Effect: types.EffectDeny, Action: "iam:*", Resource: "*"},
+			},
+		},
+		Targets: []types.SCPTarget{
+			{Type: types.SCPTargetTypeOrganizationalUnit, ID: "ou-aaaa"},
+		},
+	}
+	ouAllow := types.SCPAttachment{
+		Policy: types.PolicyDocument{
+			ID: "p-allow",
+			Statements: []types.Statement{
+				{Effect: types.EffectAllow, Action: "iam:CreateRole", Resource: "*"},
+			},
+		},
+		Targets: []types.SCPTarget{
+			{Type: types.SCPTargetTypeOrganizationalUnit, ID: "ou-bbbb"},
+		},
+	}
+
+	hierarchies := map[string]*types.OUHierarchy{
+		"111111111111": {AccountID: "111111111111", ParentOUs: []string{"ou-aaaa"}},
+		"222222222222": {AccountID: "222222222222", ParentOUs: []string{"ou-bbbb"}},
+	}
+
+	conflicts := FindSCPConflicts([]types.SCPAttachment{ouDeny, ouAllow}, hierarchies)
+	if len(conflicts) != 0 {
+		t.Errorf("FindSCPConflicts() returned %d conflicts, want 0", len(conflicts))
+	}
+}
+
+func TestFindSCPConflicts_NonMatchingActions(t *testing.T) {
+	// Deny on a different service doesn't shadow the allow
+	rootDeny := types.SCPAttachment{
+		Policy: types.PolicyDocument{
+			ID: "p-deny-kms",
+			Statements: []types.Statement{
+				{Effect: types.EffectDeny, Action: "kms:*", Resource: "*"},
+			},
+		},
+		Targets: []types.SCPTarget{
+			{Type: types.SCPTargetTypeRoot, ID: "ROOT"},
+		},
+	}
+	rootAllow := types.SCPAttachment{
+		Policy: types.PolicyDocument{
+			ID: "p-allow-s3",
+			Statements: []types.Statement{
+				{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "*"},
+			},
+		},
+		Targets: []types.SCPTarget{
+			{Type: types.SCPTargetTypeRoot, ID: "ROOT"},
+		},
+	}
+
+	hierarchies := map[string]*types.OUHierarchy{
+		"111111111111": {AccountID: "111111111111"},
+	}
+
+	conflicts := FindSCPConflicts([]types.SCPAttachment{rootDeny, rootAllow}, hierarchies)
+	if len(conflicts) != 0 {
+		t.Errorf("FindSCPConflicts() returned %d conflicts, want 0", len(conflicts))
+	}
+}
@@ -0,0 +1,149 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// maxSideEffects caps how many broken grants a suggestion reports; past that
+// point the candidate is clearly over-blocking and the list stops being useful
+const maxSideEffects = 25
+
+// SCPSideEffect is an unrelated grant a candidate SCP would also block
+type SCPSideEffect struct {
+	PrincipalARN string `json:"PrincipalArn"`
+	Action       string `json:"Action"`
+	ResourceARN  string `json:"ResourceArn"`
+}
+
+// SCPSuggestion is a ready-to-apply SCP that blocks one undesired access,
+// plus the collateral damage check that was run against the graph
+type SCPSuggestion struct {
+	PrincipalARN string               `json:"PrincipalArn"`
+	Action       string               `json:"Action"`
+	ResourceARN  string               `json:"ResourceArn"`
+	Policy       types.PolicyDocument `json:"Policy"`
+	SideEffects  []SCPSideEffect      `json:"SideEffects,omitempty"`
+}
+
+// SuggestSCP proposes an SCP deny statement that blocks the given access,
+// which must currently resolve allowed. The candidate is verified two ways
+// against a copy of the graph with the SCP in force: the target access must
+// become denied, and every other allow edge is re-evaluated so grants the
+// candidate would break are reported as side effects rather than silently
+// over-blocked.
+func (e *Engine) SuggestSCP(principalARN, action, resourceARN string) (*SCPSuggestion, error) {
+	if _, ok := e.graph.GetPrincipal(principalARN); !ok {
+		return nil, fmt.Errorf("principal not found: %s", principalARN)
+	}
+	if !e.graph.CanAccess(principalARN, action, resourceARN, e.context) {
+		return nil, fmt.Errorf("%s cannot currently perform %s on %s; nothing to block", principalARN, action, resourceARN)
+	}
+
+	// Candidate denies, most specific first: the exact resource, then a
+	// resource wildcard for grants the exact ARN doesn't intersect
+	candidates := []types.Statement{
+		{
+			Sid:      "BlockUndesiredAccess",
+			Effect:   types.EffectDeny,
+			Action:   action,
+			Resource: resourceARN,
+		},
+		{
+			Sid:      "BlockUndesiredAccess",
+			Effect:   types.EffectDeny,
+			Action:   action,
+			Resource: "*",
+		},
+	}
+
+	var best *SCPSuggestion
+	for _, stmt := range candidates {
+		policy := e.candidatePolicy(stmt)
+		trial := e.graph.WithAdditionalSCP(policy)
+
+		if trial.CanAccess(principalARN, action, resourceARN, e.context) {
+			continue // Doesn't block the target; try the broader shape
+		}
+
+		suggestion := &SCPSuggestion{
+			PrincipalARN: principalARN,
+			Action:       action,
+			ResourceARN:  resourceARN,
+			Policy:       policy,
+			SideEffects:  e.findSCPSideEffects(trial, principalARN, action, resourceARN),
+		}
+
+		// A clean candidate wins outright; otherwise keep the narrowest
+		// blocking shape and report its side effects
+		if len(suggestion.SideEffects) == 0 {
+			return suggestion, nil
+		}
+		if best == nil {
+			best = suggestion
+		}
+	}
+
+	if best != nil {
+		return best, nil
+	}
+	return nil, fmt.Errorf("no candidate SCP blocks %s on %s for %s (access may come through a path SCPs don't cover)", action, resourceARN, principalARN)
+}
+
+// candidatePolicy wraps a deny statement into an applicable SCP. When the
+// graph has no SCPs in force the document carries its own FullAWSAccess-style
+// allow, since a deny-only SCP on an account with nothing allow-listed would
+// implicitly deny everything.
+func (e *Engine) candidatePolicy(stmt types.Statement) types.PolicyDocument {
+	statements := []types.Statement{stmt}
+	if !e.graph.HasSCPs() {
+		statements = append([]types.Statement{
+			{
+				Sid:      "FullAWSAccess",
+				Effect:   types.EffectAllow,
+				Action:   "*",
+				Resource: "*",
+			},
+		}, statements...)
+	}
+	return types.PolicyDocument{
+		Version:    "2012-10-17",
+		ID:         "suggested-scp",
+		Statements: statements,
+	}
+}
+
+// findSCPSideEffects re-evaluates every other allow edge under the candidate
+// SCP and reports grants that stop resolving. The target access itself and
+// edges that were already ineffective are skipped.
+func (e *Engine) findSCPSideEffects(trial *graph.Graph, principalARN, action, resourceARN string) []SCPSideEffect {
+	var sideEffects []SCPSideEffect
+
+	for _, edge := range e.graph.GetPermissionEdges() {
+		if edge.IsDeny {
+			continue
+		}
+		if edge.PrincipalARN == principalARN && edge.Action == action && edge.ResourceARN == resourceARN {
+			continue
+		}
+		if !e.graph.CanAccess(edge.PrincipalARN, edge.Action, edge.ResourceARN, e.context) {
+			continue // Already ineffective; not our candidate's doing
+		}
+		if trial.CanAccess(edge.PrincipalARN, edge.Action, edge.ResourceARN, e.context) {
+			continue
+		}
+
+		sideEffects = append(sideEffects, SCPSideEffect{
+			PrincipalARN: edge.PrincipalARN,
+			Action:       edge.Action,
+			ResourceARN:  edge.ResourceARN,
+		})
+		if len(sideEffects) >= maxSideEffects {
+			break
+		}
+	}
+
+	return sideEffects
+}
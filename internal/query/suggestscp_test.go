@@ -0,0 +1,125 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func suggestSCPTestGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	contractor := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/contractor",
+		Type:      types.PrincipalTypeUser,
+		Name:      "contractor",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   []interface{}{"s3:GetObject", "s3:DeleteBucket"},
+						Resource: "arn:aws:s3:::prod-data",
+					},
+				},
+			},
+		},
+	}
+	operator := &types.Principal{
+		ARN:       "arn:aws:iam::123456789012:role/operator",
+		Type:      types.PrincipalTypeRole,
+		Name:      "operator",
+		AccountID: "123456789012",
+		Policies: []types.PolicyDocument{
+			{
+				Version: "2012-10-17",
+				Statements: []types.Statement{
+					{
+						Effect:   types.EffectAllow,
+						Action:   "sqs:SendMessage",
+						Resource: "arn:aws:sqs:us-east-1:123456789012:jobs",
+					},
+				},
+			},
+		},
+	}
+
+	g, err := graph.Build(&types.CollectionResult{
+		AccountID:  "123456789012",
+		Principals: []*types.Principal{contractor, operator},
+	})
+	if err != nil {
+		t.Fatalf("graph.Build() failed: %v", err)
+	}
+	return g
+}
+
+func TestSuggestSCP(t *testing.T) {
+	g := suggestSCPTestGraph(t)
+
+	suggestion, err := New(g).SuggestSCP(
+		"arn:aws:iam::123456789012:user/contractor",
+		"s3:DeleteBucket",
+		"arn:aws:s3:::prod-data",
+	)
+	if err != nil {
+		t.Fatalf("SuggestSCP() failed: %v", err)
+	}
+
+	// The candidate must actually block the target access
+	trial := g.WithAdditionalSCP(suggestion.Policy)
+	if trial.CanAccess("arn:aws:iam::123456789012:user/contractor", "s3:DeleteBucket", "arn:aws:s3:::prod-data") {
+		t.Error("Suggested SCP does not block the target access")
+	}
+
+	// Unrelated grants survive: the contractor's read and the operator's queue
+	if !trial.CanAccess("arn:aws:iam::123456789012:user/contractor", "s3:GetObject", "arn:aws:s3:::prod-data") {
+		t.Error("Suggested SCP blocks the contractor's unrelated read access")
+	}
+	if !trial.CanAccess("arn:aws:iam::123456789012:role/operator", "sqs:SendMessage", "arn:aws:sqs:us-east-1:123456789012:jobs") {
+		t.Error("Suggested SCP blocks the operator's unrelated queue access")
+	}
+	if len(suggestion.SideEffects) != 0 {
+		t.Errorf("Expected no side effects, got %v", suggestion.SideEffects)
+	}
+
+	// The graph had no SCPs, so the snippet must carry its own allow
+	if len(suggestion.Policy.Statements) != 2 {
+		t.Fatalf("Expected allow + deny statements, got %d", len(suggestion.Policy.Statements))
+	}
+	if suggestion.Policy.Statements[0].Effect != types.EffectAllow {
+		t.Error("Expected leading FullAWSAccess-style allow statement")
+	}
+	if suggestion.Policy.Statements[1].Effect != types.EffectDeny {
+		t.Error("Expected trailing deny statement")
+	}
+}
+
+func TestSuggestSCP_AccessNotAllowed(t *testing.T) {
+	g := suggestSCPTestGraph(t)
+
+	_, err := New(g).SuggestSCP(
+		"arn:aws:iam::123456789012:role/operator",
+		"s3:DeleteBucket",
+		"arn:aws:s3:::prod-data",
+	)
+	if err == nil {
+		t.Fatal("Expected error for access that is not currently allowed")
+	}
+}
+
+func TestSuggestSCP_PrincipalNotFound(t *testing.T) {
+	g := suggestSCPTestGraph(t)
+
+	_, err := New(g).SuggestSCP(
+		"arn:aws:iam::123456789012:user/ghost",
+		"s3:DeleteBucket",
+		"arn:aws:s3:::prod-data",
+	)
+	if err == nil {
+		t.Fatal("Expected error for unknown principal")
+	}
+}
@@ -3,7 +3,9 @@ package simulation
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 
 	"github.com/pfrederiksen/aws-access-map/internal/graph"
 	"github.com/pfrederiksen/aws-access-map/internal/query"
@@ -26,13 +28,187 @@ func LoadFromFile(filePath string) (*types.CollectionResult, error) {
 	return &result, nil
 }
 
+// LoadFromReader loads a CollectionResult from an open stream. The CLI uses
+// it for '--data -' so CI pipelines can pipe generated policy JSON on stdin
+// instead of writing a temp file.
+func LoadFromReader(r io.Reader) (*types.CollectionResult, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input stream: %w", err)
+	}
+
+	var result types.CollectionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON from input stream: %w", err)
+	}
+
+	return &result, nil
+}
+
+// LoadMultiAccountFromFile loads an org-wide MultiAccountCollectionResult
+// from a JSON file, as written by 'collect --all-accounts'
+func LoadMultiAccountFromFile(filePath string) (*types.MultiAccountCollectionResult, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	var result types.MultiAccountCollectionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON from %s: %w", filePath, err)
+	}
+	if len(result.Accounts) == 0 {
+		return nil, fmt.Errorf("%s is not an org-wide dataset (no accounts)", filePath)
+	}
+
+	return &result, nil
+}
+
+// LoadGraphFromFile builds a graph from a data file, detecting whether it
+// holds a single-account CollectionResult or an org-wide
+// MultiAccountCollectionResult. Org datasets produce one combined graph, so
+// queries can cross account boundaries.
+func LoadGraphFromFile(filePath string) (*graph.Graph, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	return buildGraphFromData(data, filePath)
+}
+
+// LoadGraphFromReader is the stream counterpart of LoadGraphFromFile, with
+// the same single- vs multi-account dataset detection
+func LoadGraphFromReader(r io.Reader) (*graph.Graph, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input stream: %w", err)
+	}
+	return buildGraphFromData(data, "input stream")
+}
+
+// buildGraphFromData detects the dataset shape and builds the matching graph;
+// source names the origin for error messages
+func buildGraphFromData(data []byte, source string) (*graph.Graph, error) {
+	// An org dataset is keyed by account; probe for that shape first
+	var probe struct {
+		Accounts map[string]json.RawMessage
+	}
+	if err := json.Unmarshal(data, &probe); err == nil && len(probe.Accounts) > 0 {
+		var multi types.MultiAccountCollectionResult
+		if err := json.Unmarshal(data, &multi); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON from %s: %w", source, err)
+		}
+		return graph.BuildMultiAccount(&multi)
+	}
+
+	var result types.CollectionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON from %s: %w", source, err)
+	}
+	return graph.Build(&result)
+}
+
+// MergeResults combines multiple single-account collection results into one
+// CollectionResult for organization-wide offline queries. Principals,
+// resources, and managed policies are deduplicated by ARN (first occurrence
+// wins); SCP and RCP attachments, SSO assignments, and Lake Formation grants
+// are concatenated. The inputs are deep-copied, so mutating the merged result
+// never touches the originals. When the inputs span different accounts the
+// merged AccountID is empty and the per-account OU hierarchy is dropped,
+// since neither is meaningful for the combined set.
+func MergeResults(results []*types.CollectionResult) (*types.CollectionResult, error) {
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no collection results to merge")
+	}
+	if len(results) == 1 {
+		return deepCopyResult(results[0]), nil
+	}
+
+	merged := &types.CollectionResult{}
+
+	seenPrincipals := make(map[string]bool)
+	seenResources := make(map[string]bool)
+	seenManaged := make(map[string]bool)
+	seenRegions := make(map[string]bool)
+	sameAccount := true
+
+	for _, result := range results {
+		if result == nil {
+			return nil, fmt.Errorf("cannot merge a nil collection result")
+		}
+
+		for _, principal := range result.Principals {
+			if principal == nil || seenPrincipals[principal.ARN] {
+				continue
+			}
+			seenPrincipals[principal.ARN] = true
+			merged.Principals = append(merged.Principals, deepCopyPrincipal(principal))
+		}
+
+		for _, resource := range result.Resources {
+			if resource == nil || seenResources[resource.ARN] {
+				continue
+			}
+			seenResources[resource.ARN] = true
+			merged.Resources = append(merged.Resources, deepCopyResource(resource))
+		}
+
+		for _, mp := range result.ManagedPolicies {
+			if seenManaged[mp.ARN] {
+				continue
+			}
+			seenManaged[mp.ARN] = true
+			merged.ManagedPolicies = append(merged.ManagedPolicies, mp)
+		}
+
+		merged.SCPs = append(merged.SCPs, result.SCPs...)
+		merged.SCPAttachments = append(merged.SCPAttachments, result.SCPAttachments...)
+		merged.RCPAttachments = append(merged.RCPAttachments, result.RCPAttachments...)
+		merged.SSOAssignments = append(merged.SSOAssignments, result.SSOAssignments...)
+		merged.LFGrants = append(merged.LFGrants, result.LFGrants...)
+
+		for _, region := range result.Regions {
+			if seenRegions[region] {
+				continue
+			}
+			seenRegions[region] = true
+			merged.Regions = append(merged.Regions, region)
+		}
+
+		if result.CollectedAt.After(merged.CollectedAt) {
+			merged.CollectedAt = result.CollectedAt
+		}
+		if result.AccountID != results[0].AccountID {
+			sameAccount = false
+		}
+		merged.ResourcesSkipped = merged.ResourcesSkipped || result.ResourcesSkipped
+	}
+
+	// AccountID and OUHierarchy only survive a single-account merge
+	if sameAccount {
+		merged.AccountID = results[0].AccountID
+		for _, result := range results {
+			if result.OUHierarchy != nil {
+				merged.OUHierarchy = &types.OUHierarchy{
+					AccountID: result.OUHierarchy.AccountID,
+					ParentOUs: append([]string{}, result.OUHierarchy.ParentOUs...),
+				}
+				break
+			}
+		}
+	}
+
+	return merged, nil
+}
+
 // PolicyChanges represents modifications to apply to a CollectionResult
 type PolicyChanges struct {
-	AddPrincipals    []*types.Principal                        // Principals to add
-	RemovePrincipals []string                                  // Principal ARNs to remove
-	UpdatePolicies   map[string][]types.PolicyDocument         // PrincipalARN -> new policies to append
-	AddResources     []*types.Resource                         // Resources to add
-	RemoveResources  []string                                  // Resource ARNs to remove
+	AddPrincipals     []*types.Principal                        // Principals to add
+	RemovePrincipals  []string                                  // Principal ARNs to remove
+	UpdatePolicies    map[string][]types.PolicyDocument         // PrincipalARN -> new policies to append
+	AddResources      []*types.Resource                         // Resources to add
+	RemoveResources   []string                                  // Resource ARNs to remove
+	AddSCPAttachments []types.SCPAttachment                     // Proposed SCPs with their targets
 }
 
 // MergePolicyChanges applies policy modifications to a CollectionResult
@@ -105,6 +281,23 @@ func MergePolicyChanges(base *types.CollectionResult, changes *PolicyChanges) (*
 		modified.Resources = filteredResources
 	}
 
+	// Apply proposed SCP attachments
+	if len(changes.AddSCPAttachments) > 0 {
+		// Graph building prefers attachments over the legacy SCPs field, so
+		// a dataset carrying only legacy SCPs would silently lose them once
+		// the first attachment appears; promote them to root-target
+		// attachments so existing guardrails stay in effect
+		if len(modified.SCPAttachments) == 0 && len(modified.SCPs) > 0 {
+			for _, scp := range modified.SCPs {
+				modified.SCPAttachments = append(modified.SCPAttachments, types.SCPAttachment{
+					Policy:  scp,
+					Targets: []types.SCPTarget{{Type: types.SCPTargetTypeRoot, ID: "ROOT"}},
+				})
+			}
+		}
+		modified.SCPAttachments = append(modified.SCPAttachments, changes.AddSCPAttachments...)
+	}
+
 	return modified, nil
 }
 
@@ -187,6 +380,91 @@ func CompareAccess(before, after *graph.Graph, resourceARN, action string) (*Acc
 	return diff, nil
 }
 
+// ActionAccessDiff pairs one action from the representative query set with
+// the access diff a proposed SCP produced for it
+type ActionAccessDiff struct {
+	Action string      `json:"action"`
+	Diff   *AccessDiff `json:"diff"`
+}
+
+// EvaluateSCPImpact answers "what would this SCP break": it attaches the
+// proposed SCP to the dataset, rebuilds the graph, and diffs who-can results
+// across a representative query set - every distinct action granted in the
+// current dataset plus the actions the SCP itself names. Only actions whose
+// access changed are returned.
+func EvaluateSCPImpact(base *types.CollectionResult, scp types.PolicyDocument, targets []types.SCPTarget) ([]ActionAccessDiff, error) {
+	changes := &PolicyChanges{
+		AddSCPAttachments: []types.SCPAttachment{{Policy: scp, Targets: targets}},
+	}
+	modified, err := MergePolicyChanges(base, changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply SCP: %w", err)
+	}
+
+	before, err := graph.Build(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build before graph: %w", err)
+	}
+	after, err := graph.Build(modified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build after graph: %w", err)
+	}
+
+	// Representative query set: the action patterns current grants use, plus
+	// the concrete actions the SCP denies (a grant of s3:* would otherwise
+	// hide a deny of s3:DeleteBucket)
+	actionSet := make(map[string]bool)
+	for _, edge := range before.GetPermissionEdges() {
+		if edge.IsDeny {
+			continue
+		}
+		actionSet[edge.Action] = true
+	}
+	for _, action := range statementActions(scp) {
+		actionSet[action] = true
+	}
+
+	actions := make([]string, 0, len(actionSet))
+	for action := range actionSet {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	var diffs []ActionAccessDiff
+	for _, action := range actions {
+		diff, err := CompareAccess(before, after, "*", action)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare access for %s: %w", action, err)
+		}
+		if len(diff.Granted) == 0 && len(diff.Revoked) == 0 {
+			continue
+		}
+		diffs = append(diffs, ActionAccessDiff{Action: action, Diff: diff})
+	}
+	return diffs, nil
+}
+
+// statementActions flattens the Action fields of a policy document into a
+// string slice (the JSON shape allows a string or a list)
+func statementActions(doc types.PolicyDocument) []string {
+	var actions []string
+	for _, stmt := range doc.Statements {
+		switch v := stmt.Action.(type) {
+		case string:
+			actions = append(actions, v)
+		case []string:
+			actions = append(actions, v...)
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					actions = append(actions, s)
+				}
+			}
+		}
+	}
+	return actions
+}
+
 // Helper functions for deep copying
 
 func deepCopyResult(result *types.CollectionResult) *types.CollectionResult {
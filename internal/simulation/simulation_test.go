@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -71,6 +72,107 @@ func TestLoadFromFile(t *testing.T) {
 	}
 }
 
+// TestLoadGraphFromFile_MultiAccount tests that an org-wide dataset is
+// detected and built into one combined cross-account graph
+func TestLoadGraphFromFile_MultiAccount(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "org-data.json")
+
+	multi := &types.MultiAccountCollectionResult{
+		OrganizationID: "o-123456",
+		CollectedAt:    time.Now(),
+		Accounts: map[string]*types.CollectionResult{
+			"111111111111": {
+				AccountID: "111111111111",
+				Principals: []*types.Principal{
+					{
+						ARN:       "arn:aws:iam::111111111111:user/alice",
+						Type:      types.PrincipalTypeUser,
+						Name:      "alice",
+						AccountID: "111111111111",
+						Policies: []types.PolicyDocument{
+							{
+								Version: "2012-10-17",
+								Statements: []types.Statement{
+									{
+										Effect:   types.EffectAllow,
+										Action:   "s3:GetObject",
+										Resource: "arn:aws:s3:::shared-bucket/*",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"222222222222": {
+				AccountID: "222222222222",
+				Resources: []*types.Resource{
+					{
+						ARN:       "arn:aws:s3:::shared-bucket/*",
+						Type:      types.ResourceTypeS3,
+						Name:      "shared-bucket",
+						AccountID: "222222222222",
+					},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(multi)
+	if err != nil {
+		t.Fatalf("Failed to marshal test data: %v", err)
+	}
+	if err := os.WriteFile(testFile, data, 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	g, err := LoadGraphFromFile(testFile)
+	if err != nil {
+		t.Fatalf("LoadGraphFromFile() error = %v", err)
+	}
+
+	// Principal from one account, resource from another, in the same graph
+	if _, ok := g.GetPrincipal("arn:aws:iam::111111111111:user/alice"); !ok {
+		t.Error("Combined graph missing principal from account 111111111111")
+	}
+	if _, ok := g.GetResource("arn:aws:s3:::shared-bucket/*"); !ok {
+		t.Error("Combined graph missing resource from account 222222222222")
+	}
+	if !g.CanAccess("arn:aws:iam::111111111111:user/alice", "s3:GetObject", "arn:aws:s3:::shared-bucket/*") {
+		t.Error("Cross-account access query should succeed on the combined graph")
+	}
+}
+
+// TestLoadGraphFromFile_SingleAccount tests fallback to the single-account shape
+func TestLoadGraphFromFile_SingleAccount(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "single.json")
+
+	single := &types.CollectionResult{
+		AccountID: "123456789012",
+		Principals: []*types.Principal{
+			{
+				ARN:  "arn:aws:iam::123456789012:user/bob",
+				Type: types.PrincipalTypeUser,
+				Name: "bob",
+			},
+		},
+	}
+	data, _ := json.Marshal(single)
+	if err := os.WriteFile(testFile, data, 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	g, err := LoadGraphFromFile(testFile)
+	if err != nil {
+		t.Fatalf("LoadGraphFromFile() error = %v", err)
+	}
+	if _, ok := g.GetPrincipal("arn:aws:iam::123456789012:user/bob"); !ok {
+		t.Error("Single-account graph missing its principal")
+	}
+}
+
 // TestLoadFromFile_NonExistentFile tests error handling for non-existent files
 func TestLoadFromFile_NonExistentFile(t *testing.T) {
 	_, err := LoadFromFile("/nonexistent/path/file.json")
@@ -525,3 +627,258 @@ func TestCompareAccess_NilGraphs(t *testing.T) {
 		t.Error("CompareAccess() expected error for nil after graph, got nil")
 	}
 }
+
+// TestLoadFromReader tests loading a CollectionResult from a stream, as the
+// CLI does for '--data -'
+func TestLoadFromReader(t *testing.T) {
+	payload := `{
+		"AccountID": "123456789012",
+		"Principals": [
+			{
+				"ARN": "arn:aws:iam::123456789012:user/alice",
+				"Type": "user",
+				"Name": "alice",
+				"AccountID": "123456789012"
+			}
+		]
+	}`
+
+	result, err := LoadFromReader(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("LoadFromReader() error = %v", err)
+	}
+
+	if result.AccountID != "123456789012" {
+		t.Errorf("LoadFromReader() AccountID = %s, want 123456789012", result.AccountID)
+	}
+	if len(result.Principals) != 1 {
+		t.Errorf("LoadFromReader() Principals count = %d, want 1", len(result.Principals))
+	}
+}
+
+// TestLoadFromReader_InvalidJSON tests error handling for a malformed stream
+func TestLoadFromReader_InvalidJSON(t *testing.T) {
+	_, err := LoadFromReader(strings.NewReader("{not json"))
+	if err == nil {
+		t.Error("LoadFromReader() expected error for invalid JSON, got nil")
+	}
+}
+
+// TestMergePolicyChanges_AddSCPAttachments tests that proposed SCPs are
+// appended to the dataset's attachments without mutating the original
+func TestMergePolicyChanges_AddSCPAttachments(t *testing.T) {
+	base := &types.CollectionResult{
+		AccountID: "123456789012",
+	}
+
+	changes := &PolicyChanges{
+		AddSCPAttachments: []types.SCPAttachment{
+			{
+				Policy: types.PolicyDocument{ID: "scp-proposed", Version: "2012-10-17"},
+				Targets: []types.SCPTarget{
+					{Type: types.SCPTargetTypeAccount, ID: "123456789012"},
+				},
+			},
+		},
+	}
+
+	modified, err := MergePolicyChanges(base, changes)
+	if err != nil {
+		t.Fatalf("MergePolicyChanges() error = %v", err)
+	}
+
+	if len(modified.SCPAttachments) != 1 {
+		t.Fatalf("Expected 1 SCP attachment, got %d", len(modified.SCPAttachments))
+	}
+	if len(base.SCPAttachments) != 0 {
+		t.Error("MergePolicyChanges() modified the base result")
+	}
+}
+
+// TestMergePolicyChanges_LegacySCPsPromoted tests that legacy SCPs survive as
+// root attachments when the first attachment is added
+func TestMergePolicyChanges_LegacySCPsPromoted(t *testing.T) {
+	base := &types.CollectionResult{
+		AccountID: "123456789012",
+		SCPs: []types.PolicyDocument{
+			{ID: "scp-legacy", Version: "2012-10-17"},
+		},
+	}
+
+	changes := &PolicyChanges{
+		AddSCPAttachments: []types.SCPAttachment{
+			{Policy: types.PolicyDocument{ID: "scp-proposed", Version: "2012-10-17"}},
+		},
+	}
+
+	modified, err := MergePolicyChanges(base, changes)
+	if err != nil {
+		t.Fatalf("MergePolicyChanges() error = %v", err)
+	}
+
+	if len(modified.SCPAttachments) != 2 {
+		t.Fatalf("Expected legacy + proposed attachments, got %d", len(modified.SCPAttachments))
+	}
+	if modified.SCPAttachments[0].Policy.ID != "scp-legacy" {
+		t.Errorf("Expected the legacy SCP promoted first, got %s", modified.SCPAttachments[0].Policy.ID)
+	}
+}
+
+// TestEvaluateSCPImpact tests the end-to-end "what would this SCP break" flow
+func TestEvaluateSCPImpact(t *testing.T) {
+	base := &types.CollectionResult{
+		AccountID: "123456789012",
+		Principals: []*types.Principal{
+			{
+				ARN:       "arn:aws:iam::123456789012:user/alice",
+				Type:      types.PrincipalTypeUser,
+				Name:      "alice",
+				AccountID: "123456789012",
+				Policies: []types.PolicyDocument{
+					{
+						Version: "2012-10-17",
+						Statements: []types.Statement{
+							{Effect: types.EffectAllow, Action: "s3:*", Resource: "*"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Guardrail: allow everything except s3:DeleteBucket
+	scp := types.PolicyDocument{
+		ID:      "scp-no-delete-bucket",
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{Effect: types.EffectAllow, Action: "*", Resource: "*"},
+			{Effect: types.EffectDeny, Action: "s3:DeleteBucket", Resource: "*"},
+		},
+	}
+	targets := []types.SCPTarget{{Type: types.SCPTargetTypeAccount, ID: "123456789012"}}
+
+	diffs, err := EvaluateSCPImpact(base, scp, targets)
+	if err != nil {
+		t.Fatalf("EvaluateSCPImpact() error = %v", err)
+	}
+
+	if len(diffs) != 1 {
+		t.Fatalf("Expected 1 affected action, got %d: %+v", len(diffs), diffs)
+	}
+	if diffs[0].Action != "s3:DeleteBucket" {
+		t.Errorf("Expected s3:DeleteBucket affected, got %s", diffs[0].Action)
+	}
+	if len(diffs[0].Diff.Revoked) != 1 || diffs[0].Diff.Revoked[0] != "arn:aws:iam::123456789012:user/alice" {
+		t.Errorf("Expected alice to lose access, got %v", diffs[0].Diff.Revoked)
+	}
+}
+
+// TestEvaluateSCPImpact_NoChange tests that an SCP not touching current
+// grants reports no affected actions
+func TestEvaluateSCPImpact_NoChange(t *testing.T) {
+	base := &types.CollectionResult{
+		AccountID: "123456789012",
+		Principals: []*types.Principal{
+			{
+				ARN:       "arn:aws:iam::123456789012:user/alice",
+				Type:      types.PrincipalTypeUser,
+				Name:      "alice",
+				AccountID: "123456789012",
+				Policies: []types.PolicyDocument{
+					{
+						Version: "2012-10-17",
+						Statements: []types.Statement{
+							{Effect: types.EffectAllow, Action: "s3:GetObject", Resource: "*"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	scp := types.PolicyDocument{
+		Version: "2012-10-17",
+		Statements: []types.Statement{
+			{Effect: types.EffectAllow, Action: "*", Resource: "*"},
+			{Effect: types.EffectDeny, Action: "ec2:TerminateInstances", Resource: "*"},
+		},
+	}
+	targets := []types.SCPTarget{{Type: types.SCPTargetTypeAccount, ID: "123456789012"}}
+
+	diffs, err := EvaluateSCPImpact(base, scp, targets)
+	if err != nil {
+		t.Fatalf("EvaluateSCPImpact() error = %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no affected actions, got %+v", diffs)
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	alice := &types.Principal{
+		ARN:       "arn:aws:iam::111122223333:user/alice",
+		Type:      types.PrincipalTypeUser,
+		Name:      "alice",
+		AccountID: "111122223333",
+	}
+	bob := &types.Principal{
+		ARN:       "arn:aws:iam::999988887777:user/bob",
+		Type:      types.PrincipalTypeUser,
+		Name:      "bob",
+		AccountID: "999988887777",
+	}
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::shared-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "shared-bucket",
+	}
+
+	first := &types.CollectionResult{
+		AccountID:  "111122223333",
+		Principals: []*types.Principal{alice},
+		Resources:  []*types.Resource{bucket},
+		SCPAttachments: []types.SCPAttachment{
+			{
+				Policy:  types.PolicyDocument{ID: "scp-1"},
+				Targets: []types.SCPTarget{{Type: types.SCPTargetTypeAccount, ID: "111122223333"}},
+			},
+		},
+	}
+	second := &types.CollectionResult{
+		AccountID:  "999988887777",
+		Principals: []*types.Principal{bob, alice}, // alice duplicated across files
+		Resources:  []*types.Resource{bucket},      // bucket duplicated too
+	}
+
+	merged, err := MergeResults([]*types.CollectionResult{first, second})
+	if err != nil {
+		t.Fatalf("MergeResults() error = %v", err)
+	}
+
+	if len(merged.Principals) != 2 {
+		t.Errorf("MergeResults() principals = %d, want 2 (deduplicated)", len(merged.Principals))
+	}
+	if len(merged.Resources) != 1 {
+		t.Errorf("MergeResults() resources = %d, want 1 (deduplicated)", len(merged.Resources))
+	}
+	if len(merged.SCPAttachments) != 1 {
+		t.Errorf("MergeResults() SCP attachments = %d, want 1", len(merged.SCPAttachments))
+	}
+
+	// Cross-account merges have no single account ID
+	if merged.AccountID != "" {
+		t.Errorf("MergeResults() AccountID = %q, want empty for cross-account merge", merged.AccountID)
+	}
+
+	// The merge deep-copies: mutating the output must not touch the inputs
+	merged.Principals[0].Name = "changed"
+	if alice.Name != "alice" {
+		t.Error("MergeResults() should deep-copy principals")
+	}
+}
+
+func TestMergeResults_Empty(t *testing.T) {
+	if _, err := MergeResults(nil); err == nil {
+		t.Error("MergeResults() should error on empty input")
+	}
+}
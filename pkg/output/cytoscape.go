@@ -0,0 +1,166 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+)
+
+// cytoscapeNode and cytoscapeEdge follow the Cytoscape.js element JSON
+// shape: every element is {"data": {...}} with ids referencing node ids
+type cytoscapeNode struct {
+	Data cytoscapeNodeData `json:"data"`
+}
+
+type cytoscapeNodeData struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Type    string `json:"type"`              // "principal", "resource", or "pattern"
+	Subtype string `json:"subtype,omitempty"` // Principal/resource type (user, role, s3, ...)
+	Account string `json:"account,omitempty"`
+}
+
+type cytoscapeEdge struct {
+	Data cytoscapeEdgeData `json:"data"`
+}
+
+type cytoscapeEdgeData struct {
+	ID          string `json:"id"`
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	Kind        string `json:"kind"` // "permission", "deny", or "trust"
+	Action      string `json:"action,omitempty"`
+	Policy      string `json:"policy,omitempty"`
+	Severity    string `json:"severity"`
+	Conditional bool   `json:"conditional,omitempty"`
+}
+
+type cytoscapeElements struct {
+	Nodes []cytoscapeNode `json:"nodes"`
+	Edges []cytoscapeEdge `json:"edges"`
+}
+
+type cytoscapeDocument struct {
+	Elements cytoscapeElements `json:"elements"`
+}
+
+// PrintCytoscape writes the access graph as Cytoscape.js element JSON for
+// browser-based graph explorers. Edge endpoints that are only policy
+// patterns (e.g. "*" or "arn:aws:s3:::logs-*") get synthetic pattern nodes
+// so every edge reference resolves.
+func PrintCytoscape(g *graph.Graph, w io.Writer) error {
+	doc := cytoscapeDocument{
+		Elements: cytoscapeElements{
+			Nodes: []cytoscapeNode{},
+			Edges: []cytoscapeEdge{},
+		},
+	}
+
+	known := make(map[string]bool)
+	for _, p := range g.GetAllPrincipals() {
+		known[p.ARN] = true
+		doc.Elements.Nodes = append(doc.Elements.Nodes, cytoscapeNode{Data: cytoscapeNodeData{
+			ID:      p.ARN,
+			Label:   p.Name,
+			Type:    "principal",
+			Subtype: string(p.Type),
+			Account: p.AccountID,
+		}})
+	}
+	for _, r := range g.GetAllResources() {
+		known[r.ARN] = true
+		doc.Elements.Nodes = append(doc.Elements.Nodes, cytoscapeNode{Data: cytoscapeNodeData{
+			ID:      r.ARN,
+			Label:   r.Name,
+			Type:    "resource",
+			Subtype: string(r.Type),
+			Account: r.AccountID,
+		}})
+	}
+
+	// addPatternNode registers a synthetic node for edge endpoints that do
+	// not correspond to a collected principal or resource
+	addPatternNode := func(id string) {
+		if known[id] {
+			return
+		}
+		known[id] = true
+		doc.Elements.Nodes = append(doc.Elements.Nodes, cytoscapeNode{Data: cytoscapeNodeData{
+			ID:    id,
+			Label: id,
+			Type:  "pattern",
+		}})
+	}
+
+	for i, edge := range g.GetPermissionEdges() {
+		addPatternNode(edge.PrincipalARN)
+		addPatternNode(edge.ResourceARN)
+
+		kind := "permission"
+		if edge.IsDeny {
+			kind = "deny"
+		}
+		doc.Elements.Edges = append(doc.Elements.Edges, cytoscapeEdge{Data: cytoscapeEdgeData{
+			ID:          fmt.Sprintf("perm-%d", i),
+			Source:      edge.PrincipalARN,
+			Target:      edge.ResourceARN,
+			Kind:        kind,
+			Action:      edge.Action,
+			Policy:      edge.PolicyName,
+			Severity:    actionSeverity(edge.Action),
+			Conditional: edge.Conditional,
+		}})
+	}
+
+	for i, trust := range g.GetTrustEdges() {
+		addPatternNode(trust.PrincipalARN)
+		addPatternNode(trust.RoleARN)
+
+		doc.Elements.Edges = append(doc.Elements.Edges, cytoscapeEdge{Data: cytoscapeEdgeData{
+			ID:       fmt.Sprintf("trust-%d", i),
+			Source:   trust.PrincipalARN,
+			Target:   trust.RoleARN,
+			Kind:     "trust",
+			Action:   "sts:AssumeRole",
+			Severity: "medium",
+		}})
+	}
+
+	// Sort for stable output across runs (map iteration order varies)
+	sort.Slice(doc.Elements.Nodes, func(i, j int) bool {
+		return doc.Elements.Nodes[i].Data.ID < doc.Elements.Nodes[j].Data.ID
+	})
+	sort.Slice(doc.Elements.Edges, func(i, j int) bool {
+		a, b := doc.Elements.Edges[i].Data, doc.Elements.Edges[j].Data
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Action < b.Action
+	})
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cytoscape elements: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// actionSeverity classifies an action for visualization emphasis
+func actionSeverity(action string) string {
+	switch {
+	case action == "*":
+		return "critical"
+	case strings.HasSuffix(action, ":*") || strings.HasPrefix(action, "iam:"):
+		return "high"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestPrintCytoscape(t *testing.T) {
+	g := graph.New()
+
+	g.AddPrincipal(&types.Principal{
+		ARN:       "arn:aws:iam::123456789012:user/alice",
+		Type:      types.PrincipalTypeUser,
+		Name:      "alice",
+		AccountID: "123456789012",
+	})
+	g.AddResource(&types.Resource{
+		ARN:       "arn:aws:s3:::data-bucket",
+		Type:      types.ResourceTypeS3,
+		Name:      "data-bucket",
+		AccountID: "123456789012",
+	})
+	g.AddEdge("arn:aws:iam::123456789012:user/alice", "s3:GetObject", "arn:aws:s3:::data-bucket", false)
+	g.AddTrustRelation("arn:aws:iam::123456789012:role/app", "arn:aws:iam::123456789012:user/alice")
+
+	var buf bytes.Buffer
+	if err := PrintCytoscape(g, &buf); err != nil {
+		t.Fatalf("PrintCytoscape() error = %v", err)
+	}
+
+	var doc cytoscapeDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	// alice, data-bucket, and a synthetic pattern node for the trust role
+	if len(doc.Elements.Nodes) != 3 {
+		t.Errorf("Expected 3 nodes, got %d", len(doc.Elements.Nodes))
+	}
+	if len(doc.Elements.Edges) != 2 {
+		t.Fatalf("Expected 2 edges, got %d", len(doc.Elements.Edges))
+	}
+
+	kinds := make(map[string]cytoscapeEdgeData)
+	for _, e := range doc.Elements.Edges {
+		kinds[e.Data.Kind] = e.Data
+	}
+
+	perm, ok := kinds["permission"]
+	if !ok {
+		t.Fatal("Expected a permission edge")
+	}
+	if perm.Action != "s3:GetObject" {
+		t.Errorf("Expected permission action s3:GetObject, got %s", perm.Action)
+	}
+	if perm.Severity != "info" {
+		t.Errorf("Expected severity info, got %s", perm.Severity)
+	}
+
+	trust, ok := kinds["trust"]
+	if !ok {
+		t.Fatal("Expected a trust edge")
+	}
+	if trust.Target != "arn:aws:iam::123456789012:role/app" {
+		t.Errorf("Expected trust target role/app, got %s", trust.Target)
+	}
+}
+
+func TestActionSeverity(t *testing.T) {
+	cases := map[string]string{
+		"*":            "critical",
+		"s3:*":         "high",
+		"iam:PassRole": "high",
+		"s3:GetObject": "info",
+	}
+	for action, want := range cases {
+		if got := actionSeverity(action); got != want {
+			t.Errorf("actionSeverity(%q) = %q, want %q", action, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// printPathsDOT writes access paths as a GraphViz DOT digraph: principals as
+// ellipse nodes, resources as boxes, edges labeled with the action and
+// granting policy. Edges gated on conditions render dashed so conditional
+// access stands out when the graph is plotted.
+func printPathsDOT(paths []*types.AccessPath) error {
+	var b strings.Builder
+
+	b.WriteString("digraph access_paths {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontsize=10];\n")
+	b.WriteString("  edge [fontsize=9];\n")
+
+	// Collect nodes and edges across paths, deduplicating as the same
+	// principal or hop frequently appears on several paths
+	nodes := make(map[string]string) // id -> node statement
+	edges := make(map[string]bool)   // rendered edge statement
+	var edgeOrder []string
+
+	addPrincipal := func(p *types.Principal) {
+		if p == nil {
+			return
+		}
+		nodes[p.ARN] = fmt.Sprintf("  %s [label=%s, shape=ellipse];\n",
+			dotID(p.ARN), dotString(fmt.Sprintf("%s\n(%s)", p.Name, p.Type)))
+	}
+	addResource := func(r *types.Resource) {
+		if r == nil {
+			return
+		}
+		nodes[r.ARN] = fmt.Sprintf("  %s [label=%s, shape=box];\n",
+			dotID(r.ARN), dotString(fmt.Sprintf("%s\n(%s)", r.Name, r.Type)))
+	}
+
+	for _, path := range paths {
+		addPrincipal(path.From)
+		addResource(path.To)
+
+		for _, hop := range path.Hops {
+			addPrincipal(hop.From)
+
+			var toID string
+			switch v := hop.To.(type) {
+			case *types.Principal:
+				addPrincipal(v)
+				toID = v.ARN
+			case *types.Resource:
+				addResource(v)
+				toID = v.ARN
+			default:
+				continue
+			}
+
+			label := hop.Action
+			if hop.PolicyName != "" {
+				label = fmt.Sprintf("%s\nvia %s", label, hop.PolicyName)
+			}
+			attrs := fmt.Sprintf("label=%s", dotString(label))
+			if len(hop.Conditions) > 0 {
+				attrs += ", style=dashed"
+			}
+
+			edge := fmt.Sprintf("  %s -> %s [%s];\n", dotID(hop.From.ARN), dotID(toID), attrs)
+			if !edges[edge] {
+				edges[edge] = true
+				edgeOrder = append(edgeOrder, edge)
+			}
+		}
+	}
+
+	// Emit nodes in a stable order so output is diffable
+	nodeIDs := make([]string, 0, len(nodes))
+	for id := range nodes {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Strings(nodeIDs)
+	for _, id := range nodeIDs {
+		b.WriteString(nodes[id])
+	}
+	for _, edge := range edgeOrder {
+		b.WriteString(edge)
+	}
+
+	b.WriteString("}\n")
+	fmt.Print(b.String())
+	return nil
+}
+
+// dotID turns an ARN into a quoted DOT node id
+func dotID(arn string) string {
+	return dotString(arn)
+}
+
+// dotString renders a DOT double-quoted string, escaping quotes and turning
+// newlines into DOT's \n literal
+func dotString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
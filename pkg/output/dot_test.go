@@ -0,0 +1,96 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestPrintPaths_DOT(t *testing.T) {
+	// Capture stdout
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	alice := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/alice",
+		Type: types.PrincipalTypeUser,
+		Name: "alice",
+	}
+	opsRole := &types.Principal{
+		ARN:  "arn:aws:iam::123456789012:role/OpsRole",
+		Type: types.PrincipalTypeRole,
+		Name: "OpsRole",
+	}
+	bucket := &types.Resource{
+		ARN:  "arn:aws:s3:::sensitive-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "sensitive-bucket",
+	}
+
+	paths := []*types.AccessPath{
+		{
+			From:   alice,
+			To:     bucket,
+			Action: "s3:GetObject",
+			Hops: []types.AccessHop{
+				{
+					From:       alice,
+					To:         opsRole,
+					Action:     "sts:AssumeRole",
+					PolicyType: types.PolicyTypeTrust,
+					PolicyName: "OpsRole-trust",
+				},
+				{
+					From:       opsRole,
+					To:         bucket,
+					Action:     "s3:GetObject",
+					PolicyType: types.PolicyTypeIdentity,
+					PolicyName: "ops-read",
+					Conditions: []string{"aws:SourceIp must be in 203.0.113.0/24"},
+				},
+			},
+		},
+	}
+
+	err := PrintPaths("dot", alice.ARN, bucket.ARN, "s3:GetObject", paths)
+
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("PrintPaths(dot) failed: %v", err)
+	}
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "digraph access_paths {") {
+		t.Errorf("Expected DOT digraph header, got: %q", got[:40])
+	}
+	if !strings.Contains(got, `"arn:aws:iam::123456789012:user/alice" -> "arn:aws:iam::123456789012:role/OpsRole" [label="sts:AssumeRole\nvia OpsRole-trust"];`) {
+		t.Errorf("Expected assume-role edge with policy label, got:\n%s", got)
+	}
+	// Conditional hop renders dashed
+	if !strings.Contains(got, `style=dashed`) {
+		t.Errorf("Expected conditional edge to be dashed, got:\n%s", got)
+	}
+	// Principals are ellipses, resources are boxes
+	if !strings.Contains(got, `shape=ellipse`) || !strings.Contains(got, `shape=box`) {
+		t.Errorf("Expected principal and resource node shapes, got:\n%s", got)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(got), "}") {
+		t.Errorf("Expected closing brace, got:\n%s", got)
+	}
+}
+
+func TestDotString_Escapes(t *testing.T) {
+	got := dotString("a\"b\nc")
+	if got != `"a\"b\nc"` {
+		t.Errorf("dotString escaped to %s", got)
+	}
+}
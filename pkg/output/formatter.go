@@ -6,24 +6,325 @@ import (
 	"os"
 	"time"
 
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
 	"github.com/pfrederiksen/aws-access-map/internal/query"
 	"github.com/pfrederiksen/aws-access-map/pkg/types"
 )
 
 // PrintWhoCan outputs who-can results in the specified format
-func PrintWhoCan(format string, resource, action string, principals []*types.Principal) error {
+// Optional evalCtx parameter echoes the evaluation context used (backward compatible)
+func PrintWhoCan(format string, resource, action string, principals []*types.Principal, evalCtx ...*conditions.EvaluationContext) error {
+	ctxOut := contextOutputFromArgs(evalCtx)
 	if format == "json" {
-		return printWhoCanJSON(resource, action, principals)
+		return printWhoCanJSON(resource, action, principals, ctxOut)
 	}
-	return printWhoCanText(resource, action, principals)
+	if format == "ndjson" {
+		return printWhoCanNDJSON(resource, action, principals, ctxOut)
+	}
+	return printWhoCanText(resource, action, principals, ctxOut)
+}
+
+// printWhoCanNDJSON streams who-can results as JSON Lines: a metadata line
+// with the envelope fields, then one principal object per line. Each line is
+// written to stdout as it is encoded, so organization-wide result sets never
+// build one giant in-memory array and pipe straight into jq
+func printWhoCanNDJSON(resource, action string, principals []*types.Principal, ctxOut *EvaluationContextOutput) error {
+	encoder := json.NewEncoder(os.Stdout)
+	if err := encoder.Encode(WhoCanMetaOutput{Resource: resource, Action: action, Context: ctxOut, Count: len(principals)}); err != nil {
+		return err
+	}
+	for _, p := range principals {
+		if err := encoder.Encode(PrincipalOutput{
+			ARN:       p.ARN,
+			Type:      string(p.Type),
+			Name:      p.Name,
+			AccountID: p.AccountID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintWhoCanDetailed outputs tri-state who-can results, marking principals
+// whose access hinges on conditions the query context left unspecified as
+// CONDITIONAL alongside the gating conditions
+func PrintWhoCanDetailed(format string, resource, action string, results []query.PrincipalDecision, evalCtx ...*conditions.EvaluationContext) error {
+	ctxOut := contextOutputFromArgs(evalCtx)
+	if format == "json" {
+		return printWhoCanDetailedJSON(resource, action, results, ctxOut)
+	}
+	if format == "ndjson" {
+		return printWhoCanDetailedNDJSON(resource, action, results, ctxOut)
+	}
+	return printWhoCanDetailedText(resource, action, results, ctxOut)
+}
+
+// printWhoCanDetailedNDJSON streams tri-state results in the same JSON Lines
+// shape as printWhoCanNDJSON, with decision/condition/evidence fields on each
+// principal line
+func printWhoCanDetailedNDJSON(resource, action string, results []query.PrincipalDecision, ctxOut *EvaluationContextOutput) error {
+	encoder := json.NewEncoder(os.Stdout)
+	if err := encoder.Encode(WhoCanMetaOutput{Resource: resource, Action: action, Context: ctxOut, Count: len(results)}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := encoder.Encode(PrincipalOutput{
+			ARN:        result.Principal.ARN,
+			Type:       string(result.Principal.Type),
+			Name:       result.Principal.Name,
+			AccountID:  result.Principal.AccountID,
+			Decision:   string(result.Decision),
+			Conditions: result.Conditions,
+			Evidence:   evidenceOutput(result.Evidence),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printWhoCanDetailedJSON outputs tri-state who-can results as JSON
+func printWhoCanDetailedJSON(resource, action string, results []query.PrincipalDecision, ctxOut *EvaluationContextOutput) error {
+	output := WhoCanOutput{
+		Resource:   resource,
+		Action:     action,
+		Context:    ctxOut,
+		Principals: make([]PrincipalOutput, len(results)),
+	}
+
+	for i, result := range results {
+		output.Principals[i] = PrincipalOutput{
+			ARN:        result.Principal.ARN,
+			Type:       string(result.Principal.Type),
+			Name:       result.Principal.Name,
+			AccountID:  result.Principal.AccountID,
+			Decision:   string(result.Decision),
+			Conditions: result.Conditions,
+			Evidence:   evidenceOutput(result.Evidence),
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// evidenceOutput converts graph access evidence into its serializable form
+func evidenceOutput(evidence []graph.AccessEvidence) []EvidenceOutput {
+	if len(evidence) == 0 {
+		return nil
+	}
+	out := make([]EvidenceOutput, len(evidence))
+	for i, e := range evidence {
+		out[i] = EvidenceOutput{
+			Policy:      e.PolicyName,
+			Action:      e.ActionPattern,
+			Resource:    e.ResourcePattern,
+			ViaGroup:    e.ViaGroup,
+			Conditional: e.Conditional,
+		}
+	}
+	return out
+}
+
+// printWhoCanDetailedText outputs tri-state who-can results as human-readable text
+func printWhoCanDetailedText(resource, action string, results []query.PrincipalDecision, ctxOut *EvaluationContextOutput) error {
+	if ctxOut != nil {
+		printContextText(ctxOut)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No principals found with access to this resource.")
+		return nil
+	}
+
+	conditional := 0
+	for _, result := range results {
+		if result.Decision == graph.DecisionConditional {
+			conditional++
+		}
+	}
+
+	fmt.Printf("Found %d principal(s) with access (%d conditional):\n\n", len(results), conditional)
+	for _, result := range results {
+		p := result.Principal
+		if result.Decision == graph.DecisionConditional {
+			fmt.Printf("  %s (%s) [CONDITIONAL]\n", p.Name, p.Type)
+			fmt.Printf("    ARN: %s\n", p.ARN)
+			fmt.Println("    Conditional on:")
+			for _, cond := range result.Conditions {
+				fmt.Printf("      - %s\n", cond)
+			}
+		} else {
+			fmt.Printf("  %s (%s)\n", p.Name, p.Type)
+			fmt.Printf("    ARN: %s\n", p.ARN)
+		}
+		if len(result.Evidence) > 0 {
+			fmt.Println("    Granted by:")
+			for _, ev := range result.Evidence {
+				line := fmt.Sprintf("%s on %s", ev.ActionPattern, ev.ResourcePattern)
+				if ev.PolicyName != "" {
+					line = fmt.Sprintf("%s (%s)", line, ev.PolicyName)
+				}
+				if ev.ViaGroup != "" {
+					line = fmt.Sprintf("%s via group %s", line, ev.ViaGroup)
+				}
+				if ev.Conditional {
+					line += " [conditional]"
+				}
+				fmt.Printf("      - %s\n", line)
+			}
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// PrintWhoCanBulk outputs who-can results for multiple actions, grouped by
+// action in the order the actions were provided
+func PrintWhoCanBulk(format string, resource string, results []query.ActionPrincipals, evalCtx ...*conditions.EvaluationContext) error {
+	ctxOut := contextOutputFromArgs(evalCtx)
+	if format == "json" {
+		return printWhoCanBulkJSON(resource, results, ctxOut)
+	}
+	return printWhoCanBulkText(resource, results, ctxOut)
+}
+
+// printWhoCanBulkJSON outputs grouped who-can results as JSON
+func printWhoCanBulkJSON(resource string, results []query.ActionPrincipals, ctxOut *EvaluationContextOutput) error {
+	output := WhoCanBulkOutput{
+		Resource: resource,
+		Context:  ctxOut,
+		Results:  make([]WhoCanActionOutput, len(results)),
+	}
+
+	for i, result := range results {
+		actionOut := WhoCanActionOutput{
+			Action:     result.Action,
+			Principals: make([]PrincipalOutput, len(result.Principals)),
+		}
+		for j, p := range result.Principals {
+			actionOut.Principals[j] = PrincipalOutput{
+				ARN:       p.ARN,
+				Type:      string(p.Type),
+				Name:      p.Name,
+				AccountID: p.AccountID,
+			}
+		}
+		output.Results[i] = actionOut
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// printWhoCanBulkText outputs grouped who-can results as human-readable text
+func printWhoCanBulkText(resource string, results []query.ActionPrincipals, ctxOut *EvaluationContextOutput) error {
+	if ctxOut != nil {
+		printContextText(ctxOut)
+	}
+
+	fmt.Printf("Audited %d action(s) against %s:\n\n", len(results), resource)
+	for _, result := range results {
+		fmt.Printf("%s: %d principal(s)\n", result.Action, len(result.Principals))
+		for _, p := range result.Principals {
+			fmt.Printf("  %s (%s)\n", p.Name, p.Type)
+			fmt.Printf("    ARN: %s\n", p.ARN)
+		}
+		fmt.Println()
+	}
+
+	return nil
 }
 
 // PrintPaths outputs path results in the specified format
-func PrintPaths(format string, from, to, action string, paths []*types.AccessPath) error {
+// Optional evalCtx parameter echoes the evaluation context used (backward compatible)
+func PrintPaths(format string, from, to, action string, paths []*types.AccessPath, evalCtx ...*conditions.EvaluationContext) error {
+	return PrintPathsWithDataFlows(format, from, to, action, paths, nil, evalCtx...)
+}
+
+// PrintPathsWithDataFlows outputs path results plus onward data flows from the
+// target resource (replication destinations, notification targets)
+func PrintPathsWithDataFlows(format string, from, to, action string, paths []*types.AccessPath, dataFlows []types.DataFlow, evalCtx ...*conditions.EvaluationContext) error {
+	ctxOut := contextOutputFromArgs(evalCtx)
+	flowsOut := make([]DataFlowOutput, len(dataFlows))
+	for i, flow := range dataFlows {
+		flowsOut[i] = DataFlowOutput{
+			Type:   string(flow.Type),
+			Target: flow.TargetARN,
+			Role:   flow.RoleARN,
+		}
+	}
 	if format == "json" {
-		return printPathsJSON(from, to, action, paths)
+		return printPathsJSON(from, to, action, paths, flowsOut, ctxOut)
+	}
+	if format == "dot" {
+		return printPathsDOT(paths)
+	}
+	return printPathsText(from, to, action, paths, flowsOut, ctxOut)
+}
+
+// contextOutputFromArgs converts an optional evaluation context argument to its output form
+func contextOutputFromArgs(evalCtx []*conditions.EvaluationContext) *EvaluationContextOutput {
+	if len(evalCtx) == 0 || evalCtx[0] == nil {
+		return nil
+	}
+	return NewEvaluationContextOutput(evalCtx[0])
+}
+
+// NewEvaluationContextOutput converts an EvaluationContext into its serializable output form
+func NewEvaluationContextOutput(ctx *conditions.EvaluationContext) *EvaluationContextOutput {
+	if ctx == nil {
+		return nil
+	}
+
+	out := &EvaluationContextOutput{
+		SourceIP:         ctx.SourceIP,
+		MFAAuthenticated: ctx.MFAAuthenticated,
+		SecureTransport:  ctx.SecureTransport,
+		PrincipalARN:     ctx.PrincipalARN,
+		PrincipalOrgID:   ctx.PrincipalOrgID,
+		RequestedRegion:  ctx.RequestedRegion,
+	}
+
+	if len(ctx.PrincipalTags) > 0 {
+		out.PrincipalTags = ctx.PrincipalTags
+	}
+	if len(ctx.ResourceTags) > 0 {
+		out.ResourceTags = ctx.ResourceTags
+	}
+	if !ctx.CurrentTime.IsZero() {
+		out.CurrentTime = ctx.CurrentTime.UTC().Format(time.RFC3339)
+	}
+
+	return out
+}
+
+// printContextText prints the evaluation context assumptions in text mode
+func printContextText(ctxOut *EvaluationContextOutput) {
+	fmt.Println("Evaluation context:")
+	if ctxOut.SourceIP != "" {
+		fmt.Printf("  Source IP: %s\n", ctxOut.SourceIP)
+	}
+	fmt.Printf("  MFA authenticated: %t\n", ctxOut.MFAAuthenticated)
+	fmt.Printf("  Secure transport: %t\n", ctxOut.SecureTransport)
+	if ctxOut.PrincipalARN != "" {
+		fmt.Printf("  Principal ARN: %s\n", ctxOut.PrincipalARN)
+	}
+	if ctxOut.PrincipalOrgID != "" {
+		fmt.Printf("  Principal org ID: %s\n", ctxOut.PrincipalOrgID)
+	}
+	if ctxOut.RequestedRegion != "" {
+		fmt.Printf("  Requested region: %s\n", ctxOut.RequestedRegion)
+	}
+	if ctxOut.CurrentTime != "" {
+		fmt.Printf("  Request time: %s\n", ctxOut.CurrentTime)
 	}
-	return printPathsText(from, to, action, paths)
+	fmt.Println()
 }
 
 // PrintReport outputs report results in the specified format
@@ -31,6 +332,9 @@ func PrintReport(format string, accountID string, findings []query.HighRiskFindi
 	if format == "json" {
 		return printReportJSON(accountID, findings)
 	}
+	if format == "markdown" {
+		return printReportMarkdown(accountID, findings)
+	}
 	return printReportText(accountID, findings)
 }
 
@@ -42,11 +346,12 @@ func PrintCollect(format string, result *types.CollectionResult, outputFile stri
 	return printCollectText(result, outputFile)
 }
 
-// printWhoCanJSON outputs who-can results as JSON
-func printWhoCanJSON(resource, action string, principals []*types.Principal) error {
+// buildWhoCanOutput assembles the serializable who-can result struct
+func buildWhoCanOutput(resource, action string, principals []*types.Principal, ctxOut *EvaluationContextOutput) WhoCanOutput {
 	output := WhoCanOutput{
 		Resource:   resource,
 		Action:     action,
+		Context:    ctxOut,
 		Principals: make([]PrincipalOutput, len(principals)),
 	}
 
@@ -59,13 +364,24 @@ func printWhoCanJSON(resource, action string, principals []*types.Principal) err
 		}
 	}
 
+	return output
+}
+
+// printWhoCanJSON outputs who-can results as JSON
+func printWhoCanJSON(resource, action string, principals []*types.Principal, ctxOut *EvaluationContextOutput) error {
+	output := buildWhoCanOutput(resource, action, principals, ctxOut)
+
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
 }
 
 // printWhoCanText outputs who-can results as human-readable text
-func printWhoCanText(resource, action string, principals []*types.Principal) error {
+func printWhoCanText(resource, action string, principals []*types.Principal, ctxOut *EvaluationContextOutput) error {
+	if ctxOut != nil {
+		printContextText(ctxOut)
+	}
+
 	if len(principals) == 0 {
 		fmt.Println("No principals found with access to this resource.")
 		return nil
@@ -80,13 +396,17 @@ func printWhoCanText(resource, action string, principals []*types.Principal) err
 	return nil
 }
 
-// printPathsJSON outputs path results as JSON
-func printPathsJSON(from, to, action string, paths []*types.AccessPath) error {
+// buildPathsOutput assembles the serializable path result struct
+func buildPathsOutput(from, to, action string, paths []*types.AccessPath, flowsOut []DataFlowOutput, ctxOut *EvaluationContextOutput) PathsOutput {
 	output := PathsOutput{
-		From:   from,
-		To:     to,
-		Action: action,
-		Paths:  make([]PathOutput, len(paths)),
+		From:    from,
+		To:      to,
+		Action:  action,
+		Context: ctxOut,
+		Paths:   make([]PathOutput, len(paths)),
+	}
+	if len(flowsOut) > 0 {
+		output.DataFlows = flowsOut
 	}
 
 	for i, path := range paths {
@@ -134,15 +454,27 @@ func printPathsJSON(from, to, action string, paths []*types.AccessPath) error {
 		output.Paths[i] = pathOut
 	}
 
+	return output
+}
+
+// printPathsJSON outputs path results as JSON
+func printPathsJSON(from, to, action string, paths []*types.AccessPath, flowsOut []DataFlowOutput, ctxOut *EvaluationContextOutput) error {
+	output := buildPathsOutput(from, to, action, paths, flowsOut, ctxOut)
+
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
 }
 
 // printPathsText outputs path results as human-readable text
-func printPathsText(from, to, action string, paths []*types.AccessPath) error {
+func printPathsText(from, to, action string, paths []*types.AccessPath, flowsOut []DataFlowOutput, ctxOut *EvaluationContextOutput) error {
+	if ctxOut != nil {
+		printContextText(ctxOut)
+	}
+
 	if len(paths) == 0 {
 		fmt.Println("No access paths found.")
+		printDataFlowsText(to, flowsOut)
 		return nil
 	}
 
@@ -188,11 +520,30 @@ func printPathsText(from, to, action string, paths []*types.AccessPath) error {
 		fmt.Println()
 	}
 
+	printDataFlowsText(to, flowsOut)
+
 	return nil
 }
 
-// printReportJSON outputs report results as JSON
-func printReportJSON(accountID string, findings []query.HighRiskFinding) error {
+// printDataFlowsText prints onward data flows from the target resource
+func printDataFlowsText(to string, flowsOut []DataFlowOutput) {
+	if len(flowsOut) == 0 {
+		return
+	}
+
+	fmt.Printf("Data from %s flows onward to:\n", to)
+	for _, flow := range flowsOut {
+		fmt.Printf("  - %s -> %s", flow.Type, flow.Target)
+		if flow.Role != "" {
+			fmt.Printf(" (via %s)", flow.Role)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// buildReportOutput assembles the serializable report result struct
+func buildReportOutput(accountID string, findings []query.HighRiskFinding) ReportOutput {
 	output := ReportOutput{
 		AccountID:   accountID,
 		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
@@ -229,6 +580,13 @@ func printReportJSON(accountID string, findings []query.HighRiskFinding) error {
 		output.Findings[i] = findingOut
 	}
 
+	return output
+}
+
+// printReportJSON outputs report results as JSON
+func printReportJSON(accountID string, findings []query.HighRiskFinding) error {
+	output := buildReportOutput(accountID, findings)
+
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
@@ -298,7 +656,11 @@ func printCollectJSON(result *types.CollectionResult) error {
 // printCollectText outputs collection summary as human-readable text
 func printCollectText(result *types.CollectionResult, outputFile string) error {
 	fmt.Printf("Collected %d principals\n", len(result.Principals))
-	fmt.Printf("Collected %d resources\n", len(result.Resources))
+	if result.ResourcesSkipped {
+		fmt.Println("Resource collection skipped (--iam-only)")
+	} else {
+		fmt.Printf("Collected %d resources\n", len(result.Resources))
+	}
 
 	if len(result.SCPs) > 0 {
 		fmt.Printf("Collected %d Service Control Policies (SCPs)\n", len(result.SCPs))
@@ -7,6 +7,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
 	"github.com/pfrederiksen/aws-access-map/internal/query"
 	"github.com/pfrederiksen/aws-access-map/pkg/types"
 )
@@ -466,3 +467,144 @@ func TestPrintCollect_Text_NoSCPs(t *testing.T) {
 		t.Error("Expected output to contain '--include-scps flag' hint when SCPs are not collected")
 	}
 }
+
+func TestPrintWhoCan_JSON_EchoContext(t *testing.T) {
+	// Capture stdout
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	evalCtx := conditions.NewDefaultContext()
+	evalCtx.SourceIP = "203.0.113.50"
+	evalCtx.MFAAuthenticated = true
+	evalCtx.PrincipalOrgID = "o-123456"
+
+	err := PrintWhoCan("json", "arn:aws:s3:::bucket/*", "s3:GetObject", []*types.Principal{}, evalCtx)
+	if err != nil {
+		t.Fatalf("PrintWhoCan() error = %v", err)
+	}
+
+	// Restore stdout and read output
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r) // Ignore error in test
+
+	var output WhoCanOutput
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, buf.String())
+	}
+
+	if output.Context == nil {
+		t.Fatal("Expected context to be included in output")
+	}
+
+	if output.Context.SourceIP != "203.0.113.50" {
+		t.Errorf("Expected source IP '203.0.113.50', got '%s'", output.Context.SourceIP)
+	}
+
+	if !output.Context.MFAAuthenticated {
+		t.Error("Expected MFA authenticated to be true")
+	}
+
+	if output.Context.PrincipalOrgID != "o-123456" {
+		t.Errorf("Expected principal org ID 'o-123456', got '%s'", output.Context.PrincipalOrgID)
+	}
+}
+
+func TestPrintWhoCan_JSON_NoContextByDefault(t *testing.T) {
+	// Capture stdout
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := PrintWhoCan("json", "arn:aws:s3:::bucket/*", "s3:GetObject", []*types.Principal{})
+	if err != nil {
+		t.Fatalf("PrintWhoCan() error = %v", err)
+	}
+
+	// Restore stdout and read output
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r) // Ignore error in test
+
+	var output WhoCanOutput
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, buf.String())
+	}
+
+	if output.Context != nil {
+		t.Error("Expected no context in output when none is provided")
+	}
+}
+
+func TestPrintWhoCan_NDJSON(t *testing.T) {
+	// Capture stdout
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	principals := []*types.Principal{
+		{
+			ARN:       "arn:aws:iam::123456789012:user/admin",
+			Type:      types.PrincipalTypeUser,
+			Name:      "admin",
+			AccountID: "123456789012",
+		},
+		{
+			ARN:       "arn:aws:iam::123456789012:role/AppRole",
+			Type:      types.PrincipalTypeRole,
+			Name:      "AppRole",
+			AccountID: "123456789012",
+		},
+	}
+
+	err := PrintWhoCan("ndjson", "arn:aws:s3:::bucket/*", "s3:GetObject", principals)
+	if err != nil {
+		t.Fatalf("PrintWhoCan() error = %v", err)
+	}
+
+	// Restore stdout and read output
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r) // Ignore error in test
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines (meta + 2 principals), got %d:\n%s", len(lines), buf.String())
+	}
+
+	// First line is the envelope metadata
+	var meta WhoCanMetaOutput
+	if err := json.Unmarshal(lines[0], &meta); err != nil {
+		t.Fatalf("Failed to parse metadata line: %v\nLine: %s", err, lines[0])
+	}
+	if meta.Resource != "arn:aws:s3:::bucket/*" {
+		t.Errorf("Expected resource 'arn:aws:s3:::bucket/*', got '%s'", meta.Resource)
+	}
+	if meta.Action != "s3:GetObject" {
+		t.Errorf("Expected action 's3:GetObject', got '%s'", meta.Action)
+	}
+	if meta.Count != 2 {
+		t.Errorf("Expected count 2, got %d", meta.Count)
+	}
+
+	// Remaining lines are one principal each
+	var first PrincipalOutput
+	if err := json.Unmarshal(lines[1], &first); err != nil {
+		t.Fatalf("Failed to parse principal line: %v\nLine: %s", err, lines[1])
+	}
+	if first.Name != "admin" {
+		t.Errorf("Expected first principal name 'admin', got '%s'", first.Name)
+	}
+
+	var second PrincipalOutput
+	if err := json.Unmarshal(lines[2], &second); err != nil {
+		t.Fatalf("Failed to parse principal line: %v\nLine: %s", err, lines[2])
+	}
+	if second.ARN != "arn:aws:iam::123456789012:role/AppRole" {
+		t.Errorf("Expected second principal ARN for AppRole, got '%s'", second.ARN)
+	}
+}
@@ -0,0 +1,137 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+)
+
+// graphJSONNode and graphJSONEdge form a normalized property-graph document
+// for import into graph databases (Neo4j, Neptune). ARNs serve as stable
+// node IDs so repeated exports upsert cleanly.
+type graphJSONNode struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"` // "principal" or "resource"
+	Label   string `json:"label"`
+	Type    string `json:"type"` // Principal/resource type (user, role, s3, ...)
+	Account string `json:"account,omitempty"`
+	Region  string `json:"region,omitempty"`
+}
+
+type graphJSONEdge struct {
+	Type       string                              `json:"type"` // PERMISSION, TRUST, MEMBER_OF, DELIVERS_TO
+	From       string                              `json:"from"`
+	To         string                              `json:"to"`
+	Action     string                              `json:"action,omitempty"`
+	Effect     string                              `json:"effect,omitempty"` // "allow" or "deny" (PERMISSION only)
+	Policy     string                              `json:"policy,omitempty"`
+	Conditions map[string]map[string]interface{} `json:"conditions,omitempty"`
+	Flow       string                              `json:"flow,omitempty"` // replication/notification (DELIVERS_TO only)
+	Role       string                              `json:"role,omitempty"` // Role performing a replication flow
+}
+
+type graphJSONDocument struct {
+	Nodes []graphJSONNode `json:"nodes"`
+	Edges []graphJSONEdge `json:"edges"`
+}
+
+// PrintGraphJSON writes the access graph as a normalized node/edge JSON
+// document for property-graph imports. Unlike the Cytoscape and DOT
+// renderings this targets querying rather than display: edges are typed
+// (PERMISSION, TRUST, MEMBER_OF, DELIVERS_TO) and carry their full
+// condition blocks.
+func PrintGraphJSON(g *graph.Graph, w io.Writer) error {
+	doc := graphJSONDocument{
+		Nodes: []graphJSONNode{},
+		Edges: []graphJSONEdge{},
+	}
+
+	for _, p := range g.GetAllPrincipals() {
+		doc.Nodes = append(doc.Nodes, graphJSONNode{
+			ID:      p.ARN,
+			Kind:    "principal",
+			Label:   p.Name,
+			Type:    string(p.Type),
+			Account: p.AccountID,
+		})
+
+		// Group membership edges (users -> groups)
+		for _, groupARN := range p.GroupMemberships {
+			doc.Edges = append(doc.Edges, graphJSONEdge{
+				Type: "MEMBER_OF",
+				From: p.ARN,
+				To:   groupARN,
+			})
+		}
+	}
+
+	for _, r := range g.GetAllResources() {
+		doc.Nodes = append(doc.Nodes, graphJSONNode{
+			ID:      r.ARN,
+			Kind:    "resource",
+			Label:   r.Name,
+			Type:    string(r.Type),
+			Account: r.AccountID,
+			Region:  r.Region,
+		})
+
+		for _, flow := range g.GetDataFlows(r.ARN) {
+			doc.Edges = append(doc.Edges, graphJSONEdge{
+				Type: "DELIVERS_TO",
+				From: r.ARN,
+				To:   flow.TargetARN,
+				Flow: string(flow.Type),
+				Role: flow.RoleARN,
+			})
+		}
+	}
+
+	for _, edge := range g.GetPermissionEdges() {
+		effect := "allow"
+		if edge.IsDeny {
+			effect = "deny"
+		}
+		doc.Edges = append(doc.Edges, graphJSONEdge{
+			Type:       "PERMISSION",
+			From:       edge.PrincipalARN,
+			To:         edge.ResourceARN,
+			Action:     edge.Action,
+			Effect:     effect,
+			Policy:     edge.PolicyName,
+			Conditions: edge.Conditions,
+		})
+	}
+
+	for _, trust := range g.GetTrustEdges() {
+		doc.Edges = append(doc.Edges, graphJSONEdge{
+			Type:   "TRUST",
+			From:   trust.PrincipalARN,
+			To:     trust.RoleARN,
+			Action: "sts:AssumeRole",
+		})
+	}
+
+	// Stable ordering keeps exports diffable across runs
+	sort.Slice(doc.Nodes, func(i, j int) bool {
+		return doc.Nodes[i].ID < doc.Nodes[j].ID
+	})
+	sort.Slice(doc.Edges, func(i, j int) bool {
+		a, b := doc.Edges[i], doc.Edges[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Action < b.Action
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
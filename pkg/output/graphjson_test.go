@@ -0,0 +1,107 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestPrintGraphJSON(t *testing.T) {
+	g := graph.New()
+
+	g.AddPrincipal(&types.Principal{
+		ARN:              "arn:aws:iam::123456789012:user/alice",
+		Type:             types.PrincipalTypeUser,
+		Name:             "alice",
+		AccountID:        "123456789012",
+		GroupMemberships: []string{"arn:aws:iam::123456789012:group/developers"},
+	})
+	g.AddResource(&types.Resource{
+		ARN:       "arn:aws:s3:::data-bucket",
+		Type:      types.ResourceTypeS3,
+		Name:      "data-bucket",
+		AccountID: "123456789012",
+		Region:    "us-east-1",
+	})
+	g.AddEdgeWithConditions(
+		"arn:aws:iam::123456789012:user/alice", "s3:GetObject", "arn:aws:s3:::data-bucket", false,
+		map[string]map[string]interface{}{
+			"IpAddress": {"aws:SourceIp": "203.0.113.0/24"},
+		},
+		"read-policy", nil, nil,
+	)
+	g.AddTrustRelation("arn:aws:iam::123456789012:role/app", "arn:aws:iam::123456789012:user/alice")
+	g.AddDataFlow("arn:aws:s3:::data-bucket", types.DataFlow{
+		Type:      types.DataFlowReplication,
+		TargetARN: "arn:aws:s3:::backup-bucket",
+		RoleARN:   "arn:aws:iam::123456789012:role/replication",
+	})
+
+	var buf bytes.Buffer
+	if err := PrintGraphJSON(g, &buf); err != nil {
+		t.Fatalf("PrintGraphJSON() error = %v", err)
+	}
+
+	var doc graphJSONDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	if len(doc.Nodes) != 2 {
+		t.Errorf("Expected 2 nodes, got %d", len(doc.Nodes))
+	}
+	for _, n := range doc.Nodes {
+		switch n.ID {
+		case "arn:aws:iam::123456789012:user/alice":
+			if n.Kind != "principal" || n.Type != "user" {
+				t.Errorf("Unexpected principal node: %+v", n)
+			}
+		case "arn:aws:s3:::data-bucket":
+			if n.Kind != "resource" || n.Region != "us-east-1" {
+				t.Errorf("Unexpected resource node: %+v", n)
+			}
+		default:
+			t.Errorf("Unexpected node ID: %s", n.ID)
+		}
+	}
+
+	byType := make(map[string]graphJSONEdge)
+	for _, e := range doc.Edges {
+		byType[e.Type] = e
+	}
+	if len(byType) != 4 {
+		t.Fatalf("Expected 4 edge types, got %d: %v", len(byType), byType)
+	}
+
+	perm := byType["PERMISSION"]
+	if perm.Action != "s3:GetObject" || perm.Effect != "allow" || perm.Policy != "read-policy" {
+		t.Errorf("Unexpected permission edge: %+v", perm)
+	}
+	if _, ok := perm.Conditions["IpAddress"]; !ok {
+		t.Errorf("Expected condition block on permission edge, got %v", perm.Conditions)
+	}
+
+	trust := byType["TRUST"]
+	if trust.From != "arn:aws:iam::123456789012:user/alice" || trust.To != "arn:aws:iam::123456789012:role/app" {
+		t.Errorf("Unexpected trust edge: %+v", trust)
+	}
+	if trust.Action != "sts:AssumeRole" {
+		t.Errorf("Expected trust action sts:AssumeRole, got %s", trust.Action)
+	}
+
+	member := byType["MEMBER_OF"]
+	if member.To != "arn:aws:iam::123456789012:group/developers" {
+		t.Errorf("Unexpected membership edge: %+v", member)
+	}
+
+	flow := byType["DELIVERS_TO"]
+	if flow.To != "arn:aws:s3:::backup-bucket" || flow.Flow != string(types.DataFlowReplication) {
+		t.Errorf("Unexpected data flow edge: %+v", flow)
+	}
+	if flow.Role != "arn:aws:iam::123456789012:role/replication" {
+		t.Errorf("Expected replication role on flow edge, got %s", flow.Role)
+	}
+}
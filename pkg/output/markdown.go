@@ -0,0 +1,147 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+)
+
+// Markdown output is aimed at CI bots posting results as PR comments, so it
+// sticks to GitHub-flavored Markdown: a summary table up front and a
+// collapsible <details> block for anything verbose.
+
+// printReportMarkdown outputs report results as GitHub-flavored Markdown
+func printReportMarkdown(accountID string, findings []query.HighRiskFinding) error {
+	output := buildReportOutput(accountID, findings)
+
+	fmt.Printf("## AWS Access Report — account %s\n\n", output.AccountID)
+
+	if len(output.Findings) == 0 {
+		fmt.Println("✅ No high-risk findings detected.")
+		return nil
+	}
+
+	counts := map[string]int{}
+	for _, f := range output.Findings {
+		counts[f.Severity]++
+	}
+	fmt.Printf("**%d high-risk finding(s)** (%s)\n\n", len(output.Findings), severitySummary(counts))
+
+	fmt.Println("| Severity | Type | Principal | Action |")
+	fmt.Println("| --- | --- | --- | --- |")
+	for _, f := range output.Findings {
+		principal := ""
+		if f.Principal != nil {
+			principal = fmt.Sprintf("`%s`", f.Principal.ARN)
+		}
+		action := ""
+		if f.Action != "" {
+			action = fmt.Sprintf("`%s`", f.Action)
+		}
+		fmt.Printf("| %s | %s | %s | %s |\n",
+			markdownCell(f.Severity), markdownCell(f.Type), markdownCell(principal), markdownCell(action))
+	}
+	fmt.Println()
+
+	fmt.Println("<details>")
+	fmt.Printf("<summary>Finding details (%d)</summary>\n\n", len(output.Findings))
+	for i, f := range output.Findings {
+		fmt.Printf("%d. **[%s] %s**\n", i+1, f.Severity, f.Type)
+		fmt.Printf("   %s\n", f.Description)
+		if f.Resource != nil {
+			fmt.Printf("   Resource: `%s`\n", f.Resource.ARN)
+		}
+	}
+	fmt.Println("\n</details>")
+
+	return nil
+}
+
+// PrintAccessDiffMarkdown outputs a simulation access diff as GitHub-flavored
+// Markdown
+func PrintAccessDiffMarkdown(resource, action string, diff *simulation.AccessDiff) error {
+	fmt.Printf("## Access Diff — `%s` on `%s`\n\n", action, resource)
+
+	if len(diff.Granted) == 0 && len(diff.Revoked) == 0 {
+		fmt.Println("✅ No changes in access.")
+		return nil
+	}
+
+	fmt.Println("| Change | Principals |")
+	fmt.Println("| --- | --- |")
+	fmt.Printf("| ✅ Granted | %d |\n", len(diff.Granted))
+	fmt.Printf("| ❌ Revoked | %d |\n", len(diff.Revoked))
+	fmt.Printf("| ➡️ Unchanged | %d |\n", len(diff.Unchanged))
+	fmt.Println()
+
+	if len(diff.Granted) > 0 {
+		fmt.Println("<details>")
+		fmt.Printf("<summary>New access granted (%d)</summary>\n\n", len(diff.Granted))
+		for _, arn := range diff.Granted {
+			fmt.Printf("- `%s`\n", arn)
+		}
+		fmt.Println("\n</details>")
+	}
+
+	if len(diff.Revoked) > 0 {
+		fmt.Println("<details>")
+		fmt.Printf("<summary>Access revoked (%d)</summary>\n\n", len(diff.Revoked))
+		for _, arn := range diff.Revoked {
+			fmt.Printf("- `%s`\n", arn)
+		}
+		fmt.Println("\n</details>")
+	}
+
+	return nil
+}
+
+// PrintValidationMarkdown outputs simulate-validate issues as GitHub-flavored
+// Markdown. Issues arrive pre-rendered; indented lines are treated as detail
+// lines under the preceding issue.
+func PrintValidationMarkdown(issues []string) error {
+	fmt.Println("## Policy Validation")
+	fmt.Println()
+
+	if len(issues) == 0 {
+		fmt.Println("✅ No security issues detected.")
+		return nil
+	}
+
+	for _, issue := range issues {
+		if strings.HasPrefix(issue, " ") {
+			fmt.Printf("  - %s\n", strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(issue), "-")))
+			continue
+		}
+		fmt.Printf("- %s\n", issue)
+	}
+
+	return nil
+}
+
+// severitySummary renders per-severity counts in severity order, e.g.
+// "2 CRITICAL, 1 MEDIUM"
+func severitySummary(counts map[string]int) string {
+	order := []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+	var parts []string
+	for _, sev := range order {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+			delete(counts, sev)
+		}
+	}
+	// Anything with a non-standard severity goes last, deterministically
+	var rest []string
+	for sev, n := range counts {
+		rest = append(rest, fmt.Sprintf("%d %s", n, sev))
+	}
+	sort.Strings(rest)
+	return strings.Join(append(parts, rest...), ", ")
+}
+
+// markdownCell escapes characters that would break a Markdown table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
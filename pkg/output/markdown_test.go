@@ -0,0 +1,127 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/internal/simulation"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestPrintReport_Markdown(t *testing.T) {
+	// Capture stdout
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	findings := []query.HighRiskFinding{
+		{
+			Type:        "Admin Access",
+			Severity:    "CRITICAL",
+			Description: "User has wildcard permissions",
+			Principal: &types.Principal{
+				ARN:  "arn:aws:iam::123456789012:user/admin",
+				Type: types.PrincipalTypeUser,
+				Name: "admin",
+			},
+			Action: "*",
+		},
+		{
+			Type:        "Wildcard Resource",
+			Severity:    "MEDIUM",
+			Description: "Policy grants s3:* on all buckets",
+		},
+	}
+
+	err := PrintReport("markdown", "123456789012", findings)
+	if err != nil {
+		t.Fatalf("PrintReport() error = %v", err)
+	}
+
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r) // Ignore error in test
+	out := buf.String()
+
+	for _, want := range []string{
+		"## AWS Access Report — account 123456789012",
+		"**2 high-risk finding(s)** (1 CRITICAL, 1 MEDIUM)",
+		"| Severity | Type | Principal | Action |",
+		"| CRITICAL | Admin Access | `arn:aws:iam::123456789012:user/admin` | `*` |",
+		"<details>",
+		"User has wildcard permissions",
+		"</details>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Markdown output missing %q\nOutput: %s", want, out)
+		}
+	}
+}
+
+func TestPrintReport_Markdown_NoFindings(t *testing.T) {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := PrintReport("markdown", "123456789012", nil)
+	if err != nil {
+		t.Fatalf("PrintReport() error = %v", err)
+	}
+
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r) // Ignore error in test
+
+	if !strings.Contains(buf.String(), "No high-risk findings detected") {
+		t.Errorf("Expected no-findings message, got: %s", buf.String())
+	}
+}
+
+func TestPrintAccessDiffMarkdown(t *testing.T) {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	diff := &simulation.AccessDiff{
+		Granted:   []string{"arn:aws:iam::123456789012:user/newbie"},
+		Revoked:   []string{},
+		Unchanged: []string{"arn:aws:iam::123456789012:user/admin"},
+	}
+
+	err := PrintAccessDiffMarkdown("arn:aws:s3:::bucket/*", "s3:GetObject", diff)
+	if err != nil {
+		t.Fatalf("PrintAccessDiffMarkdown() error = %v", err)
+	}
+
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r) // Ignore error in test
+	out := buf.String()
+
+	for _, want := range []string{
+		"## Access Diff — `s3:GetObject` on `arn:aws:s3:::bucket/*`",
+		"| ✅ Granted | 1 |",
+		"<summary>New access granted (1)</summary>",
+		"- `arn:aws:iam::123456789012:user/newbie`",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Markdown diff output missing %q\nOutput: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "Access revoked") {
+		t.Error("Empty revoked list should not produce a details block")
+	}
+}
+
+func TestMarkdownCell_EscapesPipes(t *testing.T) {
+	if got := markdownCell("a|b"); got != "a\\|b" {
+		t.Errorf("markdownCell() = %q, want %q", got, "a\\|b")
+	}
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+)
+
+// PrintPrometheus writes graph-level posture metrics in Prometheus text
+// exposition format, for platform teams that run collection on a schedule and
+// want to dashboard or alert on drift (e.g. a public resource appearing).
+// Findings are passed in so the caller controls which analysis ran.
+func PrintPrometheus(g *graph.Graph, accountID string, findings []query.HighRiskFinding, w io.Writer) error {
+	principalsByType := map[string]int{}
+	for _, p := range g.GetAllPrincipals() {
+		principalsByType[string(p.Type)]++
+	}
+
+	adminPrincipals := 0
+	for _, p := range g.GetAllPrincipals() {
+		if g.CanAccess(p.ARN, "*", "*") {
+			adminPrincipals++
+		}
+	}
+
+	publicResources := 0
+	findingsBySeverity := map[string]int{}
+	for _, f := range findings {
+		findingsBySeverity[strings.ToLower(f.Severity)]++
+		if f.Type == "Public Access" {
+			publicResources++
+		}
+	}
+
+	writeGauge := func(name, help string, samples []promSample) {
+		fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+		for _, s := range samples {
+			fmt.Fprintf(w, "%s{%s} %d\n", name, s.labels, s.value)
+		}
+	}
+
+	// %q matches Prometheus label escaping for backslash, quote, and newline
+	accountLabel := fmt.Sprintf("account=%q", accountID)
+
+	var principalSamples []promSample
+	for _, pType := range sortedKeys(principalsByType) {
+		principalSamples = append(principalSamples, promSample{
+			labels: fmt.Sprintf("%s,type=%q", accountLabel, pType),
+			value:  principalsByType[pType],
+		})
+	}
+	writeGauge("awsaccessmap_principals", "Number of principals in the access graph by type", principalSamples)
+
+	writeGauge("awsaccessmap_resources", "Number of resources in the access graph",
+		[]promSample{{labels: accountLabel, value: len(g.GetAllResources())}})
+
+	writeGauge("awsaccessmap_admin_principals", "Principals with unrestricted admin access (* on *)",
+		[]promSample{{labels: accountLabel, value: adminPrincipals}})
+
+	writeGauge("awsaccessmap_public_resources", "Resources with public/anonymous access findings",
+		[]promSample{{labels: accountLabel, value: publicResources}})
+
+	var findingSamples []promSample
+	for _, severity := range sortedKeys(findingsBySeverity) {
+		findingSamples = append(findingSamples, promSample{
+			labels: fmt.Sprintf("%s,severity=%q", accountLabel, severity),
+			value:  findingsBySeverity[severity],
+		})
+	}
+	if len(findingSamples) == 0 {
+		// Always emit the series so dashboards see an explicit zero
+		findingSamples = []promSample{{labels: fmt.Sprintf("%s,severity=\"none\"", accountLabel), value: 0}}
+	}
+	writeGauge("awsaccessmap_findings", "High-risk findings by severity", findingSamples)
+
+	return nil
+}
+
+type promSample struct {
+	labels string
+	value  int
+}
+
+// sortedKeys returns map keys in sorted order for deterministic output
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
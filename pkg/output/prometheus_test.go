@@ -0,0 +1,70 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pfrederiksen/aws-access-map/internal/graph"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+func TestPrintPrometheus(t *testing.T) {
+	g := graph.New()
+	g.AddPrincipal(&types.Principal{
+		ARN:  "arn:aws:iam::123456789012:user/admin",
+		Type: types.PrincipalTypeUser,
+		Name: "admin",
+	})
+	g.AddPrincipal(&types.Principal{
+		ARN:  "arn:aws:iam::123456789012:role/app",
+		Type: types.PrincipalTypeRole,
+		Name: "app",
+	})
+	g.AddResource(&types.Resource{
+		ARN:  "arn:aws:s3:::data-bucket",
+		Type: types.ResourceTypeS3,
+		Name: "data-bucket",
+	})
+	g.AddEdge("arn:aws:iam::123456789012:user/admin", "*", "*", false)
+
+	findings := []query.HighRiskFinding{
+		{Type: "Admin Access", Severity: "CRITICAL"},
+		{Type: "Public Access", Severity: "HIGH"},
+		{Type: "Public Access", Severity: "CRITICAL"},
+	}
+
+	var buf bytes.Buffer
+	if err := PrintPrometheus(g, "123456789012", findings, &buf); err != nil {
+		t.Fatalf("PrintPrometheus() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE awsaccessmap_principals gauge",
+		`awsaccessmap_principals{account="123456789012",type="role"} 1`,
+		`awsaccessmap_principals{account="123456789012",type="user"} 1`,
+		`awsaccessmap_resources{account="123456789012"} 1`,
+		`awsaccessmap_admin_principals{account="123456789012"} 1`,
+		`awsaccessmap_public_resources{account="123456789012"} 2`,
+		`awsaccessmap_findings{account="123456789012",severity="critical"} 2`,
+		`awsaccessmap_findings{account="123456789012",severity="high"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Prometheus output missing %q\nOutput: %s", want, out)
+		}
+	}
+}
+
+func TestPrintPrometheus_NoFindings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := PrintPrometheus(graph.New(), "123456789012", nil, &buf); err != nil {
+		t.Fatalf("PrintPrometheus() error = %v", err)
+	}
+
+	// The findings series is always emitted so dashboards see an explicit zero
+	if !strings.Contains(buf.String(), `awsaccessmap_findings{account="123456789012",severity="none"} 0`) {
+		t.Errorf("Expected explicit zero findings series, got: %s", buf.String())
+	}
+}
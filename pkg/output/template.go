@@ -0,0 +1,87 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pfrederiksen/aws-access-map/internal/policy/conditions"
+	"github.com/pfrederiksen/aws-access-map/internal/query"
+	"github.com/pfrederiksen/aws-access-map/pkg/types"
+)
+
+// PrintWhoCanTemplate renders who-can results through a user-supplied template file
+func PrintWhoCanTemplate(templateFile string, resource, action string, principals []*types.Principal, evalCtx ...*conditions.EvaluationContext) error {
+	output := buildWhoCanOutput(resource, action, principals, contextOutputFromArgs(evalCtx))
+	return renderTemplate(templateFile, output)
+}
+
+// PrintPathsTemplate renders path results through a user-supplied template file
+func PrintPathsTemplate(templateFile string, from, to, action string, paths []*types.AccessPath, dataFlows []types.DataFlow, evalCtx ...*conditions.EvaluationContext) error {
+	flowsOut := make([]DataFlowOutput, len(dataFlows))
+	for i, flow := range dataFlows {
+		flowsOut[i] = DataFlowOutput{
+			Type:   string(flow.Type),
+			Target: flow.TargetARN,
+			Role:   flow.RoleARN,
+		}
+	}
+	output := buildPathsOutput(from, to, action, paths, flowsOut, contextOutputFromArgs(evalCtx))
+	return renderTemplate(templateFile, output)
+}
+
+// PrintReportTemplate renders report results through a user-supplied template file
+func PrintReportTemplate(templateFile string, accountID string, findings []query.HighRiskFinding) error {
+	output := buildReportOutput(accountID, findings)
+	return renderTemplate(templateFile, output)
+}
+
+// renderTemplate parses the template file and executes it against the output struct
+func renderTemplate(templateFile string, data interface{}) error {
+	tmpl, err := template.New(filepath.Base(templateFile)).Funcs(templateFuncs()).ParseFiles(templateFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", templateFile, err)
+	}
+
+	if err := tmpl.Execute(os.Stdout, data); err != nil {
+		return fmt.Errorf("failed to render template %s: %w", templateFile, err)
+	}
+
+	return nil
+}
+
+// templateFuncs returns the helper functions available to output templates
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"shortARN":      ShortARN,
+		"severityColor": SeverityColor,
+		"upper":         strings.ToUpper,
+		"lower":         strings.ToLower,
+		"join":          strings.Join,
+	}
+}
+
+// ShortARN strips the arn:partition:service:region:account prefix, leaving
+// just the resource part (e.g. "role/AppRole"). Non-ARN inputs pass through.
+func ShortARN(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 || parts[0] != "arn" {
+		return arn
+	}
+	return parts[5]
+}
+
+// SeverityColor wraps a severity string in ANSI color codes (red for
+// CRITICAL, yellow for HIGH/MEDIUM, default otherwise)
+func SeverityColor(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return "\033[31m" + severity + "\033[0m"
+	case "HIGH", "MEDIUM":
+		return "\033[33m" + severity + "\033[0m"
+	default:
+		return severity
+	}
+}
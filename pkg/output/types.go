@@ -4,17 +4,64 @@ import "time"
 
 // WhoCanOutput represents JSON output for who-can command
 type WhoCanOutput struct {
-	Resource   string            `json:"resource"`
+	Resource   string                   `json:"resource"`
+	Action     string                   `json:"action"`
+	Context    *EvaluationContextOutput `json:"context,omitempty"`
+	Principals []PrincipalOutput        `json:"principals"`
+}
+
+// WhoCanMetaOutput is the leading metadata line of ndjson who-can output,
+// carrying the envelope fields before the per-principal lines
+type WhoCanMetaOutput struct {
+	Resource string                   `json:"resource"`
+	Action   string                   `json:"action"`
+	Context  *EvaluationContextOutput `json:"context,omitempty"`
+	Count    int                      `json:"count"`
+}
+
+// WhoCanBulkOutput represents JSON output for who-can with an actions file
+type WhoCanBulkOutput struct {
+	Resource string                   `json:"resource"`
+	Context  *EvaluationContextOutput `json:"context,omitempty"`
+	Results  []WhoCanActionOutput     `json:"results"`
+}
+
+// WhoCanActionOutput groups the principals able to perform one audited action
+type WhoCanActionOutput struct {
 	Action     string            `json:"action"`
 	Principals []PrincipalOutput `json:"principals"`
 }
 
 // PathsOutput represents JSON output for path command
 type PathsOutput struct {
-	From   string       `json:"from"`
-	To     string       `json:"to"`
-	Action string       `json:"action"`
-	Paths  []PathOutput `json:"paths"`
+	From    string                   `json:"from"`
+	To      string                   `json:"to"`
+	Action  string                   `json:"action"`
+	Context *EvaluationContextOutput `json:"context,omitempty"`
+	Paths   []PathOutput             `json:"paths"`
+	DataFlows []DataFlowOutput       `json:"data_flows,omitempty"`
+}
+
+// DataFlowOutput describes where data/events from the target resource flow
+// onward (replication destinations, notification targets)
+type DataFlowOutput struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+	Role   string `json:"role,omitempty"`
+}
+
+// EvaluationContextOutput documents the assumptions used for condition evaluation
+// so a stored result file records exactly what context produced it
+type EvaluationContextOutput struct {
+	SourceIP         string            `json:"source_ip,omitempty"`
+	MFAAuthenticated bool              `json:"mfa_authenticated"`
+	SecureTransport  bool              `json:"secure_transport"`
+	PrincipalARN     string            `json:"principal_arn,omitempty"`
+	PrincipalOrgID   string            `json:"principal_org_id,omitempty"`
+	RequestedRegion  string            `json:"requested_region,omitempty"`
+	PrincipalTags    map[string]string `json:"principal_tags,omitempty"`
+	ResourceTags     map[string]string `json:"resource_tags,omitempty"`
+	CurrentTime      string            `json:"current_time,omitempty"`
 }
 
 // PathOutput represents a single access path
@@ -51,11 +98,25 @@ type FindingOutput struct {
 }
 
 // PrincipalOutput represents a principal in JSON output
+// Decision and Conditions are set only by the detailed (tri-state) who-can path
 type PrincipalOutput struct {
-	ARN       string `json:"arn"`
-	Type      string `json:"type"`
-	Name      string `json:"name"`
-	AccountID string `json:"account_id,omitempty"`
+	ARN        string           `json:"arn"`
+	Type       string           `json:"type"`
+	Name       string           `json:"name"`
+	AccountID  string           `json:"account_id,omitempty"`
+	Decision   string           `json:"decision,omitempty"`
+	Conditions []string         `json:"conditions,omitempty"`
+	Evidence   []EvidenceOutput `json:"evidence,omitempty"`
+}
+
+// EvidenceOutput identifies a policy statement granting a principal's access
+// (present only when who-can ran with --with-evidence)
+type EvidenceOutput struct {
+	Policy      string `json:"policy,omitempty"`
+	Action      string `json:"action"`
+	Resource    string `json:"resource"`
+	ViaGroup    string `json:"via_group,omitempty"`
+	Conditional bool   `json:"conditional,omitempty"`
 }
 
 // ResourceOutput represents a resource in JSON output
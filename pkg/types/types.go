@@ -12,6 +12,7 @@ type Principal struct {
 	TrustPolicy         *PolicyDocument
 	PermissionsBoundary *PolicyDocument
 	GroupMemberships    []string // Group ARNs this principal belongs to (for users)
+	RoleLastUsed        *time.Time // When the role was last assumed (roles only); nil means never used
 }
 
 // PrincipalType represents the type of principal
@@ -23,6 +24,8 @@ const (
 	PrincipalTypeGroup   PrincipalType = "group"
 	PrincipalTypeService PrincipalType = "service"
 	PrincipalTypePublic  PrincipalType = "public"
+	PrincipalTypeSSOUser  PrincipalType = "sso-user"
+	PrincipalTypeSSOGroup PrincipalType = "sso-group"
 )
 
 // Resource represents an AWS resource (S3 bucket, KMS key, etc.)
@@ -33,6 +36,34 @@ type Resource struct {
 	Region        string
 	AccountID     string
 	ResourcePolicy *PolicyDocument
+	DataFlows     []DataFlow // Destinations data/events flow to (replication, notifications)
+	ETag          string     // Cheap list-entry fingerprint (S3: creation date); drives incremental reuse
+
+	// Roles a compute workload runs as (ECS task definitions). TaskRoleARN is
+	// what the application code calls AWS with; ExecutionRoleARN is what the
+	// container agent uses to pull images and fetch secrets
+	TaskRoleARN      string
+	ExecutionRoleARN string
+
+	// BucketARN links an S3 access point to the bucket it fronts; access
+	// point grants ultimately give access to the bucket's objects
+	BucketARN string
+}
+
+// DataFlowType classifies how data leaves a resource beyond direct API access
+type DataFlowType string
+
+const (
+	DataFlowReplication  DataFlowType = "replication"
+	DataFlowNotification DataFlowType = "notification"
+)
+
+// DataFlow records a destination that data or events from a resource flow to,
+// e.g. an S3 replication destination bucket or a notification target
+type DataFlow struct {
+	Type      DataFlowType
+	TargetARN string
+	RoleARN   string // Role performing the transfer (replication only)
 }
 
 // ResourceType represents the type of resource
@@ -48,6 +79,12 @@ const (
 	ResourceTypeAPIGateway    ResourceType = "apigateway"
 	ResourceTypeECR           ResourceType = "ecr"
 	ResourceTypeEventBridge   ResourceType = "eventbridge"
+	ResourceTypeAthena        ResourceType = "athena"
+	ResourceTypeGlue          ResourceType = "glue"
+	ResourceTypeEFS           ResourceType = "efs"
+	ResourceTypeGlacier       ResourceType = "glacier"
+	ResourceTypeECS           ResourceType = "ecs"
+	ResourceTypeS3AccessPoint ResourceType = "s3-accesspoint"
 )
 
 // PolicyDocument represents an AWS IAM policy document
@@ -55,6 +92,29 @@ type PolicyDocument struct {
 	Version    string      `json:"Version"`
 	ID         string      `json:"Id,omitempty"`
 	Statements []Statement `json:"Statement"`
+
+	// Source of the document when it came from an attached managed policy
+	// (empty for inline policies), so attachment can be queried by ARN/name
+	SourcePolicyARN  string `json:"SourcePolicyArn,omitempty"`
+	SourcePolicyName string `json:"SourcePolicyName,omitempty"`
+
+	// Historical versions of a customer-managed policy, populated only when
+	// collection runs with --all-policy-versions
+	PolicyVersions []PolicyVersion `json:"PolicyVersions,omitempty"`
+
+	// Set when collection ran with --redact-policies: the SHA-256 of the
+	// original document JSON, kept so identical policies stay correlatable
+	// after their statements are stripped
+	RedactedSHA256 string `json:"RedactedSha256,omitempty"`
+}
+
+// PolicyVersion is one stored version of a customer-managed policy, collected
+// for forensic/audit analysis of how a policy changed over time
+type PolicyVersion struct {
+	VersionID  string          `json:"VersionId"`
+	IsDefault  bool            `json:"IsDefaultVersion"`
+	CreateDate *time.Time      `json:"CreateDate,omitempty"`
+	Document   *PolicyDocument `json:"Document,omitempty"`
 }
 
 // Statement represents a single statement in a policy document
@@ -130,6 +190,25 @@ const (
 	SCPTargetTypeRoot           SCPTargetType = "ROOT"
 )
 
+// SSOAssignment maps an Identity Center user or group to a permission set
+// provisioned in an account (materialized as an AWSReservedSSO_* role)
+type SSOAssignment struct {
+	PermissionSetARN  string
+	PermissionSetName string
+	AccountID         string
+	PrincipalARN      string        // Synthesized ARN for the SSO user/group principal
+	PrincipalType     PrincipalType // sso-user or sso-group
+	PrincipalName     string        // Display name resolved from the identity store (best effort)
+}
+
+// LakeFormationGrant represents a Lake Formation permission (principal → data
+// resource), granted outside IAM/S3 policies via lakeformation:GrantPermissions
+type LakeFormationGrant struct {
+	PrincipalARN string
+	ResourceARN  string   // Glue catalog/database/table ARN the grant targets
+	Permissions  []string // LF permissions (SELECT, DESCRIBE, ALTER, ALL, ...)
+}
+
 // OUHierarchy represents the organizational unit hierarchy for an account
 type OUHierarchy struct {
 	AccountID string
@@ -142,16 +221,31 @@ type CollectionResult struct {
 	Resources       []*Resource
 	SCPs            []PolicyDocument    // Deprecated: Use SCPAttachments for hierarchy-aware filtering
 	SCPAttachments  []SCPAttachment     // SCPs with target information
+	RCPAttachments  []SCPAttachment     // Resource Control Policies with target information
+	SSOAssignments  []SSOAssignment     // Identity Center assignments for permission-set roles
+	LFGrants        []LakeFormationGrant // Lake Formation permissions on Glue data resources
+	ManagedPolicies []ManagedPolicyInfo // Customer-managed policy inventory (for unused-policy detection)
 	OUHierarchy     *OUHierarchy        // OU membership for the account
 	CollectedAt     time.Time
 	AccountID       string
 	Regions         []string
+	ResourcesSkipped bool // True when resource collection was skipped (--iam-only)
+}
+
+// ManagedPolicyInfo summarizes a customer-managed policy for unused-policy
+// detection. AttachmentCount is AWS's own count across users, groups, and
+// roles, so it also covers principals excluded from collection.
+type ManagedPolicyInfo struct {
+	ARN             string
+	Name            string
+	AttachmentCount int32
 }
 
 // MultiAccountCollectionResult holds collected AWS data from multiple accounts
 type MultiAccountCollectionResult struct {
 	Accounts       map[string]*CollectionResult // AccountID -> CollectionResult
 	SCPAttachments []SCPAttachment              // Organization-wide SCPs
+	RCPAttachments []SCPAttachment              // Organization-wide Resource Control Policies
 	OUHierarchy    map[string]*OUHierarchy      // AccountID -> OU hierarchy
 	CollectedAt    time.Time
 	OrganizationID string